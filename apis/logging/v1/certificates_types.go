@@ -0,0 +1,63 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchCertificatesSpec defines how the certificates of the cluster are issued
+// +k8s:openapi-gen=true
+type ElasticsearchCertificatesSpec struct {
+	// The validity period of the operator's CA. The CA is rotated once it expires within the
+	// lifetime of the certificates or half its own lifetime. Defaults to 5 years
+	//
+	// +optional
+	CALifetime *metav1.Duration `json:"caLifetime,omitempty"`
+
+	// The validity period of the certificates. Defaults to 2 years
+	//
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+
+	// How long before they expire the certificates are renewed. Defaults to 1 hour
+	//
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// Issue the Elasticsearch, Kibana and admin certificates with cert-manager instead of
+	// the operator's own CA
+	//
+	// +nullable
+	// +optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+}
+
+// CertManagerSpec defines the cert-manager issuer of the certificates
+// +k8s:openapi-gen=true
+type CertManagerSpec struct {
+	// The Issuer or ClusterIssuer to issue the certificates with
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerKind is the kind of a cert-manager issuer
+type CertManagerIssuerKind string
+
+const (
+	CertManagerIssuer        CertManagerIssuerKind = "Issuer"
+	CertManagerClusterIssuer CertManagerIssuerKind = "ClusterIssuer"
+)
+
+// CertManagerIssuerReference refers to a cert-manager Issuer in the namespace of the cluster
+// or to a ClusterIssuer
+// +k8s:openapi-gen=true
+type CertManagerIssuerReference struct {
+	// The name of the issuer
+	//
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The kind of the issuer. Defaults to Issuer
+	//
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +optional
+	Kind CertManagerIssuerKind `json:"kind,omitempty"`
+}
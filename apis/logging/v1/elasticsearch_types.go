@@ -80,6 +80,14 @@ type ElasticsearchSpec struct {
 	// +optional
 	Nodes []ElasticsearchNode `json:"nodes"`
 
+	// The maximum number of data nodes restarted at once by rolling restarts and updates. Nodes
+	// are only restarted together when the redundancy policy keeps copies of their shards on the
+	// other nodes. Defaults to 1
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailableNodes int32 `json:"maxUnavailableNodes,omitempty"`
+
 	// Default specification applied to all Elasticsearch nodes
 	//
 	// +optional
@@ -90,6 +98,47 @@ type ElasticsearchSpec struct {
 	// +nullable
 	// +optional
 	IndexManagement *IndexManagementSpec `json:"indexManagement"`
+
+	// Snapshot repository and schedule for the cluster
+	//
+	// +nullable
+	// +optional
+	Snapshot *ElasticsearchSnapshotSpec `json:"snapshot,omitempty"`
+
+	// Snapshot to restore into the cluster
+	//
+	// +nullable
+	// +optional
+	Restore *ElasticsearchRestoreSpec `json:"restore,omitempty"`
+
+	// How the certificates of the cluster are issued when the operator manages them
+	//
+	// +nullable
+	// +optional
+	Certificates *ElasticsearchCertificatesSpec `json:"certificates,omitempty"`
+
+	// The windows in which restarts and updates of nodes start. Operations outside of the
+	// windows are held until the next one, except for restarts required by certificates
+	// which expire before then. Operations start at any time without windows
+	//
+	// +optional
+	MaintenanceWindows []ElasticsearchMaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Roles and role mappings of the security plugin managed by the operator
+	//
+	// +nullable
+	// +optional
+	Security *ElasticsearchSecuritySpec `json:"security,omitempty"`
+
+	// Index templates managed by the operator in addition to the ones it generates
+	//
+	// +optional
+	IndexTemplates []ElasticsearchIndexTemplate `json:"indexTemplates,omitempty"`
+
+	// Ingest pipelines managed by the operator
+	//
+	// +optional
+	IngestPipelines []ElasticsearchIngestPipeline `json:"ingestPipelines,omitempty"`
 }
 
 // ElasticsearchStatus defines the observed state of Elasticsearch
@@ -111,6 +160,26 @@ type ElasticsearchStatus struct {
 	Conditions ClusterConditions `json:"conditions,omitempty"`
 	// +optional
 	IndexManagementStatus *IndexManagementStatus `json:"indexManagement,omitempty"`
+	// +optional
+	Snapshot *ElasticsearchSnapshotStatus `json:"snapshot,omitempty"`
+	// +optional
+	Restore *ElasticsearchRestoreStatus `json:"restore,omitempty"`
+	// +optional
+	Security *ElasticsearchSecurityStatus `json:"security,omitempty"`
+	// +optional
+	IndexTemplates []ElasticsearchIndexTemplateStatus `json:"indexTemplates,omitempty"`
+	// +optional
+	IngestPipelines []ElasticsearchIngestPipelineStatus `json:"ingestPipelines,omitempty"`
+	// The progress of the restart of nodes in progress, which the operator resumes after it restarted
+	//
+	// +nullable
+	// +optional
+	Restart *ElasticsearchRestartStatus `json:"restart,omitempty"`
+	// The plan of the pending changes of the spec while the cluster is in plan mode
+	//
+	// +nullable
+	// +optional
+	Plan *ElasticsearchPlanStatus `json:"plan,omitempty"`
 }
 
 type ClusterHealth struct {
@@ -177,6 +246,13 @@ type ElasticsearchNode struct {
 
 	// The resource requirements for the Elasticsearch proxy
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
+
+	// Custom attributes of the nodes (e.g. box_type: hot) rendered as node.attr.<name>.
+	// Index management policies move indices between nodes by their attributes.
+	// Nodes without an attribute used by another node get the value 'none'
+	//
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // ElasticsearchNodeSpec represents configuration of an individual Elasticsearch node
@@ -205,6 +281,40 @@ type ElasticsearchNodeSpec struct {
 	// +nullable
 	// +optional
 	ProxyResources corev1.ResourceRequirements `json:"proxyResources,omitempty"`
+
+	// Spread the nodes and the copies of each shard across zones
+	//
+	// +nullable
+	// +optional
+	ZoneAwareness *ZoneAwarenessSpec `json:"zoneAwareness,omitempty"`
+}
+
+// ZoneAwarenessSpec defines how the nodes and shards are spread across zones. The zone
+// of each node is exposed to Elasticsearch as the 'zone' node attribute
+type ZoneAwarenessSpec struct {
+	// The label of the Kubernetes nodes holding their zone. Defaults to topology.kubernetes.io/zone
+	//
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// The maximum difference between the number of Elasticsearch nodes with the same roles
+	// in any two zones. Defaults to 1
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// Whether to schedule nodes exceeding the maximum skew. Defaults to ScheduleAnyway
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+
+	// The zones to force awareness for. Replicas of a lost zone are left unassigned
+	// instead of being allocated in the remaining zones
+	//
+	// +optional
+	Zones []string `json:"zones,omitempty"`
 }
 
 type ElasticsearchStorageSpec struct {
@@ -270,13 +380,19 @@ const (
 	ZeroRedundancy RedundancyPolicyType = "ZeroRedundancy"
 )
 
-// +kubebuilder:validation:Enum:=master;client;data
+// +kubebuilder:validation:Enum:=master;client;data;ingest;coordinating
 type ElasticsearchNodeRole string
 
 const (
 	ElasticsearchRoleClient ElasticsearchNodeRole = "client"
 	ElasticsearchRoleData   ElasticsearchNodeRole = "data"
 	ElasticsearchRoleMaster ElasticsearchNodeRole = "master"
+	// ElasticsearchRoleIngest runs ingest pipelines. Once a node has the role, the nodes
+	// without it no longer run pipelines
+	ElasticsearchRoleIngest ElasticsearchNodeRole = "ingest"
+	// ElasticsearchRoleCoordinating only serves HTTP requests. Once a node has the role, the
+	// client services only select the coordinating nodes
+	ElasticsearchRoleCoordinating ElasticsearchNodeRole = "coordinating"
 )
 
 type ShardAllocationState string
@@ -307,6 +423,48 @@ const (
 	PreparationComplete ElasticsearchUpgradePhase = "preparationComplete"
 )
 
+// ElasticsearchRestartStatus records the steps of a restart of nodes as they begin and complete
+type ElasticsearchRestartStatus struct {
+	// Kind of the restart
+	Kind ElasticsearchRestartKind `json:"kind"`
+
+	// Names of the restarted nodes
+	Nodes []string `json:"nodes"`
+
+	// The step which began last. It may have been interrupted, the steps are repeated until
+	// they complete
+	Step ElasticsearchRestartStep `json:"step"`
+
+	// The step which completed last
+	//
+	// +optional
+	CompletedStep ElasticsearchRestartStep `json:"completedStep,omitempty"`
+
+	// The time the restart began
+	StartTime metav1.Time `json:"startTime"`
+}
+
+type ElasticsearchRestartKind string
+
+const (
+	FullClusterRestart     ElasticsearchRestartKind = "FullClusterRestart"
+	FullClusterCertRestart ElasticsearchRestartKind = "FullClusterCertRestart"
+	FullClusterUpdate      ElasticsearchRestartKind = "FullClusterUpdate"
+	NodeRestart            ElasticsearchRestartKind = "NodeRestart"
+	NodeCertRestart        ElasticsearchRestartKind = "NodeCertRestart"
+	NodeUpdate             ElasticsearchRestartKind = "NodeUpdate"
+)
+
+type ElasticsearchRestartStep string
+
+const (
+	RestartStepPrecheck ElasticsearchRestartStep = "Precheck"
+	RestartStepPrep     ElasticsearchRestartStep = "Prep"
+	RestartStepMain     ElasticsearchRestartStep = "Main"
+	RestartStepPost     ElasticsearchRestartStep = "Post"
+	RestartStepRecovery ElasticsearchRestartStep = "Recovery"
+)
+
 // Managed means that the operator is actively managing its resources and trying to keep the component active.
 // It will only upgrade the component if it is safe to do so
 // Unmanaged means that the operator will not take any action related to the component
@@ -327,12 +485,16 @@ const (
 	ScalingUp                ClusterConditionType = "ScalingUp"
 	ScalingDown              ClusterConditionType = "ScalingDown"
 	Restarting               ClusterConditionType = "Restarting"
+	ReloadingCertificates    ClusterConditionType = "ReloadingCertificates"
 	Recovering               ClusterConditionType = "Recovering"
 	UpdatingESSettings       ClusterConditionType = "UpdatingESSettings"
 	InvalidMasters           ClusterConditionType = "InvalidMasters"
 	InvalidData              ClusterConditionType = "InvalidData"
 	InvalidRedundancy        ClusterConditionType = "InvalidRedundancy"
 	InvalidUUID              ClusterConditionType = "InvalidUUID"
+	InvalidRoles             ClusterConditionType = "InvalidRoles"
+	InvalidNodeAttributes    ClusterConditionType = "InvalidNodeAttributes"
+	InvalidCertificates      ClusterConditionType = "InvalidCertificates"
 	ESContainerWaiting       ClusterConditionType = "ElasticsearchContainerWaiting"
 	ESContainerTerminated    ClusterConditionType = "ElasticsearchContainerTerminated"
 	ProxyContainerWaiting    ClusterConditionType = "ProxyContainerWaiting"
@@ -344,4 +506,8 @@ const (
 	StorageClassName         ClusterConditionType = "StorageClassNameChangeIgnored"
 	StorageSize              ClusterConditionType = "StorageSizeChangeIgnored"
 	StorageStructure         ClusterConditionType = "StorageStructureChangeIgnored"
+	StorageResizing          ClusterConditionType = "StorageResizing"
+	Restoring                ClusterConditionType = "Restoring"
+	RestoreFailed            ClusterConditionType = "RestoreFailed"
+	MaintenancePending       ClusterConditionType = "MaintenancePending"
 )
@@ -41,6 +41,10 @@ type IndexManagementPhasesSpec struct {
 	// +nullable
 	Hot *IndexManagementHotPhaseSpec `json:"hot,omitempty"`
 	// +nullable
+	Warm *IndexManagementWarmPhaseSpec `json:"warm,omitempty"`
+	// +nullable
+	Cold *IndexManagementColdPhaseSpec `json:"cold,omitempty"`
+	// +nullable
 	Delete *IndexManagementDeletePhaseSpec `json:"delete,omitempty"`
 }
 
@@ -51,6 +55,96 @@ type IndexManagementDeletePhaseSpec struct {
 	MinAge TimeUnit `json:"minAge"`
 }
 
+// +k8s:openapi-gen=true
+type IndexManagementWarmPhaseSpec struct {
+	// The minimum age of an index before it should be moved to the warm phase (e.g. 2d)
+	//
+	MinAge TimeUnit `json:"minAge"`
+
+	// +optional
+	Actions IndexManagementPhaseActionsSpec `json:"actions"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementColdPhaseSpec struct {
+	// The minimum age of an index before it should be moved to the cold phase (e.g. 5d)
+	//
+	MinAge TimeUnit `json:"minAge"`
+
+	// +optional
+	Actions IndexManagementPhaseActionsSpec `json:"actions"`
+}
+
+// IndexManagementPhaseActionsSpec are the actions applied to an index
+// when it enters the warm or cold phase
+// +k8s:openapi-gen=true
+type IndexManagementPhaseActionsSpec struct {
+	// Merge the segments of each shard of the index
+	//
+	// +nullable
+	// +optional
+	ForceMerge *IndexManagementForceMergeActionSpec `json:"forceMerge,omitempty"`
+
+	// Shrink the index into a new index with fewer primary shards. The shrunken
+	// index is named 'shrink-<index>', keeps the aliases of the original index
+	// and its age is counted from the time of the shrink.
+	//
+	// +nullable
+	// +optional
+	Shrink *IndexManagementShrinkActionSpec `json:"shrink,omitempty"`
+
+	// Block write operations to the index
+	//
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Change the number of replicas of the index
+	//
+	// +nullable
+	// +optional
+	Replicas *IndexManagementReplicasActionSpec `json:"replicas,omitempty"`
+
+	// Move the index to the nodes with the given attributes
+	//
+	// +nullable
+	// +optional
+	Allocation *IndexManagementAllocationActionSpec `json:"allocation,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementForceMergeActionSpec struct {
+	// The number of segments to merge each shard to (e.g. 1)
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxNumSegments int32 `json:"maxNumSegments"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementShrinkActionSpec struct {
+	// The number of primary shards of the shrunken index. It must be a factor
+	// of the number of primary shards of the original index
+	//
+	// +kubebuilder:validation:Minimum=1
+	NumberOfShards int32 `json:"numberOfShards"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementReplicasActionSpec struct {
+	// The number of replicas of the index
+	//
+	// +kubebuilder:validation:Minimum=0
+	NumberOfReplicas int32 `json:"numberOfReplicas"`
+}
+
+// +k8s:openapi-gen=true
+type IndexManagementAllocationActionSpec struct {
+	// The node attributes an index requires (e.g. box_type: warm), set as
+	// index.routing.allocation.require.<name>
+	//
+	// +kubebuilder:validation:MinProperties=1
+	Require map[string]string `json:"require"`
+}
+
 // +k8s:openapi-gen=true
 type IndexManagementHotPhaseSpec struct {
 	// +optional
@@ -62,12 +156,34 @@ type IndexManagementActionsSpec struct {
 	// +nullable
 	// +optional
 	Rollover *IndexManagementActionSpec `json:"rollover"`
+
+	// Create the indices on the nodes with the given attributes
+	//
+	// +nullable
+	// +optional
+	Allocation *IndexManagementAllocationActionSpec `json:"allocation,omitempty"`
 }
 
 // +k8s:openapi-gen=true
 type IndexManagementActionSpec struct {
-	// The maximum age of an index before it should be rolled over (e.g. 7d)
-	MaxAge TimeUnit `json:"maxAge"`
+	// The maximum age of an index before it should be rolled over (e.g. 7d).
+	// At least one of maxAge, maxSize or maxDocs is required
+	//
+	// +optional
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+
+	// The maximum size of the primary shards of an index before it should be rolled over (e.g. 50gb).
+	// Defaults to 40gb per primary shard
+	//
+	// +optional
+	MaxSize string `json:"maxSize,omitempty"`
+
+	// The maximum number of documents in an index before it should be rolled over.
+	// Defaults to 40960000 per primary shard
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxDocs *int32 `json:"maxDocs,omitempty"`
 }
 
 // IndexManagementPolicyMappingSpec maps a management policy to an index
@@ -84,6 +200,10 @@ type IndexManagementPolicyMappingSpec struct {
 
 	// Aliases to apply to a template
 	Aliases []string `json:"aliases,omitempty"`
+
+	// The ingest pipeline new indices of the mapping run documents through by default
+	// +optional
+	DefaultPipeline string `json:"defaultPipeline,omitempty"`
 }
 
 type PolicyMap map[string]IndexManagementPolicySpec
@@ -155,10 +275,51 @@ type IndexManagementMappingStatus struct {
 	// Reasons for the state of the corresponding mapping for this status
 	Conditions []IndexManagementMappingCondition `json:"conditions,omitempty"`
 
+	// The outcome of the last finished index management job of the mapping
+	//
+	// +nullable
+	// +optional
+	LastRun *IndexManagementRunStatus `json:"lastRun,omitempty"`
+
 	// LastUpdated represents the last time that the status was updated.
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
 }
 
+// IndexManagementRunStatus is the outcome of an index management job
+type IndexManagementRunStatus struct {
+	// Name of the job
+	JobName string `json:"jobName"`
+
+	// The time the job finished
+	Time metav1.Time `json:"time"`
+
+	// Outcome of the job
+	Outcome IndexManagementRunOutcome `json:"outcome"`
+
+	// The write indices created by the rollover
+	//
+	// +optional
+	RolledOverIndices []string `json:"rolledOverIndices,omitempty"`
+
+	// The number of indices deleted
+	DeletedIndices int32 `json:"deletedIndices"`
+
+	// The number of jobs which failed since the last successful job
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+
+	// The errors of a failed job
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type IndexManagementRunOutcome string
+
+const (
+	IndexManagementRunSucceeded IndexManagementRunOutcome = "Succeeded"
+	IndexManagementRunFailed    IndexManagementRunOutcome = "Failed"
+)
+
 func NewIndexManagementMappingStatus(name string) *IndexManagementMappingStatus {
 	return &IndexManagementMappingStatus{
 		Name:        name,
@@ -206,6 +367,8 @@ type IndexManagementMappingConditionType string
 const (
 	IndexManagementMappingConditionTypeName      IndexManagementMappingConditionType = "Name"
 	IndexManagementMappingConditionTypePolicyRef IndexManagementMappingConditionType = "PolicyRef"
+	// IndexManagementMappingConditionTypeRunsFailing is True when the index management jobs keep failing
+	IndexManagementMappingConditionTypeRunsFailing IndexManagementMappingConditionType = "RunsFailing"
 )
 
 type IndexManagementMappingConditionReason string
@@ -213,6 +376,7 @@ type IndexManagementMappingConditionReason string
 const (
 	IndexManagementMappingReasonMissing   IndexManagementMappingConditionReason = "Missing"
 	IndexManagementMappingReasonNonUnique IndexManagementMappingConditionReason = "NonUnique"
+	IndexManagementMappingReasonJobFailed IndexManagementMappingConditionReason = "JobFailed"
 )
 
 type IndexManagementPolicyStatus struct {
@@ -231,6 +395,9 @@ type IndexManagementPolicyStatus struct {
 	// Reasons for the state of the corresponding policy for this status
 	Conditions []IndexManagementPolicyCondition `json:"conditions,omitempty"`
 
+	// Progress of the indices managed by the policy through its phases
+	Phases []IndexManagementPhaseStatus `json:"phases,omitempty"`
+
 	// LastUpdated represents the last time that the status was updated.
 	LastUpdated metav1.Time `json:"lastUpdated,omitempty"`
 }
@@ -253,6 +420,27 @@ func (status *IndexManagementPolicyStatus) AddPolicyCondition(conditionType Inde
 	})
 }
 
+// IndexManagementPhaseStatus is the number of indices currently in a phase of a policy
+type IndexManagementPhaseStatus struct {
+	// Name of the phase
+	Name IndexManagementPhaseName `json:"name"`
+
+	// The minimum age of an index before it enters the phase
+	MinAge TimeUnit `json:"minAge,omitempty"`
+
+	// The number of indices of all mappings of the policy that are in the phase
+	Indices int32 `json:"indices"`
+}
+
+type IndexManagementPhaseName string
+
+const (
+	IndexManagementPhaseHot    IndexManagementPhaseName = "hot"
+	IndexManagementPhaseWarm   IndexManagementPhaseName = "warm"
+	IndexManagementPhaseCold   IndexManagementPhaseName = "cold"
+	IndexManagementPhaseDelete IndexManagementPhaseName = "delete"
+)
+
 type IndexManagementPolicyState string
 
 const (
@@ -283,12 +471,15 @@ const (
 	IndexManagementPolicyConditionTypeName         IndexManagementPolicyConditionType = "Name"
 	IndexManagementPolicyConditionTypePollInterval IndexManagementPolicyConditionType = "PollInterval"
 	IndexManagementPolicyConditionTypeTimeUnit     IndexManagementPolicyConditionType = "TimeUnit"
+	IndexManagementPolicyConditionTypePhases       IndexManagementPolicyConditionType = "Phases"
+	IndexManagementPolicyConditionTypeActions      IndexManagementPolicyConditionType = "Actions"
 )
 
 type IndexManagementPolicyConditionReason string
 
 const (
-	IndexManagementPolicyReasonMalformed IndexManagementPolicyConditionReason = "MalFormed"
-	IndexManagementPolicyReasonMissing   IndexManagementPolicyConditionReason = "Missing"
-	IndexManagementPolicyReasonNonUnique IndexManagementPolicyConditionReason = "NonUnique"
+	IndexManagementPolicyReasonMalformed  IndexManagementPolicyConditionReason = "MalFormed"
+	IndexManagementPolicyReasonMissing    IndexManagementPolicyConditionReason = "Missing"
+	IndexManagementPolicyReasonNonUnique  IndexManagementPolicyConditionReason = "NonUnique"
+	IndexManagementPolicyReasonOutOfOrder IndexManagementPolicyConditionReason = "OutOfOrder"
 )
@@ -0,0 +1,76 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ElasticsearchIndexTemplate defines an index template managed by the operator. The template
+// is written again whenever it drifts from the spec and deleted once removed from the spec
+// +k8s:openapi-gen=true
+type ElasticsearchIndexTemplate struct {
+	// The name of the template. Names of templates generated by the operator can not be used
+	//
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// The patterns of the names of the indices the template applies to
+	//
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// The order in which templates matching the same index are merged. Templates with a higher
+	// order override the ones with a lower order, including the ones of the operator which
+	// have the order 0
+	//
+	// +optional
+	Order int32 `json:"order,omitempty"`
+
+	// The settings of the indices, e.g. {"index": {"refresh_interval": "30s"}}
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Settings *runtime.RawExtension `json:"settings,omitempty"`
+
+	// The mappings of the indices in the format of the running version of Elasticsearch,
+	// e.g. {"_doc": {"properties": {"team": {"type": "keyword"}}}}
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Mappings *runtime.RawExtension `json:"mappings,omitempty"`
+}
+
+// ElasticsearchIndexTemplateStatus defines the observed state of a managed index template
+// +k8s:openapi-gen=true
+type ElasticsearchIndexTemplateStatus struct {
+	// The name of the template
+	Name string `json:"name"`
+
+	// Whether the template matches the spec
+	State ElasticsearchIndexTemplateState `json:"state"`
+
+	// The reason the template does not match the spec
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time the template was last written to the cluster, on creation or to correct a drift.
+	// It is only set once the operator wrote the template, the templates of the same name it did
+	// not write are neither overwritten nor deleted
+	//
+	// +nullable
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// ElasticsearchIndexTemplateState is the state of a managed index template
+type ElasticsearchIndexTemplateState string
+
+const (
+	// IndexTemplateSynced means the template in the cluster matches the spec
+	IndexTemplateSynced ElasticsearchIndexTemplateState = "Synced"
+	// IndexTemplateFailed means the template could not be written to or deleted from the cluster
+	IndexTemplateFailed ElasticsearchIndexTemplateState = "Failed"
+)
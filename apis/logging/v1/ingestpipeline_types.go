@@ -0,0 +1,71 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ElasticsearchIngestPipeline defines an ingest pipeline managed by the operator. The pipeline
+// is written again whenever it drifts from the spec and deleted once removed from the spec
+// +k8s:openapi-gen=true
+type ElasticsearchIngestPipeline struct {
+	// The id of the pipeline, used to bind it to the indices of a policy mapping
+	//
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// The description of the pipeline
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The processors run in order on every document, e.g. {"lowercase": {"field": "level"}}
+	//
+	// +kubebuilder:validation:MinItems=1
+	Processors []ElasticsearchIngestProcessor `json:"processors"`
+
+	// The processors run when a processor fails without handling the failure
+	//
+	// +optional
+	OnFailure []ElasticsearchIngestProcessor `json:"onFailure,omitempty"`
+}
+
+// ElasticsearchIngestProcessor is a processor of an ingest pipeline, passed as is
+// +kubebuilder:validation:Type=object
+// +kubebuilder:validation:XPreserveUnknownFields
+type ElasticsearchIngestProcessor struct {
+	runtime.RawExtension `json:",inline"`
+}
+
+// ElasticsearchIngestPipelineStatus defines the observed state of a managed ingest pipeline
+// +k8s:openapi-gen=true
+type ElasticsearchIngestPipelineStatus struct {
+	// The id of the pipeline
+	Name string `json:"name"`
+
+	// Whether the pipeline matches the spec
+	State ElasticsearchIngestPipelineState `json:"state"`
+
+	// The reason the pipeline does not match the spec
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time the pipeline was last written to the cluster, on creation or to correct a drift.
+	// It is only set once the operator wrote the pipeline, the pipelines of the same name it did
+	// not write are neither overwritten nor deleted
+	//
+	// +nullable
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// ElasticsearchIngestPipelineState is the state of a managed ingest pipeline
+type ElasticsearchIngestPipelineState string
+
+const (
+	// IngestPipelineSynced means the pipeline in the cluster matches the spec
+	IngestPipelineSynced ElasticsearchIngestPipelineState = "Synced"
+	// IngestPipelineFailed means the pipeline could not be written to or deleted from the cluster
+	IngestPipelineFailed ElasticsearchIngestPipelineState = "Failed"
+)
@@ -0,0 +1,32 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchMaintenanceWindow is a recurring time range in which the operator starts
+// disruptive operations, e.g. restarts and updates of nodes
+// +k8s:openapi-gen=true
+type ElasticsearchMaintenanceWindow struct {
+	// The days of the week the window starts on. Defaults to every day
+	//
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+
+	// The time of the day the window starts at, as HH:MM
+	//
+	// +kubebuilder:validation:Pattern:="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	StartTime string `json:"startTime"`
+
+	// How long the window lasts, at most a week
+	Duration metav1.Duration `json:"duration"`
+
+	// The IANA time zone of the start time, e.g. Europe/Berlin. Defaults to UTC
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindowDay is a day of the week
+// +kubebuilder:validation:Enum:=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWindowDay string
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchPlanStatus is the plan of the changes the operator would make to reconcile the
+// spec. It is computed instead of reconciling the cluster while the cluster is in plan mode
+type ElasticsearchPlanStatus struct {
+	// The generation of the spec the plan was computed for
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// The time the plan was computed
+	Time metav1.Time `json:"time"`
+
+	// The most disruptive impact of the planned changes on the cluster
+	Impact ElasticsearchPlanImpact `json:"impact"`
+
+	// A human-readable summary of the plan
+	Summary string `json:"summary"`
+
+	// The changes the operator would make, in the order it would make them
+	//
+	// +nullable
+	// +optional
+	Changes []ElasticsearchPlannedChange `json:"changes,omitempty"`
+}
+
+// ElasticsearchPlannedChange is a change of the plan
+type ElasticsearchPlannedChange struct {
+	// The action the operator would take
+	Action ElasticsearchPlanAction `json:"action"`
+
+	// The name of the node the change applies to, if any
+	//
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// A human-readable description of the change
+	Description string `json:"description"`
+}
+
+// ElasticsearchPlanImpact is the disruption caused by the changes of a plan
+type ElasticsearchPlanImpact string
+
+const (
+	PlanImpactNone               ElasticsearchPlanImpact = "None"
+	PlanImpactNoRestart          ElasticsearchPlanImpact = "NoRestart"
+	PlanImpactRollingRestart     ElasticsearchPlanImpact = "RollingRestart"
+	PlanImpactFullClusterRestart ElasticsearchPlanImpact = "FullClusterRestart"
+)
+
+// ElasticsearchPlanAction is an action of a planned change
+type ElasticsearchPlanAction string
+
+const (
+	PlanActionCreateNode          ElasticsearchPlanAction = "CreateNode"
+	PlanActionDeleteNode          ElasticsearchPlanAction = "DeleteNode"
+	PlanActionScaleNode           ElasticsearchPlanAction = "ScaleNode"
+	PlanActionUpdateNode          ElasticsearchPlanAction = "UpdateNode"
+	PlanActionRestartNode         ElasticsearchPlanAction = "RestartNode"
+	PlanActionFullClusterRestart  ElasticsearchPlanAction = "FullClusterRestart"
+	PlanActionResumeRestart       ElasticsearchPlanAction = "ResumeRestart"
+	PlanActionReloadCertificates  ElasticsearchPlanAction = "ReloadCertificates"
+	PlanActionExpandVolume        ElasticsearchPlanAction = "ExpandVolume"
+	PlanActionUpdateConfiguration ElasticsearchPlanAction = "UpdateConfiguration"
+	PlanActionIgnore              ElasticsearchPlanAction = "Ignore"
+)
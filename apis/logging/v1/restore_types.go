@@ -0,0 +1,91 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestoredIndexPrefix prefixes the names of restored indices renamed to avoid a conflict
+const RestoredIndexPrefix = "restored-"
+
+// ElasticsearchRestoreSpec defines a snapshot to restore into the cluster. A snapshot is
+// restored once; change the snapshot or repository to run another restore
+// +k8s:openapi-gen=true
+type ElasticsearchRestoreSpec struct {
+	// The repository of the snapshot. Defaults to the snapshot repository of the cluster
+	//
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// The name of the snapshot to restore
+	//
+	// +kubebuilder:validation:MinLength=1
+	Snapshot string `json:"snapshot"`
+
+	// Index patterns to restore. Defaults to all indices not starting with a '.'
+	//
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// How to handle restored indices that already exist in the cluster. Rename restores them
+	// with the 'restored-' prefix. Close replaces the existing indices, which drops the documents
+	// written to them since the snapshot, and is refused for the write indices of an alias.
+	// Defaults to Rename
+	//
+	// +optional
+	ConflictPolicy RestoreConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// RestoreConflictPolicy is how to handle restored indices that already exist
+//
+// +kubebuilder:validation:Enum=Close;Rename
+type RestoreConflictPolicy string
+
+const (
+	RestoreConflictPolicyClose  RestoreConflictPolicy = "Close"
+	RestoreConflictPolicyRename RestoreConflictPolicy = "Rename"
+)
+
+// RestoreState is the progress of a restore
+type RestoreState string
+
+const (
+	RestoreStatePending    RestoreState = "Pending"
+	RestoreStateInProgress RestoreState = "InProgress"
+	RestoreStateCompleted  RestoreState = "Completed"
+	RestoreStateFailed     RestoreState = "Failed"
+)
+
+// ElasticsearchRestoreStatus defines the observed state of the restore
+// +k8s:openapi-gen=true
+type ElasticsearchRestoreStatus struct {
+	// The repository of the restored snapshot
+	Repository string `json:"repository"`
+
+	// The name of the restored snapshot
+	Snapshot string `json:"snapshot"`
+
+	// The progress of the restore
+	State RestoreState `json:"state"`
+
+	// The indices restored into the cluster, after renaming
+	//
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// What the restore is waiting for or why it failed
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time the restore was started
+	//
+	// +nullable
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// The time the restored indices finished recovering
+	//
+	// +nullable
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchSecuritySpec defines the roles and role mappings of the security plugin managed
+// by the operator. Roles and role mappings removed from the spec are deleted from the cluster
+// +k8s:openapi-gen=true
+type ElasticsearchSecuritySpec struct {
+	// The roles to manage
+	//
+	// +optional
+	Roles []ElasticsearchRole `json:"roles,omitempty"`
+
+	// The role mappings to manage
+	//
+	// +optional
+	RoleMappings []ElasticsearchRoleMapping `json:"roleMappings,omitempty"`
+}
+
+// ElasticsearchRole defines a role of the security plugin
+// +k8s:openapi-gen=true
+type ElasticsearchRole struct {
+	// The name of the role. Reserved roles of the security plugin can not be managed
+	//
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// Cluster permissions and action groups (e.g. CLUSTER_COMPOSITE_OPS_RO)
+	//
+	// +optional
+	ClusterPermissions []string `json:"clusterPermissions,omitempty"`
+
+	// Permissions on the indices matching the index patterns
+	//
+	// +optional
+	IndexPermissions []ElasticsearchIndexPermission `json:"indexPermissions,omitempty"`
+}
+
+// ElasticsearchIndexPermission grants actions on the indices matching a list of patterns
+// +k8s:openapi-gen=true
+type ElasticsearchIndexPermission struct {
+	// Index patterns, e.g. app-team-a-*
+	//
+	// +kubebuilder:validation:MinItems=1
+	IndexPatterns []string `json:"indexPatterns"`
+
+	// Index permissions and action groups (e.g. READ, indices:data/read/search)
+	//
+	// +kubebuilder:validation:MinItems=1
+	AllowedActions []string `json:"allowedActions"`
+}
+
+// ElasticsearchRoleMapping maps users, backend roles and hosts to a role
+// +k8s:openapi-gen=true
+type ElasticsearchRoleMapping struct {
+	// The name of the mapped role
+	//
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`
+	Role string `json:"role"`
+
+	// The users mapped to the role
+	//
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// The backend roles mapped to the role
+	//
+	// +optional
+	BackendRoles []string `json:"backendRoles,omitempty"`
+
+	// The hosts mapped to the role
+	//
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// ElasticsearchSecurityStatus defines the observed state of the managed roles and role mappings
+// +k8s:openapi-gen=true
+type ElasticsearchSecurityStatus struct {
+	// The state of the managed roles
+	//
+	// +optional
+	Roles []ElasticsearchSecurityObjectStatus `json:"roles,omitempty"`
+
+	// The state of the managed role mappings, by the name of their role
+	//
+	// +optional
+	RoleMappings []ElasticsearchSecurityObjectStatus `json:"roleMappings,omitempty"`
+}
+
+// ElasticsearchSecurityObjectStatus defines the observed state of a managed role or role mapping
+// +k8s:openapi-gen=true
+type ElasticsearchSecurityObjectStatus struct {
+	// The name of the role or of the role of the mapping
+	Name string `json:"name"`
+
+	// Whether the object matches the spec
+	State ElasticsearchSecurityObjectState `json:"state"`
+
+	// The reason the object does not match the spec
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The time the object was last written to the cluster, on creation or to correct a drift.
+	// It is only set once the operator wrote the object, the roles and role mappings of the same
+	// name it did not write are neither overwritten nor deleted
+	//
+	// +nullable
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// ElasticsearchSecurityObjectState is the state of a managed role or role mapping
+type ElasticsearchSecurityObjectState string
+
+const (
+	// SecurityObjectSynced means the object in the cluster matches the spec
+	SecurityObjectSynced ElasticsearchSecurityObjectState = "Synced"
+	// SecurityObjectFailed means the object could not be written to the cluster
+	SecurityObjectFailed ElasticsearchSecurityObjectState = "Failed"
+	// SecurityObjectReserved means the object is reserved by the security plugin and left as is
+	SecurityObjectReserved ElasticsearchSecurityObjectState = "Reserved"
+)
@@ -0,0 +1,153 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotPathRepo is the path of the filesystem snapshot repository on the Elasticsearch nodes
+const SnapshotPathRepo = "/elasticsearch/snapshots"
+
+// ElasticsearchSnapshotSpec defines the repository and schedule of the cluster snapshots
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotSpec struct {
+	// The repository to store the snapshots in
+	Repository ElasticsearchSnapshotRepositorySpec `json:"repository"`
+
+	// The cron schedule to take snapshots (e.g. "0 1 * * *")
+	//
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Index patterns to include in the snapshots. Defaults to all indices
+	//
+	// +optional
+	Indices []string `json:"indices,omitempty"`
+
+	// The retention of the scheduled snapshots
+	//
+	// +optional
+	Retention ElasticsearchSnapshotRetentionSpec `json:"retention,omitempty"`
+}
+
+// ElasticsearchSnapshotRepositorySpec defines the snapshot repository. Exactly
+// one of filesystem or s3 is required
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotRepositorySpec struct {
+	// The name to register the repository under
+	//
+	// +kubebuilder:validation:Pattern=`^[a-z0-9][a-z0-9_-]*$`
+	Name string `json:"name"`
+
+	// A shared filesystem repository
+	//
+	// +nullable
+	// +optional
+	Filesystem *ElasticsearchSnapshotFilesystemRepositorySpec `json:"filesystem,omitempty"`
+
+	// An S3 compatible repository
+	//
+	// +nullable
+	// +optional
+	S3 *ElasticsearchSnapshotS3RepositorySpec `json:"s3,omitempty"`
+}
+
+// ElasticsearchSnapshotFilesystemRepositorySpec defines a repository on a shared filesystem
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotFilesystemRepositorySpec struct {
+	// The name of a ReadWriteMany PersistentVolumeClaim mounted by every Elasticsearch node
+	//
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+}
+
+// ElasticsearchSnapshotS3RepositorySpec defines a repository in an S3 compatible object store
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotS3RepositorySpec struct {
+	// The name of the bucket
+	//
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// The host and port of the object store (e.g. minio.minio.svc:9000). Defaults to AWS S3
+	//
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// The protocol to connect to the endpoint with. Defaults to https
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=http;https
+	Protocol string `json:"protocol,omitempty"`
+
+	// The path within the bucket to store the snapshots under
+	//
+	// +optional
+	BasePath string `json:"basePath,omitempty"`
+
+	// The name of a secret in the cluster namespace with the keys 'access_key' and 'secret_key'.
+	// The credentials are added to the keystore of the nodes when they start
+	//
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// ElasticsearchSnapshotRetentionSpec defines which scheduled snapshots are pruned. The
+// most recent successful snapshot is never pruned
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotRetentionSpec struct {
+	// The maximum age of a snapshot before it is deleted (e.g. 30d)
+	//
+	// +optional
+	MaxAge TimeUnit `json:"maxAge,omitempty"`
+
+	// The maximum number of snapshots to keep
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCount int32 `json:"maxCount,omitempty"`
+}
+
+// ElasticsearchSnapshotStatus defines the observed state of the cluster snapshots
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotStatus struct {
+	// The name of the registered repository
+	Repository string `json:"repository,omitempty"`
+
+	// Whether the repository is registered and verified with the cluster
+	RepositoryReady bool `json:"repositoryReady"`
+
+	// The reason the repository could not be registered
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// The last snapshot that completed successfully
+	//
+	// +nullable
+	// +optional
+	LastSuccess *ElasticsearchSnapshotResult `json:"lastSuccess,omitempty"`
+
+	// The last snapshot that failed or only partially completed
+	//
+	// +nullable
+	// +optional
+	LastFailure *ElasticsearchSnapshotResult `json:"lastFailure,omitempty"`
+}
+
+// ElasticsearchSnapshotResult describes a completed snapshot
+// +k8s:openapi-gen=true
+type ElasticsearchSnapshotResult struct {
+	// The name of the snapshot
+	Name string `json:"name"`
+
+	// The state of the snapshot reported by Elasticsearch (e.g. SUCCESS, PARTIAL, FAILED)
+	State string `json:"state"`
+
+	// The time the snapshot completed
+	EndTime metav1.Time `json:"endTime"`
+
+	// The reason of a failed snapshot
+	//
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
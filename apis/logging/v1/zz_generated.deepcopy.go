@@ -6,9 +6,41 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -88,6 +120,179 @@ func (in *Elasticsearch) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchCertificatesSpec) DeepCopyInto(out *ElasticsearchCertificatesSpec) {
+	*out = *in
+	if in.CALifetime != nil {
+		in, out := &in.CALifetime, &out.CALifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchCertificatesSpec.
+func (in *ElasticsearchCertificatesSpec) DeepCopy() *ElasticsearchCertificatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchCertificatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexPermission) DeepCopyInto(out *ElasticsearchIndexPermission) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedActions != nil {
+		in, out := &in.AllowedActions, &out.AllowedActions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexPermission.
+func (in *ElasticsearchIndexPermission) DeepCopy() *ElasticsearchIndexPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexTemplate) DeepCopyInto(out *ElasticsearchIndexTemplate) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexTemplate.
+func (in *ElasticsearchIndexTemplate) DeepCopy() *ElasticsearchIndexTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIndexTemplateStatus) DeepCopyInto(out *ElasticsearchIndexTemplateStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIndexTemplateStatus.
+func (in *ElasticsearchIndexTemplateStatus) DeepCopy() *ElasticsearchIndexTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIndexTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIngestPipeline) DeepCopyInto(out *ElasticsearchIngestPipeline) {
+	*out = *in
+	if in.Processors != nil {
+		in, out := &in.Processors, &out.Processors
+		*out = make([]ElasticsearchIngestProcessor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OnFailure != nil {
+		in, out := &in.OnFailure, &out.OnFailure
+		*out = make([]ElasticsearchIngestProcessor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIngestPipeline.
+func (in *ElasticsearchIngestPipeline) DeepCopy() *ElasticsearchIngestPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIngestPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIngestPipelineStatus) DeepCopyInto(out *ElasticsearchIngestPipelineStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIngestPipelineStatus.
+func (in *ElasticsearchIngestPipelineStatus) DeepCopy() *ElasticsearchIngestPipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIngestPipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchIngestProcessor) DeepCopyInto(out *ElasticsearchIngestProcessor) {
+	*out = *in
+	in.RawExtension.DeepCopyInto(&out.RawExtension)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchIngestProcessor.
+func (in *ElasticsearchIngestProcessor) DeepCopy() *ElasticsearchIngestProcessor {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchIngestProcessor)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchList) DeepCopyInto(out *ElasticsearchList) {
 	*out = *in
@@ -120,6 +325,27 @@ func (in *ElasticsearchList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchMaintenanceWindow) DeepCopyInto(out *ElasticsearchMaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchMaintenanceWindow.
+func (in *ElasticsearchMaintenanceWindow) DeepCopy() *ElasticsearchMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
 	*out = *in
@@ -143,95 +369,479 @@ func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.GenUUID != nil {
-		in, out := &in.GenUUID, &out.GenUUID
-		*out = new(string)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.GenUUID != nil {
+		in, out := &in.GenUUID, &out.GenUUID
+		*out = new(string)
+		**out = **in
+	}
+	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNode.
+func (in *ElasticsearchNode) DeepCopy() *ElasticsearchNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeSpec) DeepCopyInto(out *ElasticsearchNodeSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwarenessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeSpec.
+func (in *ElasticsearchNodeSpec) DeepCopy() *ElasticsearchNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeStatus) DeepCopyInto(out *ElasticsearchNodeStatus) {
+	*out = *in
+	out.UpgradeStatus = in.UpgradeStatus
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchNodeRole, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(ClusterConditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeStatus.
+func (in *ElasticsearchNodeStatus) DeepCopy() *ElasticsearchNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchNodeUpgradeStatus) DeepCopyInto(out *ElasticsearchNodeUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeUpgradeStatus.
+func (in *ElasticsearchNodeUpgradeStatus) DeepCopy() *ElasticsearchNodeUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchNodeUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchPlanStatus) DeepCopyInto(out *ElasticsearchPlanStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ElasticsearchPlannedChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchPlanStatus.
+func (in *ElasticsearchPlanStatus) DeepCopy() *ElasticsearchPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchPlannedChange) DeepCopyInto(out *ElasticsearchPlannedChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchPlannedChange.
+func (in *ElasticsearchPlannedChange) DeepCopy() *ElasticsearchPlannedChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchPlannedChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestartStatus) DeepCopyInto(out *ElasticsearchRestartStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestartStatus.
+func (in *ElasticsearchRestartStatus) DeepCopy() *ElasticsearchRestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestoreSpec) DeepCopyInto(out *ElasticsearchRestoreSpec) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestoreSpec.
+func (in *ElasticsearchRestoreSpec) DeepCopy() *ElasticsearchRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRestoreStatus) DeepCopyInto(out *ElasticsearchRestoreStatus) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRestoreStatus.
+func (in *ElasticsearchRestoreStatus) DeepCopy() *ElasticsearchRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRole) DeepCopyInto(out *ElasticsearchRole) {
+	*out = *in
+	if in.ClusterPermissions != nil {
+		in, out := &in.ClusterPermissions, &out.ClusterPermissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexPermissions != nil {
+		in, out := &in.IndexPermissions, &out.IndexPermissions
+		*out = make([]ElasticsearchIndexPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRole.
+func (in *ElasticsearchRole) DeepCopy() *ElasticsearchRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchRoleMapping) DeepCopyInto(out *ElasticsearchRoleMapping) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendRoles != nil {
+		in, out := &in.BackendRoles, &out.BackendRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchRoleMapping.
+func (in *ElasticsearchRoleMapping) DeepCopy() *ElasticsearchRoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchRoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSecurityObjectStatus) DeepCopyInto(out *ElasticsearchSecurityObjectStatus) {
+	*out = *in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSecurityObjectStatus.
+func (in *ElasticsearchSecurityObjectStatus) DeepCopy() *ElasticsearchSecurityObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSecurityObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSecuritySpec) DeepCopyInto(out *ElasticsearchSecuritySpec) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleMappings != nil {
+		in, out := &in.RoleMappings, &out.RoleMappings
+		*out = make([]ElasticsearchRoleMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSecuritySpec.
+func (in *ElasticsearchSecuritySpec) DeepCopy() *ElasticsearchSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSecurityStatus) DeepCopyInto(out *ElasticsearchSecurityStatus) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ElasticsearchSecurityObjectStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleMappings != nil {
+		in, out := &in.RoleMappings, &out.RoleMappings
+		*out = make([]ElasticsearchSecurityObjectStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSecurityStatus.
+func (in *ElasticsearchSecurityStatus) DeepCopy() *ElasticsearchSecurityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSecurityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshotFilesystemRepositorySpec) DeepCopyInto(out *ElasticsearchSnapshotFilesystemRepositorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotFilesystemRepositorySpec.
+func (in *ElasticsearchSnapshotFilesystemRepositorySpec) DeepCopy() *ElasticsearchSnapshotFilesystemRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ElasticsearchSnapshotFilesystemRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshotRepositorySpec) DeepCopyInto(out *ElasticsearchSnapshotRepositorySpec) {
+	*out = *in
+	if in.Filesystem != nil {
+		in, out := &in.Filesystem, &out.Filesystem
+		*out = new(ElasticsearchSnapshotFilesystemRepositorySpec)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(ElasticsearchSnapshotS3RepositorySpec)
 		**out = **in
 	}
-	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNode.
-func (in *ElasticsearchNode) DeepCopy() *ElasticsearchNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotRepositorySpec.
+func (in *ElasticsearchSnapshotRepositorySpec) DeepCopy() *ElasticsearchSnapshotRepositorySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNode)
+	out := new(ElasticsearchSnapshotRepositorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeSpec) DeepCopyInto(out *ElasticsearchNodeSpec) {
+func (in *ElasticsearchSnapshotResult) DeepCopyInto(out *ElasticsearchSnapshotResult) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	in.EndTime.DeepCopyInto(&out.EndTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotResult.
+func (in *ElasticsearchSnapshotResult) DeepCopy() *ElasticsearchSnapshotResult {
+	if in == nil {
+		return nil
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(ElasticsearchSnapshotResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshotRetentionSpec) DeepCopyInto(out *ElasticsearchSnapshotRetentionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotRetentionSpec.
+func (in *ElasticsearchSnapshotRetentionSpec) DeepCopy() *ElasticsearchSnapshotRetentionSpec {
+	if in == nil {
+		return nil
 	}
-	in.ProxyResources.DeepCopyInto(&out.ProxyResources)
+	out := new(ElasticsearchSnapshotRetentionSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeSpec.
-func (in *ElasticsearchNodeSpec) DeepCopy() *ElasticsearchNodeSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ElasticsearchSnapshotS3RepositorySpec) DeepCopyInto(out *ElasticsearchSnapshotS3RepositorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotS3RepositorySpec.
+func (in *ElasticsearchSnapshotS3RepositorySpec) DeepCopy() *ElasticsearchSnapshotS3RepositorySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeSpec)
+	out := new(ElasticsearchSnapshotS3RepositorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeStatus) DeepCopyInto(out *ElasticsearchNodeStatus) {
+func (in *ElasticsearchSnapshotSpec) DeepCopyInto(out *ElasticsearchSnapshotSpec) {
 	*out = *in
-	out.UpgradeStatus = in.UpgradeStatus
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]ElasticsearchNodeRole, len(*in))
+	in.Repository.DeepCopyInto(&out.Repository)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(ClusterConditions, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Retention = in.Retention
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeStatus.
-func (in *ElasticsearchNodeStatus) DeepCopy() *ElasticsearchNodeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotSpec.
+func (in *ElasticsearchSnapshotSpec) DeepCopy() *ElasticsearchSnapshotSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeStatus)
+	out := new(ElasticsearchSnapshotSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ElasticsearchNodeUpgradeStatus) DeepCopyInto(out *ElasticsearchNodeUpgradeStatus) {
+func (in *ElasticsearchSnapshotStatus) DeepCopyInto(out *ElasticsearchSnapshotStatus) {
 	*out = *in
+	if in.LastSuccess != nil {
+		in, out := &in.LastSuccess, &out.LastSuccess
+		*out = new(ElasticsearchSnapshotResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastFailure != nil {
+		in, out := &in.LastFailure, &out.LastFailure
+		*out = new(ElasticsearchSnapshotResult)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchNodeUpgradeStatus.
-func (in *ElasticsearchNodeUpgradeStatus) DeepCopy() *ElasticsearchNodeUpgradeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSnapshotStatus.
+func (in *ElasticsearchSnapshotStatus) DeepCopy() *ElasticsearchSnapshotStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ElasticsearchNodeUpgradeStatus)
+	out := new(ElasticsearchSnapshotStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -252,6 +862,47 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		*out = new(IndexManagementSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(ElasticsearchSnapshotSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(ElasticsearchRestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = new(ElasticsearchCertificatesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]ElasticsearchMaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(ElasticsearchSecuritySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexTemplates != nil {
+		in, out := &in.IndexTemplates, &out.IndexTemplates
+		*out = make([]ElasticsearchIndexTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]ElasticsearchIngestPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSpec.
@@ -312,6 +963,45 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 		*out = new(IndexManagementStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Snapshot != nil {
+		in, out := &in.Snapshot, &out.Snapshot
+		*out = new(ElasticsearchSnapshotStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(ElasticsearchRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(ElasticsearchSecurityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IndexTemplates != nil {
+		in, out := &in.IndexTemplates, &out.IndexTemplates
+		*out = make([]ElasticsearchIndexTemplateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]ElasticsearchIngestPipelineStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Restart != nil {
+		in, out := &in.Restart, &out.Restart
+		*out = new(ElasticsearchRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ElasticsearchPlanStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
@@ -352,6 +1042,11 @@ func (in *ElasticsearchStorageSpec) DeepCopy() *ElasticsearchStorageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementActionSpec) DeepCopyInto(out *IndexManagementActionSpec) {
 	*out = *in
+	if in.MaxDocs != nil {
+		in, out := &in.MaxDocs, &out.MaxDocs
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementActionSpec.
@@ -370,7 +1065,12 @@ func (in *IndexManagementActionsSpec) DeepCopyInto(out *IndexManagementActionsSp
 	if in.Rollover != nil {
 		in, out := &in.Rollover, &out.Rollover
 		*out = new(IndexManagementActionSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Allocation != nil {
+		in, out := &in.Allocation, &out.Allocation
+		*out = new(IndexManagementAllocationActionSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -384,6 +1084,44 @@ func (in *IndexManagementActionsSpec) DeepCopy() *IndexManagementActionsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementAllocationActionSpec) DeepCopyInto(out *IndexManagementAllocationActionSpec) {
+	*out = *in
+	if in.Require != nil {
+		in, out := &in.Require, &out.Require
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementAllocationActionSpec.
+func (in *IndexManagementAllocationActionSpec) DeepCopy() *IndexManagementAllocationActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementAllocationActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementColdPhaseSpec) DeepCopyInto(out *IndexManagementColdPhaseSpec) {
+	*out = *in
+	in.Actions.DeepCopyInto(&out.Actions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementColdPhaseSpec.
+func (in *IndexManagementColdPhaseSpec) DeepCopy() *IndexManagementColdPhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementColdPhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementDeletePhaseSpec) DeepCopyInto(out *IndexManagementDeletePhaseSpec) {
 	*out = *in
@@ -399,6 +1137,21 @@ func (in *IndexManagementDeletePhaseSpec) DeepCopy() *IndexManagementDeletePhase
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementForceMergeActionSpec) DeepCopyInto(out *IndexManagementForceMergeActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementForceMergeActionSpec.
+func (in *IndexManagementForceMergeActionSpec) DeepCopy() *IndexManagementForceMergeActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementForceMergeActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementHotPhaseSpec) DeepCopyInto(out *IndexManagementHotPhaseSpec) {
 	*out = *in
@@ -438,6 +1191,11 @@ func (in *IndexManagementMappingStatus) DeepCopyInto(out *IndexManagementMapping
 		*out = make([]IndexManagementMappingCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = new(IndexManagementRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 }
 
@@ -451,6 +1209,56 @@ func (in *IndexManagementMappingStatus) DeepCopy() *IndexManagementMappingStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPhaseActionsSpec) DeepCopyInto(out *IndexManagementPhaseActionsSpec) {
+	*out = *in
+	if in.ForceMerge != nil {
+		in, out := &in.ForceMerge, &out.ForceMerge
+		*out = new(IndexManagementForceMergeActionSpec)
+		**out = **in
+	}
+	if in.Shrink != nil {
+		in, out := &in.Shrink, &out.Shrink
+		*out = new(IndexManagementShrinkActionSpec)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(IndexManagementReplicasActionSpec)
+		**out = **in
+	}
+	if in.Allocation != nil {
+		in, out := &in.Allocation, &out.Allocation
+		*out = new(IndexManagementAllocationActionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPhaseActionsSpec.
+func (in *IndexManagementPhaseActionsSpec) DeepCopy() *IndexManagementPhaseActionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPhaseActionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementPhaseStatus) DeepCopyInto(out *IndexManagementPhaseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementPhaseStatus.
+func (in *IndexManagementPhaseStatus) DeepCopy() *IndexManagementPhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementPhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementPhasesSpec) DeepCopyInto(out *IndexManagementPhasesSpec) {
 	*out = *in
@@ -459,6 +1267,16 @@ func (in *IndexManagementPhasesSpec) DeepCopyInto(out *IndexManagementPhasesSpec
 		*out = new(IndexManagementHotPhaseSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Warm != nil {
+		in, out := &in.Warm, &out.Warm
+		*out = new(IndexManagementWarmPhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cold != nil {
+		in, out := &in.Cold, &out.Cold
+		*out = new(IndexManagementColdPhaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Delete != nil {
 		in, out := &in.Delete, &out.Delete
 		*out = new(IndexManagementDeletePhaseSpec)
@@ -535,6 +1353,11 @@ func (in *IndexManagementPolicyStatus) DeepCopyInto(out *IndexManagementPolicySt
 		*out = make([]IndexManagementPolicyCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]IndexManagementPhaseStatus, len(*in))
+		copy(*out, *in)
+	}
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
 }
 
@@ -548,6 +1371,57 @@ func (in *IndexManagementPolicyStatus) DeepCopy() *IndexManagementPolicyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementReplicasActionSpec) DeepCopyInto(out *IndexManagementReplicasActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementReplicasActionSpec.
+func (in *IndexManagementReplicasActionSpec) DeepCopy() *IndexManagementReplicasActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementReplicasActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementRunStatus) DeepCopyInto(out *IndexManagementRunStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.RolledOverIndices != nil {
+		in, out := &in.RolledOverIndices, &out.RolledOverIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementRunStatus.
+func (in *IndexManagementRunStatus) DeepCopy() *IndexManagementRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementShrinkActionSpec) DeepCopyInto(out *IndexManagementShrinkActionSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementShrinkActionSpec.
+func (in *IndexManagementShrinkActionSpec) DeepCopy() *IndexManagementShrinkActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementShrinkActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementSpec) DeepCopyInto(out *IndexManagementSpec) {
 	*out = *in
@@ -607,6 +1481,22 @@ func (in *IndexManagementStatus) DeepCopy() *IndexManagementStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexManagementWarmPhaseSpec) DeepCopyInto(out *IndexManagementWarmPhaseSpec) {
+	*out = *in
+	in.Actions.DeepCopyInto(&out.Actions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexManagementWarmPhaseSpec.
+func (in *IndexManagementWarmPhaseSpec) DeepCopy() *IndexManagementWarmPhaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexManagementWarmPhaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kibana) DeepCopyInto(out *Kibana) {
 	*out = *in
@@ -828,3 +1718,23 @@ func (in *ProxySpec) DeepCopy() *ProxySpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwarenessSpec) DeepCopyInto(out *ZoneAwarenessSpec) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwarenessSpec.
+func (in *ZoneAwarenessSpec) DeepCopy() *ZoneAwarenessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwarenessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -6,26 +6,58 @@ import (
 
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
 
+	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
 	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
 )
 
 // ElasticsearchReconciler reconciles a Elasticsearch object
 type ElasticsearchReconciler struct {
 	client.Client
-	Log    logr.Logger
-	Scheme *runtime.Scheme
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// PodExecutor runs the commands in the pods of the nodes, e.g. to rebuild their keystores
+	// before they reload renewed certificates
+	PodExecutor k8shandler.PodExecutor
+	// HealthCheckInterval is how often an idle cluster is reconciled to follow the health of
+	// Elasticsearch. The changes of the owned resources trigger a reconciliation on their own
+	HealthCheckInterval time.Duration
+	// Namespace is the namespace watched by the manager, all namespaces when empty
+	Namespace string
 }
 
+// DefaultHealthCheckInterval is the interval of the health checks of an idle cluster
+const DefaultHealthCheckInterval = 2 * time.Minute
+
 // Reconcile reads that state of the cluster for a Elasticsearch object and makes changes based on the state read
 // and what is in the Elasticsearch.Spec
 var (
@@ -34,6 +66,15 @@ var (
 	reconcileResult = ctrl.Result{RequeueAfter: reconcilePeriod}
 )
 
+// requeueResult returns when to check the health of the cluster again. Restarts, upgrades and
+// other operations waiting on Elasticsearch are followed every reconcilePeriod
+func (r *ElasticsearchReconciler) requeueResult(cluster *loggingv1.Elasticsearch) ctrl.Result {
+	if r.HealthCheckInterval <= 0 || k8shandler.IsOperationInProgress(cluster) {
+		return reconcileResult
+	}
+	return ctrl.Result{RequeueAfter: r.HealthCheckInterval}
+}
+
 func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result, error) {
 	// Fetch the Elasticsearch instance
 	cluster := &loggingv1.Elasticsearch{}
@@ -43,6 +84,7 @@ func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result,
 		if apierrors.IsNotFound(err) {
 			log.Info("Flushing nodes", "objectKey", request.NamespacedName)
 			k8shandler.FlushNodes(request.NamespacedName.Name, request.NamespacedName.Namespace)
+			metrics.DeleteClusterMetrics(request.NamespacedName.Namespace, request.NamespacedName.Name)
 			k8shandler.RemoveDashboardConfigMap(r.Client)
 			return ctrl.Result{}, nil
 		}
@@ -81,16 +123,276 @@ func (r *ElasticsearchReconciler) Reconcile(request ctrl.Request) (ctrl.Result,
 
 	}
 
-	if err = k8shandler.Reconcile(cluster, r.Client); err != nil {
+	start := time.Now()
+	err = k8shandler.Reconcile(cluster, r.Client, r.Recorder, r.PodExecutor)
+	metrics.ObserveReconcile(cluster.Namespace, cluster.Name, time.Since(start), err)
+	reportStatusMetrics(cluster)
+	if err != nil {
 		return reconcileResult, err
 	}
 
-	return reconcileResult, nil
+	return r.requeueResult(cluster), nil
+}
+
+// reportStatusMetrics reports the state of the cluster described by its status
+func reportStatusMetrics(cluster *loggingv1.Elasticsearch) {
+	conditions := map[string]bool{}
+	for _, condition := range cluster.Status.Conditions {
+		conditions[string(condition.Type)] = condition.Status == v1.ConditionTrue
+	}
+	metrics.SetClusterConditions(cluster.Namespace, cluster.Name, conditions)
+
+	phases := map[string]string{}
+	for _, node := range cluster.Status.Nodes {
+		name := node.DeploymentName
+		if name == "" {
+			name = node.StatefulSetName
+		}
+		phases[name] = string(node.UpgradeStatus.UpgradePhase)
+	}
+	metrics.SetNodeUpgradePhases(cluster.Namespace, cluster.Name, phases)
+
+	runs := map[string]metrics.IndexManagementRun{}
+	if cluster.Status.IndexManagementStatus != nil {
+		for _, mapping := range cluster.Status.IndexManagementStatus.Mappings {
+			if mapping.LastRun == nil {
+				continue
+			}
+			runs[mapping.Name] = metrics.IndexManagementRun{
+				Finished:            mapping.LastRun.Time.Time,
+				Succeeded:           mapping.LastRun.Outcome == loggingv1.IndexManagementRunSucceeded,
+				ConsecutiveFailures: mapping.LastRun.ConsecutiveFailures,
+			}
+		}
+	}
+	metrics.SetIndexManagementRuns(cluster.Namespace, cluster.Name, runs)
+}
+
+// getIndexManagementJobEvent returns the cluster of an index management job
+func getIndexManagementJobEvent(a handler.MapObject) []reconcile.Request {
+	clusterName, ok := indexmanagement.IsIndexManagementJob(a.Meta.GetLabels())
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      clusterName,
+				Namespace: a.Meta.GetNamespace(),
+			},
+		},
+	}
+}
+
+// getPodEvent returns the cluster of an Elasticsearch pod
+func getPodEvent(a handler.MapObject) []reconcile.Request {
+	labels := a.Meta.GetLabels()
+	if labels["component"] != "elasticsearch" || labels["cluster-name"] == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      labels["cluster-name"],
+				Namespace: a.Meta.GetNamespace(),
+			},
+		},
+	}
+}
+
+// getClaimEvent returns the cluster of a claim of an Elasticsearch node. The claims are not owned
+// by the cluster to retain the data when the cluster is deleted
+func getClaimEvent(a handler.MapObject) []reconcile.Request {
+	clusterName, ok := a.Meta.GetLabels()["logging-cluster"]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      clusterName,
+				Namespace: a.Meta.GetNamespace(),
+			},
+		},
+	}
+}
+
+// hasPodStatusChanged returns true if the pod changed its phase, readiness or restarted a container
+func hasPodStatusChanged(oldObj, newObj runtime.Object) bool {
+	oldPod, ok := oldObj.(*v1.Pod)
+	if !ok {
+		return false
+	}
+	newPod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return false
+	}
+
+	if oldPod.Status.Phase != newPod.Status.Phase || (oldPod.DeletionTimestamp == nil) != (newPod.DeletionTimestamp == nil) {
+		return true
+	}
+	if isPodReady(oldPod) != isPodReady(newPod) {
+		return true
+	}
+	restarts := func(pod *v1.Pod) int32 {
+		count := int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			count += status.RestartCount
+		}
+		return count
+	}
+	return restarts(oldPod) != restarts(newPod)
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isJobFinished returns true if the job completed or failed
+func isJobFinished(obj runtime.Object) bool {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return false
+	}
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *ElasticsearchReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Watch for index management jobs finishing to report their outcome
+	jobPred := predicate.Funcs{
+		UpdateFunc:  func(e event.UpdateEvent) bool { return !isJobFinished(e.ObjectOld) && isJobFinished(e.ObjectNew) },
+		CreateFunc:  func(e event.CreateEvent) bool { return false },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
+	// Watch for the pods of the nodes crashing, becoming ready or going away
+	podPred := predicate.Funcs{
+		UpdateFunc:  func(e event.UpdateEvent) bool { return hasPodStatusChanged(e.ObjectOld, e.ObjectNew) },
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+
+	// The pods and claims of the nodes are watched through informers filtered by their labels,
+	// instead of the cache of the manager holding every pod and claim of the watched namespace
+	podInformer, err := newFilteredInformer(mgr, r.Namespace, "pods", &v1.Pod{}, "component=elasticsearch")
+	if err != nil {
+		return err
+	}
+	claimInformer, err := newFilteredInformer(mgr, r.Namespace, "persistentvolumeclaims", &v1.PersistentVolumeClaim{}, "logging-cluster")
+	if err != nil {
+		return err
+	}
+	jobInformer, err := newFilteredInformer(mgr, r.Namespace, "jobs", &batchv1.Job{}, "component=indexManagement,logging-infra=indexManagement,cluster-name")
+	if err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("elasticsearch-controller").
 		For(&loggingv1.Elasticsearch{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&v1.Service{}).
+		Owns(&v1.ConfigMap{}).
+		Owns(&batchv1beta1.CronJob{}).
+		Watches(&source.Informer{Informer: claimInformer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(getClaimEvent),
+		}).
+		Watches(&source.Informer{Informer: podInformer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(getPodEvent),
+		}, builder.WithPredicates(podPred)).
+		Watches(&source.Informer{Informer: jobInformer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(getIndexManagementJobEvent),
+		}, builder.WithPredicates(jobPred)).
 		Complete(r)
 }
+
+// newFilteredInformer returns an informer of the objects of the core or batch resource matching
+// the label selector. The informer runs along with the manager
+func newFilteredInformer(mgr ctrl.Manager, namespace, resource string, objType runtime.Object, selector string) (toolscache.SharedIndexInformer, error) {
+	var restClient rest.Interface
+	if _, ok := objType.(*batchv1.Job); ok {
+		batchClient, err := batchv1client.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to create the client of the informer",
+				"resource", resource)
+		}
+		restClient = batchClient.RESTClient()
+	} else {
+		coreClient, err := corev1client.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to create the client of the informer",
+				"resource", resource)
+		}
+		restClient = coreClient.RESTClient()
+	}
+
+	listWatch := toolscache.NewFilteredListWatchFromClient(restClient, resource, namespace, func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+	})
+	informer := toolscache.NewSharedIndexInformer(listWatch, objType, 0, toolscache.Indexers{})
+
+	err := mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		informer.Run(stop)
+		return nil
+	}))
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to add the informer to the manager",
+			"resource", resource)
+	}
+	return informer, nil
+}
+
+// NewClient returns the client of the manager. The pods, claims and jobs are read from the API
+// server, since reading them through the cache of the manager would add informers holding
+// every pod, claim and job of the watched namespace next to the filtered ones
+func NewClient(cache cache.Cache, config *rest.Config, options client.Options) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.DelegatingClient{
+		Reader: &uncachedReader{
+			cacheReader:  cache,
+			clientReader: c,
+		},
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
+
+// uncachedReader reads the objects watched through filtered informers from the API server and
+// any other object from the cache
+type uncachedReader struct {
+	cacheReader  client.Reader
+	clientReader client.Reader
+}
+
+func (r *uncachedReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	switch obj.(type) {
+	case *v1.Pod, *v1.PersistentVolumeClaim, *batchv1.Job, *unstructured.Unstructured:
+		return r.clientReader.Get(ctx, key, obj)
+	}
+	return r.cacheReader.Get(ctx, key, obj)
+}
+
+func (r *uncachedReader) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	switch list.(type) {
+	case *v1.PodList, *v1.PersistentVolumeClaimList, *batchv1.JobList, *unstructured.UnstructuredList:
+		return r.clientReader.List(ctx, list, opts...)
+	}
+	return r.cacheReader.List(ctx, list, opts...)
+}
@@ -13,6 +13,7 @@ const (
 	SecretHashPrefix            = "logging.openshift.io/"
 	ElasticsearchDefaultImage   = "quay.io/openshift/origin-logging-elasticsearch6"
 	ProxyDefaultImage           = "quay.io/openshift/origin-elasticsearch-proxy:latest"
+	OperatorDefaultImage        = "quay.io/openshift/origin-elasticsearch-operator:latest"
 	TheoreticalShardMaxSizeInMB = 40960
 
 	// OcpTemplatePrefix is the prefix all operator generated templates
@@ -27,6 +28,7 @@ const (
 var (
 	ReconcileForGlobalProxyList = []string{KibanaTrustedCAName}
 	packagedElasticsearchImage  = utils.LookupEnvWithDefault("ELASTICSEARCH_IMAGE", ElasticsearchDefaultImage)
+	packagedOperatorImage       = utils.LookupEnvWithDefault("OPERATOR_IMAGE", OperatorDefaultImage)
 	ExpectedSecretKeys          = []string{
 		"admin-ca",
 		"admin-cert",
@@ -41,3 +43,8 @@ var (
 func PackagedElasticsearchImage() string {
 	return packagedElasticsearchImage
 }
+
+// PackagedOperatorImage is the image of the operator, which also runs the index management jobs
+func PackagedOperatorImage() string {
+	return packagedOperatorImage
+}
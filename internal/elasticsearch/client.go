@@ -14,11 +14,13 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,15 +59,26 @@ type Client interface {
 	CreateIndex(name string, index *estypes.Index) error
 	ReIndex(src, dst, script, lang string) error
 	GetAllIndices(name string) (estypes.CatIndicesResponses, error)
+	GetIndicesCreationDate(pattern string) (map[string]time.Time, error)
+	CloseIndex(name string) error
+	OpenIndex(name string) error
+	DeleteIndices(names ...string) error
+	RolloverIndex(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error)
+	ShrinkIndex(source, target string, request *estypes.ShrinkIndexRequest) error
+	ForceMerge(name string, maxNumSegments int32) error
 
 	// Index Alias API
 	ListIndicesForAlias(aliasPattern string) ([]string, error)
+	GetAliases(aliasPattern string) (map[string]map[string]estypes.IndexAlias, error)
+	GetIndexAliases(name string) (map[string]estypes.IndexAlias, error)
 	UpdateAlias(actions estypes.AliasActions) error
 	AddAliasForOldIndices() bool
 
 	// Index Settings API
 	GetIndexSettings(name string) (*estypes.Index, error)
+	GetIndicesDefaultPipeline(pattern string) (map[string]string, error)
 	UpdateIndexSettings(name string, settings *estypes.IndexSettings) error
+	PutIndexSettings(name string, settings map[string]interface{}) error
 
 	// Nodes API
 	GetNodeDiskUsage(nodeName string) (string, float64, error)
@@ -79,14 +92,46 @@ type Client interface {
 	ClearTransientShardAllocation() (bool, error)
 	GetShardAllocation() (string, error)
 	SetShardAllocation(state api.ShardAllocationState) (bool, error)
+	GetShardAllocationExclude() ([]string, error)
+	SetShardAllocationExclude(nodeNames []string) error
+	GetNodeShardCounts() (map[string]int32, error)
+	GetIndexShards(index string) (estypes.CatShardsResponses, error)
+	GetNodeShards() (map[string][]string, error)
 
 	// Index Templates API
 	CreateIndexTemplate(name string, template *estypes.IndexTemplate) error
 	DeleteIndexTemplate(name string) error
 	ListTemplates() (sets.String, error)
 	GetIndexTemplates() (map[string]estypes.GetIndexTemplate, error)
+	GetRawIndexTemplate(name string) (*estypes.RawIndexTemplate, error)
+	PutRawIndexTemplate(name string, template *estypes.RawIndexTemplate) error
 	UpdateTemplatePrimaryShards(shardCount int32) error
 
+	// Security API
+	ReloadCertificates(address string) error
+	GetCertificatesNotAfter() (time.Time, error)
+	GetSecurityRole(name string) (*estypes.SecurityRole, error)
+	PutSecurityRole(name string, role *estypes.SecurityRole) error
+	DeleteSecurityRole(name string) error
+	GetSecurityRoleMapping(role string) (*estypes.SecurityRoleMapping, error)
+	PutSecurityRoleMapping(role string, mapping *estypes.SecurityRoleMapping) error
+	DeleteSecurityRoleMapping(role string) error
+
+	// Ingest API
+	GetIngestPipeline(id string) (*estypes.IngestPipeline, error)
+	PutIngestPipeline(id string, pipeline *estypes.IngestPipeline) error
+	DeleteIngestPipeline(id string) error
+
+	// Snapshot API
+	CreateSnapshotRepository(name string, repository *estypes.SnapshotRepository) error
+	GetSnapshotRepository(name string) (*estypes.SnapshotRepository, error)
+	GetSnapshots(repository string) ([]estypes.Snapshot, error)
+	GetSnapshot(repository, name string) (*estypes.Snapshot, error)
+	CreateSnapshot(repository, name string, request *estypes.SnapshotRequest) (*estypes.Snapshot, error)
+	DeleteSnapshot(repository, name string) error
+	RestoreSnapshot(repository, name string, request *estypes.SnapshotRestoreRequest) error
+	GetIndicesRecovery(pattern string) (map[string]estypes.IndexRecovery, error)
+
 	SetSendRequestFn(fn FnEsSendRequest)
 }
 
@@ -102,6 +147,7 @@ type esClient struct {
 type EsRequest struct {
 	Method          string // use net/http constants https://golang.org/pkg/net/http/#pkg-constants
 	URI             string
+	Address         string // the address of the node to send the request to instead of the cluster service
 	RequestBody     string
 	StatusCode      int
 	RawResponseBody string
@@ -133,9 +179,29 @@ func (ec *esClient) errorCtx() kverrors.Context {
 	)
 }
 
+// getServiceName returns the name of the cluster service the HTTP certificates are issued for
+func getServiceName(cluster, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc", cluster, namespace)
+}
+
+func getRequestURL(cluster, namespace string, payload *EsRequest) string {
+	host := getServiceName(cluster, namespace)
+	if payload.Address != "" {
+		host = payload.Address
+	}
+	return fmt.Sprintf("https://%s/%s", net.JoinHostPort(host, "9200"), payload.URI)
+}
+
+// observeRequest reports the duration of the request started at start to the operator metrics
+func observeRequest(cluster, namespace string, payload *EsRequest, start time.Time) {
+	metrics.ObserveElasticsearchRequest(namespace, cluster, payload.Method, payload.StatusCode, time.Since(start))
+}
+
 // FIXME: this needs to return an error instead of swallowing
 func sendEsRequest(cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
-	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", cluster, namespace, payload.URI)
+	defer observeRequest(cluster, namespace, payload, time.Now())
+
+	u := getRequestURL(cluster, namespace, payload)
 	urlURL, err := url.Parse(u)
 	if err != nil {
 		log.Error(err, "failed to parse URL", "url", u)
@@ -219,7 +285,7 @@ func sendEsRequest(cluster, namespace string, payload *EsRequest, client k8sclie
 }
 
 func sendRequestWithMTlsClient(clusterName, namespace string, payload *EsRequest, client k8sclient.Client) {
-	u := fmt.Sprintf("https://%s.%s.svc:9200/%s", clusterName, namespace, payload.URI)
+	u := getRequestURL(clusterName, namespace, payload)
 	urlURL, err := url.Parse(u)
 	if err != nil {
 		log.Error(err, "unable to parse URL", "url", u)
@@ -288,6 +354,65 @@ func sendRequestWithMTlsClient(clusterName, namespace string, payload *EsRequest
 	payload.Error = err
 }
 
+// NewServiceSendRequestFn returns a function sending the requests to the Elasticsearch service at
+// serviceURL with the given HTTP client instead of the cluster service with the certificates from
+// the cluster secret, e.g. for the index management jobs which mount the certificates
+func NewServiceSendRequestFn(serviceURL string, httpClient *http.Client) FnEsSendRequest {
+	return func(_, _ string, payload *EsRequest, _ k8sclient.Client) {
+		u := fmt.Sprintf("%s/%s", strings.TrimSuffix(serviceURL, "/"), payload.URI)
+		var body io.Reader
+		if payload.RequestBody != "" {
+			body = strings.NewReader(payload.RequestBody)
+		}
+		request, err := http.NewRequest(payload.Method, u, body)
+		if err != nil {
+			payload.Error = err
+			return
+		}
+		if payload.RequestBody != "" {
+			request.Header.Set("Content-Type", "application/json")
+		}
+		request.Header = ensureTokenHeader(request.Header)
+
+		resp, err := httpClient.Do(request)
+		if err != nil {
+			payload.Error = err
+			return
+		}
+		defer resp.Body.Close()
+
+		payload.StatusCode = resp.StatusCode
+		if payload.RawResponseBody, err = getRawBody(resp.Body); err != nil {
+			payload.Error = err
+			return
+		}
+		payload.ResponseBody, payload.Error = getMapFromBody(payload.RawResponseBody)
+	}
+}
+
+// NewTimeoutSendRequestFn returns a function sending the requests to the cluster service which
+// gives up after the timeout, e.g. for the admission webhook which must answer quickly. The
+// request keeps running in the background until the HTTP client times out
+func NewTimeoutSendRequestFn(timeout time.Duration) FnEsSendRequest {
+	return func(cluster, namespace string, payload *EsRequest, client k8sclient.Client) {
+		done := make(chan EsRequest, 1)
+		sent := *payload
+		go func() {
+			sendEsRequest(cluster, namespace, &sent, client)
+			done <- sent
+		}()
+
+		select {
+		case result := <-done:
+			*payload = result
+		case <-time.After(timeout):
+			payload.Error = kverrors.New("timed out sending the request",
+				"uri", payload.URI,
+				"timeout", timeout)
+		}
+	}
+}
+
 func ensureTokenHeader(header http.Header) http.Header {
 	if header == nil {
 		header = map[string][]string{}
@@ -339,6 +464,7 @@ func getTLSClient(clusterName, namespace string, client k8sclient.Client) *http.
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: false,
 				RootCAs:            getRootCA(clusterName, namespace),
+				ServerName:         getServiceName(clusterName, namespace),
 			},
 		},
 	}
@@ -367,6 +493,7 @@ func getMTlsClient(clusterName, namespace string, client k8sclient.Client) *http
 				InsecureSkipVerify: false,
 				RootCAs:            getRootCA(clusterName, namespace),
 				Certificates:       getClientCertificates(clusterName, namespace),
+				ServerName:         getServiceName(clusterName, namespace),
 			},
 		},
 	}
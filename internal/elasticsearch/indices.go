@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
@@ -70,6 +72,72 @@ func (ec *esClient) GetAllIndices(name string) (estypes.CatIndicesResponses, err
 	return res, nil
 }
 
+// GetIndicesCreationDate returns the creation date of the indices for the given pattern (e.g. foo-*, foo-write)
+func (ec *esClient) GetIndicesCreationDate(pattern string) (map[string]time.Time, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_settings/index.creation_date", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return map[string]time.Time{}, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index creation dates",
+			"pattern", pattern,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	dates := map[string]time.Time{}
+	for index, settings := range payload.ResponseBody {
+		settingsMap, ok := settings.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		millis, err := strconv.ParseInt(parseString("settings.index.creation_date", settingsMap), 10, 64)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse index creation date",
+				"index", index)
+		}
+		dates[index] = time.Unix(0, millis*int64(time.Millisecond))
+	}
+	return dates, nil
+}
+
+// GetIndicesDefaultPipeline returns the default ingest pipeline of the indices for the given
+// pattern which have one
+func (ec *esClient) GetIndicesDefaultPipeline(pattern string) (map[string]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_settings/index.default_pipeline", pattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return map[string]string{}, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index default pipelines",
+			"pattern", pattern,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	pipelines := map[string]string{}
+	for index, settings := range payload.ResponseBody {
+		settingsMap, ok := settings.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pipeline := parseString("settings.index.default_pipeline", settingsMap); pipeline != "" {
+			pipelines[index] = pipeline
+		}
+	}
+	return pipelines, nil
+}
+
 func (ec *esClient) CreateIndex(name string, index *estypes.Index) error {
 	body, err := utils.ToJSON(index)
 	if err != nil {
@@ -93,6 +161,44 @@ func (ec *esClient) CreateIndex(name string, index *estypes.Index) error {
 	return nil
 }
 
+// CloseIndex closes the index, e.g. before restoring it from a snapshot
+func (ec *esClient) CloseIndex(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_close", name),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to close index",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// OpenIndex opens the index, e.g. after a failed restore of a closed index
+func (ec *esClient) OpenIndex(name string) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_open", name),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to open index",
+			"index", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
 func (ec *esClient) GetIndexSettings(name string) (*estypes.Index, error) {
 	payload := &EsRequest{
 		Method: http.MethodGet,
@@ -325,3 +431,165 @@ func (ec *esClient) AddAliasForOldIndices() bool {
 
 	return successful
 }
+
+// DeleteIndices deletes the given indices. Missing indices are ignored
+func (ec *esClient) DeleteIndices(names ...string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("%s?ignore_unavailable=true", strings.Join(names, ",")),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to delete indices",
+			"indices", names,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// GetAliases returns the aliases of each index for the given alias pattern (e.g. foo-write, foo*-write)
+func (ec *esClient) GetAliases(aliasPattern string) (map[string]map[string]estypes.IndexAlias, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_alias/%s", aliasPattern),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return map[string]map[string]estypes.IndexAlias{}, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get aliases",
+			"alias", aliasPattern,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	indices := map[string]estypes.Index{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &indices); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _alias response body",
+			"alias", aliasPattern)
+	}
+	aliases := map[string]map[string]estypes.IndexAlias{}
+	for name, index := range indices {
+		aliases[name] = index.Aliases
+	}
+	return aliases, nil
+}
+
+// GetIndexAliases returns the aliases of the index
+func (ec *esClient) GetIndexAliases(name string) (map[string]estypes.IndexAlias, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_alias", name),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index aliases",
+			"index", name,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	indices := map[string]estypes.Index{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &indices); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _alias response body",
+			"index", name)
+	}
+	return indices[name].Aliases, nil
+}
+
+// PutIndexSettings updates the given flat settings (e.g. index.number_of_replicas) of the index.
+// A nil value resets the setting to its default
+func (ec *esClient) PutIndexSettings(name string, settings map[string]interface{}) error {
+	body, err := utils.ToJSON(settings)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("%s/_settings", name),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to update index settings",
+			"index", name,
+			"settings", settings,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ForceMerge merges the segments of each shard of the index down to maxNumSegments
+func (ec *esClient) ForceMerge(name string, maxNumSegments int32) error {
+	payload := &EsRequest{
+		Method: http.MethodPost,
+		URI:    fmt.Sprintf("%s/_forcemerge?max_num_segments=%d", name, maxNumSegments),
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to force merge index",
+			"index", name,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// ShrinkIndex shrinks the source index into the new target index
+func (ec *esClient) ShrinkIndex(source, target string, request *estypes.ShrinkIndexRequest) error {
+	body, err := utils.ToJSON(request)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_shrink/%s", source, target),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to shrink index",
+			"index", source,
+			"target", target,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+	return nil
+}
+
+// RolloverIndex rolls the alias over to a new index when any of the conditions is met
+func (ec *esClient) RolloverIndex(alias string, conditions estypes.RolloverConditions) (*estypes.RolloverResponse, error) {
+	body, err := utils.ToJSON(estypes.RolloverRequest{Conditions: conditions})
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("%s/_rollover", alias),
+		RequestBody: body,
+	}
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to rollover index",
+			"alias", alias,
+			"response_error", payload.Error,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody)
+	}
+
+	response := &estypes.RolloverResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), response); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _rollover response body",
+			"alias", alias)
+	}
+	return response, nil
+}
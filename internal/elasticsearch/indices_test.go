@@ -1,8 +1,11 @@
 package elasticsearch_test
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -349,3 +352,136 @@ func TestAliasOperationsNotNeeded(t *testing.T) {
 		t.Errorf("Expected creation of aliases to succeed")
 	}
 }
+
+func TestGetIndicesCreationDate(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app-write/_settings/index.creation_date": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body: `{
+                      "app-000001": {
+                          "settings": {"index": {"creation_date": "1600000000000"}}
+                      },
+                      "app-000002": {
+                          "settings": {"index": {"creation_date": "1600000060000"}}
+                      }
+                    }`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	dates, err := esClient.GetIndicesCreationDate("app-write")
+	if err != nil {
+		t.Fatalf("Expected to get creation dates without error: %v", err)
+	}
+	if len(dates) != 2 {
+		t.Fatalf("Expected creation dates for 2 indices, got %v", dates)
+	}
+	if !dates["app-000002"].Equal(time.Unix(1600000060, 0)) {
+		t.Errorf("Expected creation date of app-000002 to be %v, got %v", time.Unix(1600000060, 0), dates["app-000002"])
+	}
+}
+
+func TestGetIndicesCreationDateWhenAliasMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app-write/_settings/index.creation_date": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	dates, err := esClient.GetIndicesCreationDate("app-write")
+	if err != nil {
+		t.Fatalf("Expected to not error for a missing alias: %v", err)
+	}
+	if len(dates) != 0 {
+		t.Errorf("Expected no creation dates, got %v", dates)
+	}
+}
+
+func TestGetIndicesDefaultPipeline(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"*/_settings/index.default_pipeline": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body: `{
+                      "app-000001": {
+                          "settings": {"index": {"default_pipeline": "lowercase-level"}}
+                      },
+                      "infra-000001": {
+                          "settings": {}
+                      }
+                    }`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	pipelines, err := esClient.GetIndicesDefaultPipeline("*")
+	if err != nil {
+		t.Fatalf("Expected to get default pipelines without error: %v", err)
+	}
+	want := map[string]string{"app-000001": "lowercase-level"}
+	if !reflect.DeepEqual(pipelines, want) {
+		t.Errorf("Expected default pipelines %v, got %v", want, pipelines)
+	}
+}
+
+func TestGetIndexAliases(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app-000001/_alias": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"app-000001": {"aliases": {"app": {}, "app-write": {"is_write_index": false}}}}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	aliases, err := esClient.GetIndexAliases("app-000001")
+	if err != nil {
+		t.Fatalf("Expected to get the aliases without error: %v", err)
+	}
+	want := map[string]estypes.IndexAlias{"app": {}, "app-write": {}}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("Expected aliases %v, got %v", want, aliases)
+	}
+}
+
+func TestOpenIndex(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app-000001/_open": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"acknowledged": true, "shards_acknowledged": true}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.OpenIndex("app-000001"); err != nil {
+		t.Errorf("Expected to open the index: %v", err)
+	}
+	if err := esClient.OpenIndex("app-000002"); err == nil {
+		t.Error("Expected an error for an unknown index")
+	}
+}
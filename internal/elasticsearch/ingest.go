@@ -0,0 +1,84 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// GetIngestPipeline returns the pipeline or nil if it does not exist
+func (ec *esClient) GetIngestPipeline(id string) (*estypes.IngestPipeline, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_ingest/pipeline/%s", id),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get ingest pipeline",
+			"pipeline", id,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	pipelines := map[string]*estypes.IngestPipeline{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &pipelines); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode ingest pipeline",
+			"pipeline", id)
+	}
+	return pipelines[id], nil
+}
+
+// PutIngestPipeline creates or replaces the pipeline
+func (ec *esClient) PutIngestPipeline(id string, pipeline *estypes.IngestPipeline) error {
+	body, err := utils.ToJSON(pipeline)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_ingest/pipeline/%s", id),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to put ingest pipeline",
+			"pipeline", id,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+// DeleteIngestPipeline deletes the pipeline. Missing pipelines are ignored
+func (ec *esClient) DeleteIngestPipeline(id string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("_ingest/pipeline/%s", id),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && (payload.StatusCode == http.StatusNotFound || payload.StatusCode < 300) {
+		return nil
+	}
+
+	return ec.errorCtx().New("failed to delete ingest pipeline",
+		"pipeline", id,
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody,
+		"response_error", payload.Error,
+	)
+}
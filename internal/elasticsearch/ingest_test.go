@@ -0,0 +1,101 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestGetIngestPipeline(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ingest/pipeline/lowercase-level": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"lowercase-level": {"description": "Lowercase the level", "processors": [{"lowercase": {"field": "level"}}]}}`,
+				},
+			},
+			"_ingest/pipeline/missing": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	pipeline, err := esClient.GetIngestPipeline("lowercase-level")
+	if err != nil {
+		t.Fatalf("Expected to get the pipeline without error: %v", err)
+	}
+	want := &estypes.IngestPipeline{
+		Description: "Lowercase the level",
+		Processors:  []map[string]interface{}{{"lowercase": map[string]interface{}{"field": "level"}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("Expected pipeline %+v, got %+v", want, pipeline)
+	}
+
+	pipeline, err = esClient.GetIngestPipeline("missing")
+	if err != nil || pipeline != nil {
+		t.Errorf("Expected no pipeline and no error for a missing pipeline, got %+v, %v", pipeline, err)
+	}
+}
+
+func TestPutIngestPipeline(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ingest/pipeline/lowercase-level": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	pipeline := &estypes.IngestPipeline{
+		Processors: []map[string]interface{}{{"lowercase": map[string]interface{}{"field": "level"}}},
+	}
+	if err := esClient.PutIngestPipeline("lowercase-level", pipeline); err != nil {
+		t.Fatalf("Expected to put the pipeline without error: %v", err)
+	}
+
+	req, found := chatter.GetRequest("_ingest/pipeline/lowercase-level")
+	if !found {
+		t.Fatal("Expected a request to put the pipeline")
+	}
+	if req.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, req.Method)
+	}
+	if req.Body != `{"processors":[{"lowercase":{"field":"level"}}]}` {
+		t.Errorf("Expected the body to only carry the processors, got %s", req.Body)
+	}
+}
+
+func TestDeleteIngestPipelineWhenMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_ingest/pipeline/missing": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.DeleteIngestPipeline("missing"); err != nil {
+		t.Errorf("Expected no error when deleting a missing pipeline, got %v", err)
+	}
+}
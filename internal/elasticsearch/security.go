@@ -0,0 +1,211 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// certificateTypes are the TLS layers of a node the security plugin reloads the certificates of
+var certificateTypes = []string{"transport", "http"}
+
+// ReloadCertificates makes the node at the address reload its transport and HTTP certificates
+// from disk. The security plugin only reloads the certificates of the node serving the request
+func (ec *esClient) ReloadCertificates(address string) error {
+	for _, certType := range certificateTypes {
+		payload := &EsRequest{
+			Method:  http.MethodPut,
+			URI:     fmt.Sprintf("_opendistro/_security/api/ssl/%s/reloadcerts", certType),
+			Address: address,
+		}
+
+		ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+		if payload.Error != nil || payload.StatusCode != http.StatusOK {
+			return ec.errorCtx().New("failed to reload certificates",
+				"address", address,
+				"type", certType,
+				"response_status", payload.StatusCode,
+				"response_body", payload.ResponseBody,
+				"response_error", payload.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// GetCertificatesNotAfter returns the earliest expiry of the transport and HTTP certificates
+// the node serving the request uses
+func (ec *esClient) GetCertificatesNotAfter() (time.Time, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_opendistro/_security/api/ssl/certs",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return time.Time{}, ec.errorCtx().New("failed to get certificates",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	var notAfter time.Time
+	for _, certType := range certificateTypes {
+		certs, ok := payload.ResponseBody[fmt.Sprintf("%s_certificates_list", certType)].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, cert := range certs {
+			fields, _ := cert.(map[string]interface{})
+			value, _ := fields["not_after"].(string)
+			certNotAfter, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return time.Time{}, ec.errorCtx().Wrap(err, "failed to parse the expiry of certificate",
+					"type", certType,
+					"not_after", value)
+			}
+			if notAfter.IsZero() || certNotAfter.Before(notAfter) {
+				notAfter = certNotAfter
+			}
+		}
+	}
+
+	if notAfter.IsZero() {
+		return notAfter, ec.errorCtx().New("no certificates found",
+			"response_body", payload.ResponseBody)
+	}
+	return notAfter, nil
+}
+
+// GetSecurityRole returns the role of the security plugin or nil if it does not exist
+func (ec *esClient) GetSecurityRole(name string) (*estypes.SecurityRole, error) {
+	role := &estypes.SecurityRole{}
+	found, err := ec.getSecurityObject("roles", name, role)
+	if err != nil || !found {
+		return nil, err
+	}
+	return role, nil
+}
+
+// PutSecurityRole creates or replaces the role of the security plugin
+func (ec *esClient) PutSecurityRole(name string, role *estypes.SecurityRole) error {
+	return ec.putSecurityObject("roles", name, role)
+}
+
+// DeleteSecurityRole deletes the role of the security plugin. Missing roles are ignored
+func (ec *esClient) DeleteSecurityRole(name string) error {
+	return ec.deleteSecurityObject("roles", name)
+}
+
+// GetSecurityRoleMapping returns the mapping of the role or nil if it does not exist
+func (ec *esClient) GetSecurityRoleMapping(role string) (*estypes.SecurityRoleMapping, error) {
+	mapping := &estypes.SecurityRoleMapping{}
+	found, err := ec.getSecurityObject("rolesmapping", role, mapping)
+	if err != nil || !found {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// PutSecurityRoleMapping creates or replaces the mapping of the role
+func (ec *esClient) PutSecurityRoleMapping(role string, mapping *estypes.SecurityRoleMapping) error {
+	return ec.putSecurityObject("rolesmapping", role, mapping)
+}
+
+// DeleteSecurityRoleMapping deletes the mapping of the role. Missing mappings are ignored
+func (ec *esClient) DeleteSecurityRoleMapping(role string) error {
+	return ec.deleteSecurityObject("rolesmapping", role)
+}
+
+// getSecurityObject decodes the object of the security REST API into obj. It returns false if
+// the object does not exist
+func (ec *esClient) getSecurityObject(kind, name string, obj interface{}) (bool, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_opendistro/_security/api/%s/%s", kind, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return false, ec.errorCtx().New("failed to get security object",
+			"kind", kind,
+			"name", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	objects := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &objects); err != nil {
+		return false, kverrors.Wrap(err, "failed to decode security object",
+			"kind", kind,
+			"name", name)
+	}
+	raw, ok := objects[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return false, kverrors.Wrap(err, "failed to decode security object",
+			"kind", kind,
+			"name", name)
+	}
+	return true, nil
+}
+
+func (ec *esClient) putSecurityObject(kind, name string, obj interface{}) error {
+	body, err := utils.ToJSON(obj)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_opendistro/_security/api/%s/%s", kind, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to put security object",
+			"kind", kind,
+			"name", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+func (ec *esClient) deleteSecurityObject(kind, name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("_opendistro/_security/api/%s/%s", kind, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to delete security object",
+			"kind", kind,
+			"name", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
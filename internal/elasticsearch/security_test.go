@@ -0,0 +1,188 @@
+package elasticsearch_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestReloadCertificates(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/ssl/transport/reloadcerts": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"message": "updated transport certs"}`,
+				},
+			},
+			"_opendistro/_security/api/ssl/http/reloadcerts": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"message": "updated http certs"}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.ReloadCertificates("10.128.2.12"); err != nil {
+		t.Fatalf("Expected to reload the certificates without error: %v", err)
+	}
+
+	for _, uri := range []string{"_opendistro/_security/api/ssl/transport/reloadcerts", "_opendistro/_security/api/ssl/http/reloadcerts"} {
+		req, found := chatter.GetRequest(uri)
+		if !found {
+			t.Fatalf("Expected a request to %s", uri)
+		}
+		if req.Method != http.MethodPut {
+			t.Errorf("Expected method %s, got %s", http.MethodPut, req.Method)
+		}
+		if req.Address != "10.128.2.12" {
+			t.Errorf("Expected the request to be sent to the node, got address %q", req.Address)
+		}
+	}
+}
+
+func TestReloadCertificatesWhenRejected(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/ssl/transport/reloadcerts": {
+				{
+					Error:      nil,
+					StatusCode: 500,
+					Body:       `{"error": "New certificates should not expire before the current ones."}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.ReloadCertificates("10.128.2.12"); err == nil {
+		t.Fatal("Expected an error when the node rejects the certificates")
+	}
+	if _, found := chatter.GetRequest("_opendistro/_security/api/ssl/http/reloadcerts"); found {
+		t.Error("Expected not to reload the HTTP certificates after the transport certificates failed")
+	}
+}
+
+func TestGetCertificatesNotAfter(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/ssl/certs": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body: `{
+						"http_certificates_list": [{"subject_dn": "CN=elasticsearch", "not_after": "2026-11-02T10:00:00Z"}],
+						"transport_certificates_list": [{"subject_dn": "CN=elasticsearch", "not_after": "2026-10-20T08:30:00Z"}]
+					}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	notAfter, err := esClient.GetCertificatesNotAfter()
+	if err != nil {
+		t.Fatalf("Expected to get the certificates without error: %v", err)
+	}
+	expected := time.Date(2026, 10, 20, 8, 30, 0, 0, time.UTC)
+	if !notAfter.Equal(expected) {
+		t.Errorf("Expected the earliest expiry %v, got %v", expected, notAfter)
+	}
+}
+
+func TestGetSecurityRole(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/roles/team_a_read": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"team_a_read": {"readonly": false, "cluster": ["CLUSTER_COMPOSITE_OPS_RO"], "indices": {"app-team-a-*": {"*": ["READ"]}}}}`,
+				},
+			},
+			"_opendistro/_security/api/roles/missing": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{"status": "NOT_FOUND", "message": "missing not found."}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	role, err := esClient.GetSecurityRole("team_a_read")
+	if err != nil {
+		t.Fatalf("Expected to get the role without error: %v", err)
+	}
+	want := &estypes.SecurityRole{
+		Cluster: []string{"CLUSTER_COMPOSITE_OPS_RO"},
+		Indices: map[string]map[string][]string{"app-team-a-*": {"*": {"READ"}}},
+	}
+	if !reflect.DeepEqual(role, want) {
+		t.Errorf("Expected role %+v, got %+v", want, role)
+	}
+
+	role, err = esClient.GetSecurityRole("missing")
+	if err != nil || role != nil {
+		t.Errorf("Expected no role and no error for a missing role, got %+v, %v", role, err)
+	}
+}
+
+func TestPutSecurityRoleMapping(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/rolesmapping/team_a_read": {
+				{
+					Error:      nil,
+					StatusCode: 201,
+					Body:       `{"status": "CREATED", "message": "'team_a_read' created."}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	mapping := &estypes.SecurityRoleMapping{BackendRoles: []string{"team-a"}}
+	if err := esClient.PutSecurityRoleMapping("team_a_read", mapping); err != nil {
+		t.Fatalf("Expected to put the role mapping without error: %v", err)
+	}
+
+	req, found := chatter.GetRequest("_opendistro/_security/api/rolesmapping/team_a_read")
+	if !found {
+		t.Fatal("Expected a request to put the role mapping")
+	}
+	if req.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, req.Method)
+	}
+	if req.Body != `{"backendroles":["team-a"]}` {
+		t.Errorf("Expected the body to only carry the backend roles, got %s", req.Body)
+	}
+}
+
+func TestDeleteSecurityRoleWhenMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_opendistro/_security/api/roles/team_a_read": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{"status": "NOT_FOUND", "message": "team_a_read not found."}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.DeleteSecurityRole("team_a_read"); err != nil {
+		t.Errorf("Expected to ignore a missing role, got %v", err)
+	}
+}
@@ -1,10 +1,16 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
 
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
 )
 
 func (ec *esClient) ClearTransientShardAllocation() (bool, error) {
@@ -75,3 +81,161 @@ func (ec *esClient) GetShardAllocation() (string, error) {
 
 	return allocationString, payload.Error
 }
+
+// GetShardAllocationExclude returns the names of the nodes shards are moved away from
+func (ec *esClient) GetShardAllocationExclude() ([]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cluster/settings",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shard allocation exclusions",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	names := []string{}
+	value, ok := walkInterfaceMap("persistent.cluster.routing.allocation.exclude._name", payload.ResponseBody).(string)
+	if !ok || value == "" {
+		return names, nil
+	}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// SetShardAllocationExclude moves the shards away from the named nodes. An empty list
+// clears the exclusions
+func (ec *esClient) SetShardAllocationExclude(nodeNames []string) error {
+	value := "null"
+	if len(nodeNames) > 0 {
+		value = fmt.Sprintf("%q", strings.Join(nodeNames, ","))
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         "_cluster/settings",
+		RequestBody: fmt.Sprintf("{%q:{%q:%s}}", "persistent", "cluster.routing.allocation.exclude._name", value),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+
+	acknowledged := false
+	if acknowledgedBool, ok := payload.ResponseBody["acknowledged"].(bool); ok {
+		acknowledged = acknowledgedBool
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK || !acknowledged {
+		return ec.errorCtx().New("failed to set shard allocation exclusions",
+			"nodes", nodeNames,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+// GetNodeShardCounts returns the number of shards allocated to each node, including the
+// shards relocating away from it
+func (ec *esClient) GetNodeShardCounts() (map[string]int32, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/allocation?format=json&h=shards,node",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shard allocation",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	allocations := estypes.CatAllocationResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &allocations); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/allocation response body")
+	}
+
+	counts := map[string]int32{}
+	for _, allocation := range allocations {
+		shards, err := strconv.ParseInt(allocation.Shards, 10, 32)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse the shard count of node",
+				"node", allocation.Node)
+		}
+		counts[allocation.Node] = int32(shards)
+	}
+	return counts, nil
+}
+
+// GetIndexShards returns the copies of each shard of the index
+func (ec *esClient) GetIndexShards(index string) (estypes.CatShardsResponses, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_cat/shards/%s?format=json&h=shard,prirep,state,node", index),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index shards",
+			"index", index,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	shards := estypes.CatShardsResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &shards); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/shards response body",
+			"index", index)
+	}
+	return shards, nil
+}
+
+// GetNodeShards returns the shards allocated to each node as index/shard, including the
+// shards relocating to or away from it
+func (ec *esClient) GetNodeShards() (map[string][]string, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    "_cat/shards?format=json&h=index,shard,prirep,state,node",
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get shards",
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	shards := estypes.CatShardsResponses{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &shards); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse _cat/shards response body")
+	}
+
+	nodeShards := map[string][]string{}
+	for _, shard := range shards {
+		// the node of a relocating shard reads "source -> address id target"
+		fields := strings.Fields(shard.Node)
+		if len(fields) == 0 {
+			continue
+		}
+		shardNodes := []string{fields[0]}
+		if len(fields) > 2 && fields[1] == "->" {
+			shardNodes = append(shardNodes, fields[len(fields)-1])
+		}
+		for _, node := range shardNodes {
+			nodeShards[node] = append(nodeShards[node], fmt.Sprintf("%s/%s", shard.Index, shard.Shard))
+		}
+	}
+	return nodeShards, nil
+}
@@ -0,0 +1,134 @@
+package elasticsearch_test
+
+import (
+	"reflect"
+	"testing"
+
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestGetShardAllocationExclude(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_cluster/settings": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "node-1, node-2"}}}}}, "transient": {}}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	names, err := esClient.GetShardAllocationExclude()
+	if err != nil {
+		t.Fatalf("Expected to get the exclusions without error: %v", err)
+	}
+	expected := []string{"node-1", "node-2"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected exclusions %v, got %v", expected, names)
+	}
+}
+
+func TestSetShardAllocationExclude(t *testing.T) {
+	tests := []struct {
+		desc      string
+		nodeNames []string
+		want      string
+	}{
+		{
+			desc:      "exclude nodes",
+			nodeNames: []string{"node-1", "node-2"},
+			want:      `{"persistent":{"cluster.routing.allocation.exclude._name":"node-1,node-2"}}`,
+		},
+		{
+			desc:      "clear exclusions",
+			nodeNames: nil,
+			want:      `{"persistent":{"cluster.routing.allocation.exclude._name":null}}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			chatter := testhelpers.NewFakeElasticsearchChatter(
+				map[string]testhelpers.FakeElasticsearchResponses{
+					"_cluster/settings": {
+						{
+							Error:      nil,
+							StatusCode: 200,
+							Body:       `{"acknowledged": true}`,
+						},
+					},
+				},
+			)
+			esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+			if err := esClient.SetShardAllocationExclude(test.nodeNames); err != nil {
+				t.Fatalf("Expected to set the exclusions without error: %v", err)
+			}
+			req, _ := chatter.GetRequest("_cluster/settings")
+			if req.Body != test.want {
+				t.Errorf("Expected request body %s, got %s", test.want, req.Body)
+			}
+		})
+	}
+}
+
+func TestGetNodeShardCounts(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_cat/allocation?format=json&h=shards,node": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `[{"shards": "5", "node": "node-1"}, {"shards": "0", "node": "node-2"}, {"shards": "2", "node": "UNASSIGNED"}]`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	counts, err := esClient.GetNodeShardCounts()
+	if err != nil {
+		t.Fatalf("Expected to get the shard counts without error: %v", err)
+	}
+	expected := map[string]int32{"node-1": 5, "node-2": 0, "UNASSIGNED": 2}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected shard counts %v, got %v", expected, counts)
+	}
+}
+
+func TestGetNodeShards(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body: `[
+						{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "node-1"},
+						{"index": "app-000001", "shard": "0", "prirep": "r", "state": "RELOCATING", "node": "node-2 -> 10.128.2.12 Ab3d node-3"},
+						{"index": "app-000001", "shard": "1", "prirep": "p", "state": "STARTED", "node": "node-2"},
+						{"index": "app-000001", "shard": "1", "prirep": "r", "state": "UNASSIGNED", "node": null}
+					]`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	shards, err := esClient.GetNodeShards()
+	if err != nil {
+		t.Fatalf("Expected to get the shards without error: %v", err)
+	}
+	expected := map[string][]string{
+		"node-1": {"app-000001/0"},
+		"node-2": {"app-000001/0", "app-000001/1"},
+		"node-3": {"app-000001/0"},
+	}
+	if !reflect.DeepEqual(shards, expected) {
+		t.Errorf("Expected shards %v, got %v", expected, shards)
+	}
+}
@@ -0,0 +1,234 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+)
+
+// CreateSnapshotRepository registers the repository. Elasticsearch verifies every node can
+// access the repository before acknowledging the request
+func (ec *esClient) CreateSnapshotRepository(name string, repository *estypes.SnapshotRepository) error {
+	body, err := utils.ToJSON(repository)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_snapshot/%s", name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to create snapshot repository",
+			"repository", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+// GetSnapshotRepository returns the registered repository or nil if it does not exist
+func (ec *esClient) GetSnapshotRepository(name string) (*estypes.SnapshotRepository, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s", name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshot repository",
+			"repository", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	repositories := map[string]estypes.SnapshotRepository{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &repositories); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode snapshot repository",
+			"repository", name)
+	}
+	repository, ok := repositories[name]
+	if !ok {
+		return nil, nil
+	}
+	return &repository, nil
+}
+
+// GetSnapshots returns all snapshots in the repository
+func (ec *esClient) GetSnapshots(repository string) ([]estypes.Snapshot, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s/_all", repository),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshots",
+			"repository", repository,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	response := estypes.SnapshotsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &response); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode snapshots",
+			"repository", repository)
+	}
+	return response.Snapshots, nil
+}
+
+// GetSnapshot returns the snapshot or nil if it does not exist in the repository
+func (ec *esClient) GetSnapshot(repository, name string) (*estypes.Snapshot, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_snapshot/%s/%s", repository, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	response := estypes.SnapshotsResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &response); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode snapshot",
+			"repository", repository,
+			"snapshot", name)
+	}
+	for i := range response.Snapshots {
+		if response.Snapshots[i].Snapshot == name {
+			return &response.Snapshots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateSnapshot takes the snapshot and waits for it to complete. The returned snapshot
+// holds the state the snapshot completed with
+func (ec *esClient) CreateSnapshot(repository, name string, request *estypes.SnapshotRequest) (*estypes.Snapshot, error) {
+	body, err := utils.ToJSON(request)
+	if err != nil {
+		return nil, err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_snapshot/%s/%s?wait_for_completion=true", repository, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to create snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	response := estypes.SnapshotResponse{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &response); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode snapshot",
+			"repository", repository,
+			"snapshot", name)
+	}
+	return &response.Snapshot, nil
+}
+
+// DeleteSnapshot deletes the snapshot. Missing snapshots are ignored
+func (ec *esClient) DeleteSnapshot(repository, name string) error {
+	payload := &EsRequest{
+		Method: http.MethodDelete,
+		URI:    fmt.Sprintf("_snapshot/%s/%s", repository, name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error == nil && (payload.StatusCode == http.StatusOK || payload.StatusCode == http.StatusNotFound) {
+		return nil
+	}
+
+	return ec.errorCtx().New("failed to delete snapshot",
+		"repository", repository,
+		"snapshot", name,
+		"response_status", payload.StatusCode,
+		"response_body", payload.ResponseBody,
+		"response_error", payload.Error,
+	)
+}
+
+// RestoreSnapshot starts restoring the snapshot without waiting for the restore to complete.
+// Use GetIndicesRecovery to follow the progress
+func (ec *esClient) RestoreSnapshot(repository, name string, request *estypes.SnapshotRestoreRequest) error {
+	body, err := utils.ToJSON(request)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPost,
+		URI:         fmt.Sprintf("_snapshot/%s/%s/_restore", repository, name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return ec.errorCtx().New("failed to restore snapshot",
+			"repository", repository,
+			"snapshot", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
+// GetIndicesRecovery returns the shard recoveries of the indices matching the pattern
+func (ec *esClient) GetIndicesRecovery(pattern string) (map[string]estypes.IndexRecovery, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("%s/_recovery", pattern),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get indices recovery",
+			"pattern", pattern,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	recoveries := map[string]estypes.IndexRecovery{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &recoveries); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode indices recovery",
+			"pattern", pattern)
+	}
+	return recoveries, nil
+}
@@ -0,0 +1,200 @@
+package elasticsearch_test
+
+import (
+	"testing"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	testhelpers "github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func TestCreateSnapshotRepository(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	repository := &estypes.SnapshotRepository{
+		Type:     "fs",
+		Settings: map[string]string{"location": "/elasticsearch/snapshots"},
+	}
+	if err := esClient.CreateSnapshotRepository("backups", repository); err != nil {
+		t.Fatalf("Expected to create the repository without error: %v", err)
+	}
+
+	req, found := chatter.GetRequest("_snapshot/backups")
+	if !found {
+		t.Fatal("Expected a request to register the repository")
+	}
+	expected := `{"type":"fs","settings":{"location":"/elasticsearch/snapshots"}}`
+	if req.Body != expected {
+		t.Errorf("Expected request body %s, got %s", expected, req.Body)
+	}
+}
+
+func TestGetSnapshotRepositoryWhenMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{"error": {"type": "repository_missing_exception"}, "status": 404}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	repository, err := esClient.GetSnapshotRepository("backups")
+	if err != nil {
+		t.Fatalf("Expected no error for a missing repository: %v", err)
+	}
+	if repository != nil {
+		t.Errorf("Expected no repository, got %v", repository)
+	}
+}
+
+func TestGetSnapshots(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups/_all": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body: `{"snapshots": [
+                      {"snapshot": "scheduled-1", "state": "SUCCESS", "indices": ["app-000001"], "start_time_in_millis": 1600000000000, "end_time_in_millis": 1600000060000},
+                      {"snapshot": "scheduled-2", "state": "FAILED", "reason": "boom", "start_time_in_millis": 1600000100000, "end_time_in_millis": 1600000160000}
+                    ]}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	snapshots, err := esClient.GetSnapshots("backups")
+	if err != nil {
+		t.Fatalf("Expected to get snapshots without error: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots, got %v", snapshots)
+	}
+	if snapshots[1].State != "FAILED" || snapshots[1].Reason != "boom" || snapshots[1].EndTimeInMillis != 1600000160000 {
+		t.Errorf("Expected the failed snapshot to be decoded, got %v", snapshots[1])
+	}
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups/scheduled-1?wait_for_completion=true": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"snapshot": {"snapshot": "scheduled-1", "state": "PARTIAL", "indices": ["app-000001"]}}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	snapshot, err := esClient.CreateSnapshot("backups", "scheduled-1", &estypes.SnapshotRequest{Indices: "app-*"})
+	if err != nil {
+		t.Fatalf("Expected to create the snapshot without error: %v", err)
+	}
+	if snapshot.Snapshot != "scheduled-1" || snapshot.State != "PARTIAL" {
+		t.Errorf("Expected the completed snapshot to be decoded, got %v", snapshot)
+	}
+
+	req, found := chatter.GetRequest("_snapshot/backups/scheduled-1?wait_for_completion=true")
+	if !found {
+		t.Fatal("Expected a request to create the snapshot")
+	}
+	expected := `{"indices":"app-*","include_global_state":false}`
+	if req.Body != expected {
+		t.Errorf("Expected request body %s, got %s", expected, req.Body)
+	}
+}
+
+func TestDeleteSnapshotWhenMissing(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups/scheduled-1": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{"error": {"type": "snapshot_missing_exception"}, "status": 404}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	if err := esClient.DeleteSnapshot("backups", "scheduled-1"); err != nil {
+		t.Errorf("Expected no error for a missing snapshot: %v", err)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_snapshot/backups/scheduled-1/_restore": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"accepted": true}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	request := &estypes.SnapshotRestoreRequest{
+		Indices:           "app-000001",
+		RenamePattern:     "^(app-000001)$",
+		RenameReplacement: "restored-$1",
+	}
+	if err := esClient.RestoreSnapshot("backups", "scheduled-1", request); err != nil {
+		t.Fatalf("Expected to restore the snapshot without error: %v", err)
+	}
+
+	req, found := chatter.GetRequest("_snapshot/backups/scheduled-1/_restore")
+	if !found {
+		t.Fatal("Expected a request to restore the snapshot")
+	}
+	expected := `{"indices":"app-000001","ignore_unavailable":false,"include_global_state":false,"include_aliases":false,"rename_pattern":"^(app-000001)$","rename_replacement":"restored-$1"}`
+	if req.Body != expected {
+		t.Errorf("Expected request body %s, got %s", expected, req.Body)
+	}
+}
+
+func TestGetIndicesRecovery(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"app-000001/_recovery": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"app-000001": {"shards": [{"id": 0, "type": "SNAPSHOT", "stage": "INDEX"}]}}`,
+				},
+			},
+		},
+	)
+	esClient := testhelpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", fakeClient, chatter)
+
+	recoveries, err := esClient.GetIndicesRecovery("app-000001")
+	if err != nil {
+		t.Fatalf("Expected to get the recovery without error: %v", err)
+	}
+	shards := recoveries["app-000001"].Shards
+	if len(shards) != 1 || shards[0].Type != "SNAPSHOT" || shards[0].Stage != "INDEX" {
+		t.Errorf("Expected the shard recovery to be decoded, got %v", recoveries)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
@@ -92,6 +93,58 @@ func (ec *esClient) GetIndexTemplates() (map[string]estypes.GetIndexTemplate, er
 	return templates, payload.Error
 }
 
+// GetRawIndexTemplate returns the template with flat settings or nil if it does not exist
+func (ec *esClient) GetRawIndexTemplate(name string) (*estypes.RawIndexTemplate, error) {
+	payload := &EsRequest{
+		Method: http.MethodGet,
+		URI:    fmt.Sprintf("_template/%s?flat_settings=true", name),
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		return nil, ec.errorCtx().New("failed to get index template",
+			"template", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+
+	templates := map[string]*estypes.RawIndexTemplate{}
+	if err := json.Unmarshal([]byte(payload.RawResponseBody), &templates); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode index template",
+			"template", name)
+	}
+	return templates[name], nil
+}
+
+// PutRawIndexTemplate creates or replaces the template
+func (ec *esClient) PutRawIndexTemplate(name string, template *estypes.RawIndexTemplate) error {
+	body, err := utils.ToJSON(template)
+	if err != nil {
+		return err
+	}
+	payload := &EsRequest{
+		Method:      http.MethodPut,
+		URI:         fmt.Sprintf("_template/%s", name),
+		RequestBody: body,
+	}
+
+	ec.fnSendEsRequest(ec.cluster, ec.namespace, payload, ec.k8sClient)
+	if payload.Error != nil || (payload.StatusCode != http.StatusOK && payload.StatusCode != http.StatusCreated) {
+		return ec.errorCtx().New("failed to put index template",
+			"template", name,
+			"response_status", payload.StatusCode,
+			"response_body", payload.ResponseBody,
+			"response_error", payload.Error,
+		)
+	}
+	return nil
+}
+
 func (ec *esClient) updateAllIndexTemplateReplicas(replicaCount int32) (bool, error) {
 	// get the index template and then update the replica and put it
 	indexTemplates, err := ec.GetIndexTemplates()
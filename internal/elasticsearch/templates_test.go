@@ -2,6 +2,7 @@ package elasticsearch_test
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/ViaQ/logerr/kverrors"
@@ -88,3 +89,76 @@ func TestCreateIndexTemplateWhenResponse201(t *testing.T) {
 		t.Errorf("Exp. to not return an error %v", err)
 	}
 }
+
+func TestGetRawIndexTemplate(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template/team-a?flat_settings=true": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"team-a": {"order": 10, "index_patterns": ["app-team-a-*"], "settings": {"index.refresh_interval": "30s"}, "mappings": {}, "aliases": {}}}`,
+				},
+			},
+			"_template/missing?flat_settings=true": {
+				{
+					Error:      nil,
+					StatusCode: 404,
+					Body:       `{}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	template, err := esClient.GetRawIndexTemplate("team-a")
+	if err != nil {
+		t.Fatalf("Expected to get the template without error: %v", err)
+	}
+	want := &estypes.RawIndexTemplate{
+		Order:         10,
+		IndexPatterns: []string{"app-team-a-*"},
+		Settings:      map[string]interface{}{"index.refresh_interval": "30s"},
+		Mappings:      map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(template, want) {
+		t.Errorf("Expected template %+v, got %+v", want, template)
+	}
+
+	template, err = esClient.GetRawIndexTemplate("missing")
+	if err != nil || template != nil {
+		t.Errorf("Expected no template and no error for a missing template, got %+v, %v", template, err)
+	}
+}
+
+func TestPutRawIndexTemplate(t *testing.T) {
+	chatter := testhelpers.NewFakeElasticsearchChatter(
+		map[string]testhelpers.FakeElasticsearchResponses{
+			"_template/team-a": {
+				{
+					Error:      nil,
+					StatusCode: 200,
+					Body:       `{"acknowledged": true}`,
+				},
+			},
+		})
+	esClient := testhelpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+
+	template := &estypes.RawIndexTemplate{
+		IndexPatterns: []string{"app-team-a-*"},
+		Mappings:      map[string]interface{}{"_doc": map[string]interface{}{"properties": map[string]interface{}{"team": map[string]interface{}{"type": "keyword"}}}},
+	}
+	if err := esClient.PutRawIndexTemplate("team-a", template); err != nil {
+		t.Fatalf("Expected to put the template without error: %v", err)
+	}
+
+	req, found := chatter.GetRequest("_template/team-a")
+	if !found {
+		t.Fatal("Expected a request to put the template")
+	}
+	if req.Method != http.MethodPut {
+		t.Errorf("Expected method %s, got %s", http.MethodPut, req.Method)
+	}
+	if req.Body != `{"order":0,"index_patterns":["app-team-a-*"],"mappings":{"_doc":{"properties":{"team":{"type":"keyword"}}}}}` {
+		t.Errorf("Expected the body to carry the order, patterns and mappings, got %s", req.Body)
+	}
+}
@@ -3,6 +3,8 @@ package indexmanagement
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
@@ -13,15 +15,21 @@ func calculateConditions(policy apis.IndexManagementPolicySpec, primaryShards in
 	// 40GB = 40960 1K messages
 	maxDoc := constants.TheoreticalShardMaxSizeInMB * 1000 * primaryShards
 	maxSize := defaultShardSize * primaryShards
-	maxAge := ""
-	if policy.Phases.Hot != nil && policy.Phases.Hot.Actions.Rollover != nil {
-		maxAge = string(policy.Phases.Hot.Actions.Rollover.MaxAge)
-	}
-	return rolloverConditions{
+	conditions := rolloverConditions{
 		MaxSize: fmt.Sprintf("%dgb", maxSize),
 		MaxDocs: maxDoc,
-		MaxAge:  maxAge,
 	}
+	if policy.Phases.Hot != nil && policy.Phases.Hot.Actions.Rollover != nil {
+		rollover := policy.Phases.Hot.Actions.Rollover
+		conditions.MaxAge = string(rollover.MaxAge)
+		if rollover.MaxSize != "" {
+			conditions.MaxSize = strings.ToLower(rollover.MaxSize)
+		}
+		if rollover.MaxDocs != nil {
+			conditions.MaxDocs = *rollover.MaxDocs
+		}
+	}
+	return conditions
 }
 
 func calculateMillisForTimeUnit(timeunit apis.TimeUnit) (uint64, error) {
@@ -62,3 +70,44 @@ func crontabScheduleFor(timeunit apis.TimeUnit) (string, error) {
 
 	return "", kverrors.New("crontab schedule for time unit is unsupported", "timeunit", match[2])
 }
+
+// CalculatePhaseStatus counts the indices in each phase of the policy by comparing
+// their age to the minimum age of the phases
+func CalculatePhaseStatus(policy apis.IndexManagementPolicySpec, creationDates map[string]time.Time, now time.Time) []apis.IndexManagementPhaseStatus {
+	phases := []apis.IndexManagementPhaseStatus{
+		{Name: apis.IndexManagementPhaseHot},
+	}
+	minAges := []uint64{0}
+	appendPhase := func(name apis.IndexManagementPhaseName, minAge apis.TimeUnit) {
+		millis, err := calculateMillisForTimeUnit(minAge)
+		if err != nil {
+			return
+		}
+		phases = append(phases, apis.IndexManagementPhaseStatus{Name: name, MinAge: minAge})
+		minAges = append(minAges, millis)
+	}
+	if policy.Phases.Warm != nil {
+		appendPhase(apis.IndexManagementPhaseWarm, policy.Phases.Warm.MinAge)
+	}
+	if policy.Phases.Cold != nil {
+		appendPhase(apis.IndexManagementPhaseCold, policy.Phases.Cold.MinAge)
+	}
+	if policy.Phases.Delete != nil {
+		appendPhase(apis.IndexManagementPhaseDelete, policy.Phases.Delete.MinAge)
+	}
+
+	for _, created := range creationDates {
+		age := uint64(0)
+		if now.After(created) {
+			age = uint64(now.Sub(created) / time.Millisecond)
+		}
+		// phases are validated to be in order of their minimum age
+		for i := len(phases) - 1; i >= 0; i-- {
+			if age >= minAges[i] {
+				phases[i].Indices++
+				break
+			}
+		}
+	}
+	return phases
+}
@@ -2,6 +2,7 @@ package indexmanagement
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -72,6 +73,74 @@ var _ = Describe("Index Management", func() {
 				Expect(conditions.MaxAge).To(Equal(""))
 			})
 		})
+		Context("a user defined strategy", func() {
+			It("should restrict the index to the size and docs defined by policy management", func() {
+				maxDocs := int32(1000)
+				policy := apis.IndexManagementPolicySpec{
+					Phases: apis.IndexManagementPhasesSpec{
+						Hot: &apis.IndexManagementHotPhaseSpec{
+							Actions: apis.IndexManagementActionsSpec{
+								Rollover: &apis.IndexManagementActionSpec{
+									MaxAge:  "3d",
+									MaxSize: "10GB",
+									MaxDocs: &maxDocs,
+								},
+							},
+						},
+					},
+				}
+				Expect(calculateConditions(policy, 3)).To(Equal(rolloverConditions{
+					MaxAge:  "3d",
+					MaxSize: "10gb",
+					MaxDocs: 1000,
+				}))
+			})
+		})
+	})
+
+	Describe("#CalculatePhaseStatus", func() {
+		var (
+			policy apis.IndexManagementPolicySpec
+			now    = time.Now()
+			dates  map[string]time.Time
+		)
+		BeforeEach(func() {
+			policy = apis.IndexManagementPolicySpec{
+				Phases: apis.IndexManagementPhasesSpec{
+					Hot:    &apis.IndexManagementHotPhaseSpec{},
+					Warm:   &apis.IndexManagementWarmPhaseSpec{MinAge: "2d"},
+					Cold:   &apis.IndexManagementColdPhaseSpec{MinAge: "5d"},
+					Delete: &apis.IndexManagementDeletePhaseSpec{MinAge: "7d"},
+				},
+			}
+			dates = map[string]time.Time{
+				"app-000001": now.Add(-8 * 24 * time.Hour),
+				"app-000002": now.Add(-6 * 24 * time.Hour),
+				"app-000003": now.Add(-3 * 24 * time.Hour),
+				"app-000004": now.Add(-2 * 24 * time.Hour),
+				"app-000005": now.Add(-1 * time.Hour),
+			}
+		})
+		It("should count the indices in each phase by their age", func() {
+			Expect(CalculatePhaseStatus(policy, dates, now)).To(Equal([]apis.IndexManagementPhaseStatus{
+				{Name: apis.IndexManagementPhaseHot, Indices: 1},
+				{Name: apis.IndexManagementPhaseWarm, MinAge: "2d", Indices: 2},
+				{Name: apis.IndexManagementPhaseCold, MinAge: "5d", Indices: 1},
+				{Name: apis.IndexManagementPhaseDelete, MinAge: "7d", Indices: 1},
+			}))
+		})
+		It("should only report the phases defined by the policy", func() {
+			policy.Phases.Warm = nil
+			policy.Phases.Delete = nil
+			Expect(CalculatePhaseStatus(policy, dates, now)).To(Equal([]apis.IndexManagementPhaseStatus{
+				{Name: apis.IndexManagementPhaseHot, Indices: 3},
+				{Name: apis.IndexManagementPhaseCold, MinAge: "5d", Indices: 2},
+			}))
+		})
+		It("should count indices created in the future as hot", func() {
+			dates = map[string]time.Time{"app-000001": now.Add(time.Hour)}
+			Expect(CalculatePhaseStatus(policy, dates, now)[0].Indices).To(BeEquivalentTo(1))
+		})
 	})
 
 	Describe("#calculateMillisForTimeUnit", func() {
@@ -0,0 +1,128 @@
+package job
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// Config is the index management policy of a policy mapping as passed to the job
+// by the environment of the cronjob
+type Config struct {
+	// PolicyMapping is the prefix of the write aliases managed by the job
+	PolicyMapping string
+	// DeleteMinAge is the age after which indices are deleted
+	DeleteMinAge time.Duration
+	// RolloverConditions are the conditions to roll the write aliases over
+	RolloverConditions *estypes.RolloverConditions
+	Warm               *PhaseConfig
+	Cold               *PhaseConfig
+}
+
+// PhaseConfig holds the actions applied to the indices of the warm or cold phase
+type PhaseConfig struct {
+	MinAge time.Duration
+	// MaxAge is the age after which indices are left to the next phase. Zero has no upper bound
+	MaxAge                   time.Duration
+	NumberOfReplicas         *int32
+	ReadOnly                 bool
+	ShrinkNumberOfShards     int32
+	ForceMergeMaxNumSegments int32
+	AllocationRequire        map[string]string
+}
+
+// NewConfigFromEnv reads the configuration of the phases from the environment. A phase
+// is left unset when its env vars are missing
+func NewConfigFromEnv(getenv func(string) string) (*Config, error) {
+	config := &Config{
+		PolicyMapping: getenv("POLICY_MAPPING"),
+	}
+	if config.PolicyMapping == "" {
+		return nil, kverrors.New("missing the policy mapping", "env", "POLICY_MAPPING")
+	}
+
+	var err error
+	if value := getenv("MIN_AGE"); value != "" {
+		if config.DeleteMinAge, err = parseMillis("MIN_AGE", value); err != nil {
+			return nil, err
+		}
+	}
+
+	if value := getenv("ROLLOVER_CONDITIONS"); value != "" {
+		conditions := &estypes.RolloverConditions{}
+		if err := json.Unmarshal([]byte(value), conditions); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse the rollover conditions", "env", "ROLLOVER_CONDITIONS")
+		}
+		config.RolloverConditions = conditions
+	}
+
+	if config.Warm, err = newPhaseConfigFromEnv("WARM", getenv); err != nil {
+		return nil, err
+	}
+	if config.Cold, err = newPhaseConfigFromEnv("COLD", getenv); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func newPhaseConfigFromEnv(prefix string, getenv func(string) string) (*PhaseConfig, error) {
+	minAge := getenv(prefix + "_MIN_AGE")
+	if minAge == "" {
+		return nil, nil
+	}
+
+	var err error
+	phase := &PhaseConfig{}
+	if phase.MinAge, err = parseMillis(prefix+"_MIN_AGE", minAge); err != nil {
+		return nil, err
+	}
+	if value := getenv(prefix + "_MAX_AGE"); value != "" {
+		if phase.MaxAge, err = parseMillis(prefix+"_MAX_AGE", value); err != nil {
+			return nil, err
+		}
+	}
+	if value := getenv(prefix + "_NUMBER_OF_REPLICAS"); value != "" {
+		replicas, err := parseInt32(prefix+"_NUMBER_OF_REPLICAS", value)
+		if err != nil {
+			return nil, err
+		}
+		phase.NumberOfReplicas = &replicas
+	}
+	phase.ReadOnly = getenv(prefix+"_READ_ONLY") == "true"
+	if value := getenv(prefix + "_SHRINK_NUMBER_OF_SHARDS"); value != "" {
+		if phase.ShrinkNumberOfShards, err = parseInt32(prefix+"_SHRINK_NUMBER_OF_SHARDS", value); err != nil {
+			return nil, err
+		}
+	}
+	if value := getenv(prefix + "_FORCE_MERGE_MAX_NUM_SEGMENTS"); value != "" {
+		if phase.ForceMergeMaxNumSegments, err = parseInt32(prefix+"_FORCE_MERGE_MAX_NUM_SEGMENTS", value); err != nil {
+			return nil, err
+		}
+	}
+	if value := getenv(prefix + "_ALLOCATION_REQUIRE"); value != "" {
+		if err := json.Unmarshal([]byte(value), &phase.AllocationRequire); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse the allocation", "env", prefix+"_ALLOCATION_REQUIRE")
+		}
+	}
+	return phase, nil
+}
+
+func parseMillis(name, value string) (time.Duration, error) {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, kverrors.Wrap(err, "failed to parse the age in milliseconds", "env", name)
+	}
+	return time.Duration(millis) * time.Millisecond, nil
+}
+
+func parseInt32(name, value string) (int32, error) {
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, kverrors.Wrap(err, "failed to parse number", "env", name)
+	}
+	return int32(i), nil
+}
@@ -0,0 +1,39 @@
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+// deleteBatchSize limits the number of indices deleted by a single request
+const deleteBatchSize = 25
+
+// delete removes the indices of the alias older than the minimum age of the delete phase
+func (im *indexManager) delete(alias string, result *Result) error {
+	writeIndex, err := im.getWriteIndex(alias)
+	if err != nil {
+		return err
+	}
+
+	indices, err := im.getIndicesCreatedBetween(alias, writeIndex, time.Time{}, im.now.Add(-im.config.DeleteMinAge))
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		result.Message = "No indices to delete"
+		return nil
+	}
+
+	for start := 0; start < len(indices); start += deleteBatchSize {
+		end := start + deleteBatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+		if err := im.esClient.DeleteIndices(indices[start:end]...); err != nil {
+			return err
+		}
+		result.Indices = append(result.Indices, indices[start:end]...)
+	}
+	result.Message = fmt.Sprintf("Deleted %d indices", len(result.Indices))
+	return nil
+}
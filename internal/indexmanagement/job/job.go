@@ -0,0 +1,177 @@
+package job
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// Command is the subcommand of the operator binary running the index management job
+const Command = "indexmanagement"
+
+// The phases the job can run, in the order the cronjobs run them
+const (
+	PhaseDelete   = "delete"
+	PhaseCold     = "cold"
+	PhaseWarm     = "warm"
+	PhaseRollover = "rollover"
+)
+
+const writeAliasSuffix = "-write"
+
+// Result is the outcome of running a phase for one alias of the policy mapping
+type Result struct {
+	Phase string `json:"phase"`
+	Alias string `json:"alias,omitempty"`
+	// Indices are the indices the phase acted on
+	Indices []string `json:"indices,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Failed returns true if the phase did not complete
+func (r Result) Failed() bool {
+	return r.Error != ""
+}
+
+type indexManager struct {
+	esClient elasticsearch.Client
+	config   *Config
+	now      time.Time
+}
+
+// Run runs the phases for each write alias of the policy mapping. A failing alias does not
+// keep the phases from running for the other aliases
+func Run(esClient elasticsearch.Client, config *Config, phases []string, now time.Time) []Result {
+	im := &indexManager{esClient: esClient, config: config, now: now}
+
+	aliases, err := im.listAliases()
+	if err != nil {
+		results := make([]Result, len(phases))
+		for i, phase := range phases {
+			results[i] = Result{Phase: phase, Error: err.Error()}
+		}
+		return results
+	}
+
+	results := []Result{}
+	for _, phase := range phases {
+		for _, alias := range aliases {
+			result := Result{Phase: phase, Alias: alias}
+			if err := im.runPhase(phase, alias, &result); err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func (im *indexManager) runPhase(phase, alias string, result *Result) error {
+	switch phase {
+	case PhaseDelete:
+		if im.config.DeleteMinAge == 0 {
+			return kverrors.New("the delete phase is not configured")
+		}
+		return im.delete(alias, result)
+	case PhaseRollover:
+		if im.config.RolloverConditions == nil {
+			return kverrors.New("the rollover phase is not configured")
+		}
+		return im.rollover(alias, result)
+	case PhaseWarm:
+		if im.config.Warm == nil {
+			return kverrors.New("the warm phase is not configured")
+		}
+		return im.managePhase(alias, im.config.Warm, result)
+	case PhaseCold:
+		if im.config.Cold == nil {
+			return kverrors.New("the cold phase is not configured")
+		}
+		return im.managePhase(alias, im.config.Cold, result)
+	default:
+		return kverrors.New("unknown phase", "phase", phase)
+	}
+}
+
+// listAliases returns the aliases of the policy mapping, e.g. app for app-write
+func (im *indexManager) listAliases() ([]string, error) {
+	indices, err := im.esClient.GetAliases(im.config.PolicyMapping + "*" + writeAliasSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := map[string]bool{}
+	for _, aliases := range indices {
+		for alias := range aliases {
+			if strings.HasSuffix(alias, writeAliasSuffix) {
+				unique[strings.TrimSuffix(alias, writeAliasSuffix)] = true
+			}
+		}
+	}
+
+	aliases := make([]string, 0, len(unique))
+	for alias := range unique {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases, nil
+}
+
+// getWriteIndex returns the write index of the alias. Extra write indices left by an
+// interrupted rollover stop being write indices, keeping the latest one
+func (im *indexManager) getWriteIndex(alias string) (string, error) {
+	writeAlias := alias + writeAliasSuffix
+	indices, err := im.esClient.GetAliases(writeAlias)
+	if err != nil {
+		return "", err
+	}
+
+	writeIndices := []string{}
+	for index, aliases := range indices {
+		if aliases[writeAlias].IsWriteIndex {
+			writeIndices = append(writeIndices, index)
+		}
+	}
+	if len(writeIndices) == 0 {
+		return "", kverrors.New("unable to find the write index", "alias", writeAlias)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(writeIndices)))
+
+	isWriteIndex := false
+	for _, index := range writeIndices[1:] {
+		actions := estypes.AliasActions{
+			Actions: []estypes.AliasAction{
+				{Add: &estypes.AddAliasAction{Index: index, Alias: writeAlias, IsWriteIndex: &isWriteIndex}},
+			},
+		}
+		if err := im.esClient.UpdateAlias(actions); err != nil {
+			return "", err
+		}
+	}
+	return writeIndices[0], nil
+}
+
+// getIndicesCreatedBetween returns the sorted indices of the alias besides the write index
+// created in [from, to). A zero from has no lower bound
+func (im *indexManager) getIndicesCreatedBetween(alias, writeIndex string, from, to time.Time) ([]string, error) {
+	dates, err := im.esClient.GetIndicesCreationDate(alias + writeAliasSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := []string{}
+	for index, created := range dates {
+		if index == writeIndex || !created.Before(to) || created.Before(from) {
+			continue
+		}
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return indices, nil
+}
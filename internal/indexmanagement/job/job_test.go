@@ -0,0 +1,410 @@
+package job
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+var now = time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+// fakeElasticsearch serves the responses keyed by "METHOD URI" and records the requests
+type fakeElasticsearch struct {
+	sync.Mutex
+	responses map[string]fakeResponse
+	requests  []string
+	bodies    map[string]string
+}
+
+func newFakeElasticsearch(t *testing.T, responses map[string]fakeResponse) (*fakeElasticsearch, elasticsearch.Client) {
+	fake := &fakeElasticsearch{responses: responses, bodies: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	esClient := elasticsearch.NewClient("elasticsearch", "openshift-logging", nil)
+	esClient.SetSendRequestFn(elasticsearch.NewServiceSendRequestFn(server.URL, server.Client()))
+	return fake, esClient
+}
+
+func (f *fakeElasticsearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	key := fmt.Sprintf("%s %s", r.Method, strings.TrimPrefix(r.URL.RequestURI(), "/"))
+	body, _ := ioutil.ReadAll(r.Body)
+	f.requests = append(f.requests, key)
+	f.bodies[key] = string(body)
+
+	response, ok := f.responses[key]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "not found", "status": 404}`))
+		return
+	}
+	w.WriteHeader(response.status)
+	_, _ = w.Write([]byte(response.body))
+}
+
+func (f *fakeElasticsearch) received(key string) bool {
+	for _, request := range f.requests {
+		if request == key {
+			return true
+		}
+	}
+	return false
+}
+
+func creationDate(age time.Duration) string {
+	return fmt.Sprintf(`{"settings": {"index": {"creation_date": "%d"}}}`, now.Add(-age).UnixNano()/int64(time.Millisecond))
+}
+
+var writeAliasResponses = map[string]fakeResponse{
+	"GET _alias/app*-write": {
+		status: 200,
+		body:   `{"app-000001": {"aliases": {"app-write": {}}}, "app-000002": {"aliases": {"app-write": {"is_write_index": true}}}}`,
+	},
+	"GET _alias/app-write": {
+		status: 200,
+		body:   `{"app-000001": {"aliases": {"app-write": {}}}, "app-000002": {"aliases": {"app-write": {"is_write_index": true}}}}`,
+	},
+}
+
+func withResponses(responses map[string]fakeResponse) map[string]fakeResponse {
+	all := map[string]fakeResponse{}
+	for key, response := range writeAliasResponses {
+		all[key] = response
+	}
+	for key, response := range responses {
+		all[key] = response
+	}
+	return all
+}
+
+func TestRunDelete(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"GET app-write/_settings/index.creation_date": {
+			status: 200,
+			body:   fmt.Sprintf(`{"app-000001": %s, "app-000002": %s}`, creationDate(10*24*time.Hour), creationDate(10*24*time.Hour)),
+		},
+		"DELETE app-000001?ignore_unavailable=true": {status: 200, body: `{"acknowledged": true}`},
+	}))
+	config := &Config{PolicyMapping: "app", DeleteMinAge: 7 * 24 * time.Hour}
+
+	results := Run(esClient, config, []string{PhaseDelete}, now)
+
+	expected := []Result{{Phase: PhaseDelete, Alias: "app", Indices: []string{"app-000001"}, Message: "Deleted 1 indices"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+	if fake.received("DELETE app-000002?ignore_unavailable=true") {
+		t.Error("Expected the write index to be kept")
+	}
+}
+
+func TestRunDeleteWithoutIndicesToDelete(t *testing.T) {
+	_, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"GET app-write/_settings/index.creation_date": {
+			status: 200,
+			body:   fmt.Sprintf(`{"app-000001": %s, "app-000002": %s}`, creationDate(time.Hour), creationDate(time.Minute)),
+		},
+	}))
+	config := &Config{PolicyMapping: "app", DeleteMinAge: 7 * 24 * time.Hour}
+
+	results := Run(esClient, config, []string{PhaseDelete}, now)
+
+	expected := []Result{{Phase: PhaseDelete, Alias: "app", Message: "No indices to delete"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+}
+
+func TestRunRollover(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"POST app-write/_rollover": {
+			status: 200,
+			body:   `{"acknowledged": true, "old_index": "app-000002", "new_index": "app-000003", "rolled_over": true, "conditions": {"[max_age: 3d]": true}}`,
+		},
+		"GET app-000003": {status: 200, body: `{"app-000003": {}}`},
+		"POST _aliases":  {status: 200, body: `{"acknowledged": true}`},
+	}))
+	config := &Config{PolicyMapping: "app", RolloverConditions: &estypes.RolloverConditions{MaxAge: "3d"}}
+
+	results := Run(esClient, config, []string{PhaseRollover}, now)
+
+	// the fake alias still points to the old write index, which is moved to the new one
+	expected := []Result{{Phase: PhaseRollover, Alias: "app", Indices: []string{"app-000002", "app-000003"}, Message: "Rolled app-000002 over to app-000003"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+	if body := fake.bodies["POST app-write/_rollover"]; body != `{"conditions":{"max_age":"3d"}}` {
+		t.Errorf("Expected the rollover conditions to be sent, got %s", body)
+	}
+	want := `{"actions":[{"add":{"index":"app-000002","alias":"app-write","is_write_index":false}},{"add":{"index":"app-000003","alias":"app-write","is_write_index":true}}]}`
+	if body := fake.bodies["POST _aliases"]; body != want {
+		t.Errorf("Expected the write alias to be moved with %s, got %s", want, body)
+	}
+}
+
+func TestRunRolloverWithoutMetConditions(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"POST app-write/_rollover": {
+			status: 200,
+			body:   `{"acknowledged": false, "old_index": "app-000002", "new_index": "app-000003", "rolled_over": false, "conditions": {"[max_age: 3d]": false}}`,
+		},
+	}))
+	config := &Config{PolicyMapping: "app", RolloverConditions: &estypes.RolloverConditions{MaxAge: "3d"}}
+
+	results := Run(esClient, config, []string{PhaseRollover}, now)
+
+	expected := []Result{{Phase: PhaseRollover, Alias: "app", Message: "No rollover conditions met for app-000002"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+	if fake.received("POST _aliases") {
+		t.Error("Expected the write alias to be kept")
+	}
+}
+
+func TestRunRolloverFailure(t *testing.T) {
+	_, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"POST app-write/_rollover": {status: 500, body: `{"error": "cluster_block_exception", "status": 500}`},
+	}))
+	config := &Config{PolicyMapping: "app", RolloverConditions: &estypes.RolloverConditions{MaxAge: "3d"}}
+
+	results := Run(esClient, config, []string{PhaseRollover}, now)
+
+	if len(results) != 1 || !results[0].Failed() {
+		t.Fatalf("Expected the rollover to fail, got %v", results)
+	}
+	if !strings.Contains(results[0].Error, "failed to rollover index") {
+		t.Errorf("Expected the rollover error, got %s", results[0].Error)
+	}
+}
+
+func TestRunWarmPhase(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, withResponses(map[string]fakeResponse{
+		"GET app-write/_settings/index.creation_date": {
+			status: 200,
+			body:   fmt.Sprintf(`{"app-000001": %s, "app-000002": %s}`, creationDate(3*24*time.Hour), creationDate(3*24*time.Hour)),
+		},
+		"PUT app-000001/_settings":                       {status: 200, body: `{"acknowledged": true}`},
+		"POST app-000001/_forcemerge?max_num_segments=1": {status: 200, body: `{"_shards": {}}`},
+	}))
+	replicas := int32(0)
+	config := &Config{
+		PolicyMapping: "app",
+		Warm: &PhaseConfig{
+			MinAge:                   2 * 24 * time.Hour,
+			MaxAge:                   7 * 24 * time.Hour,
+			NumberOfReplicas:         &replicas,
+			ReadOnly:                 true,
+			ForceMergeMaxNumSegments: 1,
+			AllocationRequire:        map[string]string{"box_type": "warm"},
+		},
+	}
+
+	results := Run(esClient, config, []string{PhaseWarm}, now)
+
+	expected := []Result{{Phase: PhaseWarm, Alias: "app", Indices: []string{"app-000001"}, Message: "Applied the phase actions to 1 indices"}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+	want := `{"index.blocks.write":true,"index.number_of_replicas":0,"index.routing.allocation.require.box_type":"warm"}`
+	if body := fake.bodies["PUT app-000001/_settings"]; body != want {
+		t.Errorf("Expected the settings %s, got %s", want, body)
+	}
+}
+
+func TestShrinkKeepsTheCreationDate(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, map[string]fakeResponse{
+		"GET _cat/shards/app-000001?format=json&h=shard,prirep,state,node": {
+			status: 200,
+			body:   `[{"shard": "0", "prirep": "p", "state": "STARTED", "node": "node-1"}, {"shard": "1", "prirep": "p", "state": "STARTED", "node": "node-1"}]`,
+		},
+		"GET app-000001/_settings/index.creation_date": {
+			status: 200,
+			body:   fmt.Sprintf(`{"app-000001": %s}`, creationDate(3*24*time.Hour)),
+		},
+		"POST app-000001/_shrink/shrink-app-000001": {status: 200, body: `{"acknowledged": true}`},
+	})
+	im := &indexManager{esClient: esClient, config: &Config{}, now: now}
+
+	if _, err := im.shrink("app-000001", &PhaseConfig{ShrinkNumberOfShards: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := fmt.Sprintf(`{"settings":{"index.blocks.write":false,"index.creation_date":%d,"index.number_of_replicas":0,"index.number_of_shards":1,"index.routing.allocation.require._name":null}}`,
+		now.Add(-3*24*time.Hour).UnixNano()/int64(time.Millisecond))
+	if body := fake.bodies["POST app-000001/_shrink/shrink-app-000001"]; body != want {
+		t.Errorf("Expected the shrink request %s, got %s", want, body)
+	}
+}
+
+func TestShrinkMovesTheAliasesOnceGreen(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, map[string]fakeResponse{
+		"GET shrink-app-000001":                          {status: 200, body: `{"shrink-app-000001": {}}`},
+		"GET _cat/indices/shrink-app-000001?format=json": {status: 200, body: `[{"health": "green", "index": "shrink-app-000001"}]`},
+		"GET app-000001/_alias":                          {status: 200, body: `{"app-000001": {"aliases": {"app": {}, "app-write": {}}}}`},
+		"POST _aliases":                                  {status: 200, body: `{"acknowledged": true}`},
+	})
+	im := &indexManager{esClient: esClient, config: &Config{}, now: now}
+
+	if _, err := im.shrink("app-000001", &PhaseConfig{ShrinkNumberOfShards: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := `{"actions":[{"add":{"index":"shrink-app-000001","alias":"app"}},{"add":{"index":"shrink-app-000001","alias":"app-write"}},{"remove_index":{"index":"app-000001"}}]}`
+	if body := fake.bodies["POST _aliases"]; body != want {
+		t.Errorf("Expected the alias actions %s, got %s", want, body)
+	}
+	if fake.received("DELETE app-000001?ignore_unavailable=true") {
+		t.Error("Expected the index to be removed by the alias actions")
+	}
+}
+
+func TestRunWithoutAliases(t *testing.T) {
+	_, esClient := newFakeElasticsearch(t, map[string]fakeResponse{
+		"GET _alias/app*-write": {status: 200, body: `{}`},
+	})
+	config := &Config{PolicyMapping: "app", DeleteMinAge: time.Hour}
+
+	if results := Run(esClient, config, []string{PhaseDelete}, now); len(results) != 0 {
+		t.Errorf("Expected no results, got %v", results)
+	}
+}
+
+func TestNewShrinkPlan(t *testing.T) {
+	shards := estypes.CatShardsResponses{
+		{Shard: "0", Prirep: "p", State: "STARTED", Node: "node-1"},
+		{Shard: "0", Prirep: "r", State: "STARTED", Node: "node-2"},
+		{Shard: "1", Prirep: "p", State: "STARTED", Node: "node-2"},
+		{Shard: "1", Prirep: "r", State: "STARTED", Node: "node-3"},
+	}
+
+	tests := []struct {
+		desc           string
+		numberOfShards int32
+		want           *shrinkPlan
+		wantErr        bool
+	}{
+		{
+			desc:           "pick the node holding every shard",
+			numberOfShards: 1,
+			want:           &shrinkPlan{node: "node-2", ready: true, replicas: 1},
+		},
+		{
+			desc:           "nothing to shrink",
+			numberOfShards: 2,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			plan, err := newShrinkPlan(test.numberOfShards, shards)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(plan, test.want) {
+				t.Errorf("Expected plan %v, got %v", test.want, plan)
+			}
+		})
+	}
+
+	if _, err := newShrinkPlan(2, append(shards, estypes.CatShardsResponse{Shard: "2", Prirep: "p", State: "STARTED", Node: "node-1"})); err == nil {
+		t.Error("Expected an error shrinking 3 primary shards into 2")
+	}
+}
+
+func TestNextGeneration(t *testing.T) {
+	next, err := nextGeneration("audit-infra-000009")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next != "audit-infra-000010" {
+		t.Errorf("Expected audit-infra-000010, got %s", next)
+	}
+}
+
+func TestNewConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"POLICY_MAPPING":               "app",
+		"MIN_AGE":                      "86400000",
+		"ROLLOVER_CONDITIONS":          `{"max_age":"3d","max_size":"40gb"}`,
+		"COLD_MIN_AGE":                 "3600000",
+		"COLD_READ_ONLY":               "true",
+		"COLD_NUMBER_OF_REPLICAS":      "0",
+		"COLD_ALLOCATION_REQUIRE":      `{"box_type":"cold"}`,
+		"COLD_SHRINK_NUMBER_OF_SHARDS": "1",
+	}
+
+	config, err := NewConfigFromEnv(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	replicas := int32(0)
+	expected := &Config{
+		PolicyMapping:      "app",
+		DeleteMinAge:       24 * time.Hour,
+		RolloverConditions: &estypes.RolloverConditions{MaxAge: "3d", MaxSize: "40gb"},
+		Cold: &PhaseConfig{
+			MinAge:               time.Hour,
+			NumberOfReplicas:     &replicas,
+			ReadOnly:             true,
+			ShrinkNumberOfShards: 1,
+			AllocationRequire:    map[string]string{"box_type": "cold"},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected config %+v, got %+v", expected, config)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	results := []Result{
+		{Phase: PhaseDelete, Alias: "app", Indices: []string{"app-000001", "app-000002"}},
+		{Phase: PhaseDelete, Alias: "audit", Error: "failed to delete indices"},
+		{Phase: PhaseRollover, Alias: "app", Indices: []string{"app-000003", "app-000004"}},
+		{Phase: PhaseRollover, Alias: "audit", Message: "No rollover conditions met for audit-000001"},
+	}
+
+	summary := Summarize(results)
+
+	expected := Summary{
+		RolledOverIndices: []string{"app-000004"},
+		DeletedIndices:    2,
+		Errors:            []string{"delete audit: failed to delete indices"},
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected %v, got %v", expected, summary)
+	}
+}
+
+func TestParseSummary(t *testing.T) {
+	summary, err := ParseSummary(`{"rolledOverIndices":["app-000004"],"deletedIndices":2}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &Summary{RolledOverIndices: []string{"app-000004"}, DeletedIndices: 2}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected %v, got %v", expected, summary)
+	}
+
+	if _, err := ParseSummary(""); err == nil {
+		t.Error("Expected an error parsing an empty termination message")
+	}
+}
@@ -0,0 +1,116 @@
+package job
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+)
+
+const (
+	caFile                = "/etc/indexmanagement/keys/admin-ca"
+	defaultConnectTimeout = 30 * time.Second
+)
+
+// Main runs the given phases of the policy mapping configured by the environment, or the
+// snapshot phase, prints a JSON result per phase and alias and writes the summary to the
+// termination message. It returns the exit code of the job
+func Main(phases []string) int {
+	results := run(phases)
+
+	encoder := json.NewEncoder(os.Stdout)
+	code := 0
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+		}
+		if result.Failed() {
+			code = 1
+		}
+	}
+	if err := writeSummary(Summarize(results)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return code
+}
+
+func run(phases []string) []Result {
+	if len(phases) == 0 {
+		return []Result{{Error: "no phases to run"}}
+	}
+
+	esClient, err := newClient(os.Getenv("ES_SERVICE"))
+	if err != nil {
+		return []Result{{Error: err.Error()}}
+	}
+
+	if len(phases) == 1 && phases[0] == PhaseSnapshot {
+		config, err := NewSnapshotConfigFromEnv(os.Getenv)
+		if err != nil {
+			return []Result{{Phase: PhaseSnapshot, Error: err.Error()}}
+		}
+		return RunSnapshot(esClient, config, time.Now())
+	}
+
+	config, err := NewConfigFromEnv(os.Getenv)
+	if err != nil {
+		return []Result{{Error: err.Error()}}
+	}
+	return Run(esClient, config, phases, time.Now())
+}
+
+// newClient returns a client for the Elasticsearch service authenticated by the service account
+// token of the job
+func newClient(serviceURL string) (elasticsearch.Client, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil || u.Host == "" {
+		return nil, kverrors.New("invalid Elasticsearch service URL", "env", "ES_SERVICE", "url", serviceURL)
+	}
+
+	connectTimeout := defaultConnectTimeout
+	if value := os.Getenv("CONNECT_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse the connect timeout", "env", "CONNECT_TIMEOUT")
+		}
+		connectTimeout = time.Duration(seconds) * time.Second
+	}
+
+	caPem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to read the Elasticsearch CA", "file", caFile)
+	}
+	certPool := x509.NewCertPool()
+	certPool.AppendCertsFromPEM(caPem)
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   connectTimeout,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig: &tls.Config{
+				RootCAs: certPool,
+			},
+		},
+	}
+
+	// the service is named after the cluster
+	esClient := elasticsearch.NewClient(u.Hostname(), "", nil)
+	esClient.SetSendRequestFn(newRetrySendRequestFn(elasticsearch.NewServiceSendRequestFn(serviceURL, httpClient), defaultRetryDelay))
+	return esClient, nil
+}
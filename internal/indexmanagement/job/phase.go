@@ -0,0 +1,264 @@
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	shrinkPrefix         = "shrink-"
+	allocationRequireKey = "index.routing.allocation.require."
+)
+
+// managePhase applies the actions of the warm or cold phase to the indices of the alias
+// which are in the phase
+func (im *indexManager) managePhase(alias string, phase *PhaseConfig, result *Result) error {
+	writeIndex, err := im.getWriteIndex(alias)
+	if err != nil {
+		return err
+	}
+
+	from := time.Time{}
+	if phase.MaxAge > 0 {
+		from = im.now.Add(-phase.MaxAge)
+	}
+	indices, err := im.getIndicesCreatedBetween(alias, writeIndex, from, im.now.Add(-phase.MinAge))
+	if err != nil {
+		return err
+	}
+	if len(indices) == 0 {
+		result.Message = "No indices in the phase"
+		return nil
+	}
+
+	messages := []string{}
+	for _, index := range indices {
+		message, err := im.applyPhaseActions(index, phase)
+		if err != nil {
+			return err
+		}
+		result.Indices = append(result.Indices, index)
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	result.Message = fmt.Sprintf("Applied the phase actions to %d indices", len(result.Indices))
+	if len(messages) > 0 {
+		result.Message = fmt.Sprintf("%s: %s", result.Message, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// applyPhaseActions returns a message when an action is waiting for the cluster
+func (im *indexManager) applyPhaseActions(index string, phase *PhaseConfig) (string, error) {
+	settings := map[string]interface{}{}
+	if phase.NumberOfReplicas != nil {
+		settings["index.number_of_replicas"] = *phase.NumberOfReplicas
+	}
+	for name, value := range phase.AllocationRequire {
+		settings[allocationRequireKey+name] = value
+	}
+	if phase.ReadOnly {
+		settings["index.blocks.write"] = true
+	}
+	if len(settings) > 0 {
+		if err := im.esClient.PutIndexSettings(index, settings); err != nil {
+			return "", err
+		}
+	}
+
+	message := ""
+	if phase.ShrinkNumberOfShards > 0 {
+		var err error
+		if message, err = im.shrink(index, phase); err != nil {
+			return "", err
+		}
+	}
+
+	if phase.ForceMergeMaxNumSegments > 0 {
+		if err := im.esClient.ForceMerge(index, phase.ForceMergeMaxNumSegments); err != nil {
+			return "", err
+		}
+	}
+	return message, nil
+}
+
+// shrink moves a copy of every shard of the index to one node and shrinks it into a new index
+// without aliases. Once the shrunken index is green, the aliases move to it and the original
+// index is removed in a single request, for searches to never see both. The shrunken index
+// keeps the creation date of the original one for the later phases to age it the same. Every
+// step is retried by the next run until the index is shrunken
+func (im *indexManager) shrink(index string, phase *PhaseConfig) (string, error) {
+	if strings.HasPrefix(index, shrinkPrefix) {
+		return "", nil
+	}
+	target := shrinkPrefix + index
+
+	existing, err := im.esClient.GetIndex(target)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		health, err := im.esClient.GetAllIndices(target)
+		if err != nil {
+			return "", err
+		}
+		if len(health) == 0 || health[0].Health != "green" {
+			return fmt.Sprintf("waiting for %s to be green before removing %s", target, index), nil
+		}
+		if err := im.replaceShrunkenIndex(index, target); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("replaced %s with %s after it was shrunken", index, target), nil
+	}
+
+	shards, err := im.esClient.GetIndexShards(index)
+	if err != nil {
+		return "", err
+	}
+	// the shards first move to the nodes required by the phase
+	if len(phase.AllocationRequire) > 0 && hasRelocatingShards(shards) {
+		return fmt.Sprintf("waiting for the shards of %s to be relocated before shrinking", index), nil
+	}
+
+	plan, err := newShrinkPlan(phase.ShrinkNumberOfShards, shards)
+	if err != nil || plan == nil {
+		return "", err
+	}
+
+	if !plan.ready {
+		err := im.esClient.PutIndexSettings(index, map[string]interface{}{
+			allocationRequireKey + "_name": plan.node,
+			"index.blocks.write":           true,
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("relocating a copy of every shard of %s to %s before shrinking", index, plan.node), nil
+	}
+
+	dates, err := im.esClient.GetIndicesCreationDate(index)
+	if err != nil {
+		return "", err
+	}
+	created, ok := dates[index]
+	if !ok {
+		return "", kverrors.New("the index to shrink does not exist", "index", index)
+	}
+
+	// the shrunken index is kept on the nodes required by the phase
+	settings := map[string]interface{}{
+		"index.number_of_shards":       phase.ShrinkNumberOfShards,
+		"index.number_of_replicas":     plan.replicas,
+		"index.creation_date":          created.UnixNano() / int64(time.Millisecond),
+		allocationRequireKey + "_name": nil,
+		"index.blocks.write":           phase.ReadOnly,
+	}
+	for name, value := range phase.AllocationRequire {
+		settings[allocationRequireKey+name] = value
+	}
+	request := &estypes.ShrinkIndexRequest{Settings: settings}
+	if err := im.esClient.ShrinkIndex(index, target, request); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("shrinking %s into %s with %d primary shards", index, target, phase.ShrinkNumberOfShards), nil
+}
+
+// replaceShrunkenIndex adds the aliases of the index to the shrunken one and removes the index
+// atomically. The shrunken index is never the write index
+func (im *indexManager) replaceShrunkenIndex(index, target string) error {
+	current, err := im.esClient.GetIndexAliases(index)
+	if err != nil {
+		return err
+	}
+
+	aliases := make([]string, 0, len(current))
+	for alias := range current {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	actions := estypes.AliasActions{}
+	for _, alias := range aliases {
+		actions.Actions = append(actions.Actions, estypes.AliasAction{
+			Add: &estypes.AddAliasAction{Index: target, Alias: alias},
+		})
+	}
+	actions.Actions = append(actions.Actions, estypes.AliasAction{
+		RemoveIndex: &estypes.RemoveAliasAction{Index: index},
+	})
+	return im.esClient.UpdateAlias(actions)
+}
+
+type shrinkPlan struct {
+	// node is the node to move a copy of every shard to
+	node string
+	// ready is true when the node holds a started copy of every shard
+	ready    bool
+	replicas int32
+}
+
+// newShrinkPlan picks the node holding most of the started shard copies, which is the least
+// amount of relocation. It returns nil when the index has no more shards than desired
+func newShrinkPlan(numberOfShards int32, shards estypes.CatShardsResponses) (*shrinkPlan, error) {
+	shardNumbers := map[string]bool{}
+	replicas := 0
+	started := map[string]map[string]bool{}
+	for _, shard := range shards {
+		shardNumbers[shard.Shard] = true
+		if shard.Prirep == "r" {
+			replicas++
+		}
+		if shard.State == "STARTED" && shard.Node != "" {
+			if started[shard.Node] == nil {
+				started[shard.Node] = map[string]bool{}
+			}
+			started[shard.Node][shard.Shard] = true
+		}
+	}
+
+	if len(shardNumbers) <= int(numberOfShards) {
+		return nil, nil
+	}
+	if len(shardNumbers)%int(numberOfShards) != 0 {
+		return nil, kverrors.New("unable to shrink the primary shards into the number of shards",
+			"primary_shards", len(shardNumbers),
+			"number_of_shards", numberOfShards)
+	}
+	if len(started) == 0 {
+		return nil, kverrors.New("no started shards found to shrink")
+	}
+
+	nodes := make([]string, 0, len(started))
+	for node := range started {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if len(started[nodes[i]]) != len(started[nodes[j]]) {
+			return len(started[nodes[i]]) > len(started[nodes[j]])
+		}
+		return nodes[i] < nodes[j]
+	})
+	node := nodes[0]
+
+	return &shrinkPlan{
+		node:     node,
+		ready:    len(started[node]) == len(shardNumbers) && !hasRelocatingShards(shards),
+		replicas: int32(replicas / len(shardNumbers)),
+	}, nil
+}
+
+func hasRelocatingShards(shards estypes.CatShardsResponses) bool {
+	for _, shard := range shards {
+		if shard.State == "RELOCATING" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package job
+
+import (
+	"net/http"
+	"time"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+)
+
+const (
+	// maxRetries matches the retries of the curl requests the job used to send
+	maxRetries        = 5
+	defaultRetryDelay = time.Second
+)
+
+// newRetrySendRequestFn retries the requests failing with a transient failure up to maxRetries
+// times, doubling the delay between the attempts
+func newRetrySendRequestFn(send elasticsearch.FnEsSendRequest, delay time.Duration) elasticsearch.FnEsSendRequest {
+	return func(cluster, namespace string, payload *elasticsearch.EsRequest, client k8sclient.Client) {
+		for attempt := 0; ; attempt++ {
+			payload.StatusCode = 0
+			payload.RawResponseBody = ""
+			payload.ResponseBody = nil
+			payload.Error = nil
+
+			send(cluster, namespace, payload, client)
+			if attempt == maxRetries || !isTransientFailure(payload) {
+				return
+			}
+			time.Sleep(delay << attempt)
+		}
+	}
+}
+
+// isTransientFailure returns true for the failures which are safe to retry. Elasticsearch
+// rejects the requests it did not process with 429. Other failures may have been processed,
+// e.g. a rollover or a shrink timing out, so they are only retried for idempotent methods
+func isTransientFailure(payload *elasticsearch.EsRequest) bool {
+	if payload.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotent(payload.Method) {
+		return false
+	}
+	if payload.StatusCode == 0 {
+		return payload.Error != nil
+	}
+	switch payload.StatusCode {
+	case http.StatusRequestTimeout, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return false
+}
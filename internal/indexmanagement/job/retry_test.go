@@ -0,0 +1,95 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+)
+
+func newSequenceSendRequestFn(statusCodes []int, calls *int) elasticsearch.FnEsSendRequest {
+	return func(_, _ string, payload *elasticsearch.EsRequest, _ k8sclient.Client) {
+		statusCode := statusCodes[*calls]
+		*calls++
+		if statusCode == 0 {
+			payload.Error = errors.New("connection refused")
+			return
+		}
+		payload.StatusCode = statusCode
+	}
+}
+
+func TestRetrySendRequestFnRetriesTransientFailures(t *testing.T) {
+	calls := 0
+	send := newRetrySendRequestFn(newSequenceSendRequestFn([]int{0, http.StatusServiceUnavailable, http.StatusOK}, &calls), 0)
+
+	payload := &elasticsearch.EsRequest{Method: http.MethodGet, URI: "_cat/indices"}
+	send("elasticsearch", "", payload, nil)
+
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+	if payload.Error != nil || payload.StatusCode != http.StatusOK {
+		t.Errorf("Expected the last attempt to succeed, got status %d and error %v", payload.StatusCode, payload.Error)
+	}
+}
+
+func TestRetrySendRequestFnIsBounded(t *testing.T) {
+	statusCodes := make([]int, maxRetries+2)
+	for i := range statusCodes {
+		statusCodes[i] = http.StatusServiceUnavailable
+	}
+	calls := 0
+	send := newRetrySendRequestFn(newSequenceSendRequestFn(statusCodes, &calls), 0)
+
+	payload := &elasticsearch.EsRequest{Method: http.MethodGet, URI: "_cat/indices"}
+	send("elasticsearch", "", payload, nil)
+
+	if calls != maxRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", maxRetries+1, calls)
+	}
+	if payload.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the status of the last attempt, got %d", payload.StatusCode)
+	}
+}
+
+func TestRetrySendRequestFnDoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	send := newRetrySendRequestFn(newSequenceSendRequestFn([]int{http.StatusNotFound, http.StatusOK}, &calls), 0)
+
+	payload := &elasticsearch.EsRequest{Method: http.MethodGet, URI: "app-write/_settings"}
+	send("elasticsearch", "", payload, nil)
+
+	if calls != 1 || payload.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a single attempt, got %d attempts and status %d", calls, payload.StatusCode)
+	}
+}
+
+func TestRetrySendRequestFnDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	for _, statusCode := range []int{0, http.StatusServiceUnavailable} {
+		calls := 0
+		send := newRetrySendRequestFn(newSequenceSendRequestFn([]int{statusCode, http.StatusOK}, &calls), 0)
+
+		payload := &elasticsearch.EsRequest{Method: http.MethodPost, URI: "app-write/_rollover"}
+		send("elasticsearch", "", payload, nil)
+
+		if calls != 1 {
+			t.Errorf("Expected a single attempt of the rollover failing with status %d, got %d attempts", statusCode, calls)
+		}
+	}
+}
+
+func TestRetrySendRequestFnRetriesRejectedRequests(t *testing.T) {
+	calls := 0
+	send := newRetrySendRequestFn(newSequenceSendRequestFn([]int{http.StatusTooManyRequests, http.StatusOK}, &calls), 0)
+
+	payload := &elasticsearch.EsRequest{Method: http.MethodPost, URI: "app-write/_rollover"}
+	send("elasticsearch", "", payload, nil)
+
+	if calls != 2 || payload.StatusCode != http.StatusOK {
+		t.Errorf("Expected the rejected rollover to be retried, got %d attempts and status %d", calls, payload.StatusCode)
+	}
+}
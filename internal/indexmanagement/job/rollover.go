@@ -0,0 +1,108 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// rollover rolls the write alias over to a new index when the conditions are met and
+// makes sure the write alias points to the new index
+func (im *indexManager) rollover(alias string, result *Result) error {
+	writeAlias := alias + writeAliasSuffix
+	writeIndex, err := im.getWriteIndex(alias)
+	if err != nil {
+		return err
+	}
+
+	nextIndex, err := im.rolloverIndex(writeAlias, writeIndex)
+	if err != nil {
+		return err
+	}
+	if nextIndex == writeIndex {
+		result.Message = fmt.Sprintf("No rollover conditions met for %s", writeIndex)
+		return nil
+	}
+
+	// the next index must exist and be writable, e.g. the cluster is not read-only
+	// because of low disk space
+	index, err := im.esClient.GetIndex(nextIndex)
+	if err != nil {
+		return err
+	}
+	if index == nil {
+		return kverrors.New("the next write index does not exist", "alias", writeAlias, "index", nextIndex)
+	}
+
+	result.Indices = []string{writeIndex, nextIndex}
+	result.Message = fmt.Sprintf("Rolled %s over to %s", writeIndex, nextIndex)
+
+	currentIndex, err := im.getWriteIndex(alias)
+	if err != nil {
+		return err
+	}
+	if currentIndex == nextIndex {
+		return nil
+	}
+
+	isWriteIndex, isNotWriteIndex := true, false
+	return im.esClient.UpdateAlias(estypes.AliasActions{
+		Actions: []estypes.AliasAction{
+			{Add: &estypes.AddAliasAction{Index: currentIndex, Alias: writeAlias, IsWriteIndex: &isNotWriteIndex}},
+			{Add: &estypes.AddAliasAction{Index: nextIndex, Alias: writeAlias, IsWriteIndex: &isWriteIndex}},
+		},
+	})
+}
+
+// rolloverIndex returns the next write index of the alias, which is the current write index
+// when the alias was not rolled over
+func (im *indexManager) rolloverIndex(writeAlias, writeIndex string) (string, error) {
+	response, err := im.esClient.RolloverIndex(writeAlias, *im.config.RolloverConditions)
+	if err != nil {
+		// an earlier rollover might have created the next index without moving the alias
+		nextIndex, genErr := nextGeneration(writeIndex)
+		if genErr != nil {
+			return "", err
+		}
+		if index, getErr := im.esClient.GetIndex(nextIndex); getErr != nil || index == nil {
+			return "", err
+		}
+		return nextIndex, nil
+	}
+
+	if !response.Acknowledged && !response.RolledOver {
+		for condition, met := range response.Conditions {
+			if met {
+				return "", kverrors.New("index was not rolled over despite meeting conditions to do so",
+					"alias", writeAlias,
+					"condition", condition)
+			}
+		}
+		return response.OldIndex, nil
+	}
+
+	if response.OldIndex != writeIndex {
+		return "", kverrors.New("the rolled over index does not match the write index",
+			"alias", writeAlias,
+			"old_index", response.OldIndex,
+			"write_index", writeIndex)
+	}
+	return response.NewIndex, nil
+}
+
+// nextGeneration returns the index following the given one, e.g. app-000002 for app-000001
+func nextGeneration(index string) (string, error) {
+	i := strings.LastIndex(index, "-")
+	if i < 0 {
+		return "", kverrors.New("index name has no generation", "index", index)
+	}
+	generation, err := strconv.Atoi(index[i+1:])
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to parse the index generation", "index", index)
+	}
+	return fmt.Sprintf("%s-%06d", index[:i], generation+1), nil
+}
@@ -0,0 +1,140 @@
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// PhaseSnapshot takes a snapshot of the cluster and prunes the expired ones. It is run
+	// by the snapshot cronjob instead of the phases of a policy mapping
+	PhaseSnapshot = "snapshot"
+
+	snapshotStateSuccess    = "SUCCESS"
+	snapshotStateInProgress = "IN_PROGRESS"
+)
+
+// SnapshotConfig is the schedule of the cluster snapshots as passed to the job by the
+// environment of the snapshot cronjob
+type SnapshotConfig struct {
+	Repository string
+	// Prefix prefixes the names of the snapshots taken by the job. Only snapshots with the
+	// prefix are pruned
+	Prefix  string
+	Indices string
+	// MaxAge is the age after which snapshots are pruned. Zero keeps them
+	MaxAge time.Duration
+	// MaxCount is the number of snapshots kept. Zero keeps them all
+	MaxCount int
+}
+
+// NewSnapshotConfigFromEnv reads the configuration of the snapshots from the environment
+func NewSnapshotConfigFromEnv(getenv func(string) string) (*SnapshotConfig, error) {
+	config := &SnapshotConfig{
+		Repository: getenv("REPOSITORY"),
+		Prefix:     getenv("SNAPSHOT_PREFIX"),
+		Indices:    getenv("SNAPSHOT_INDICES"),
+	}
+	if config.Repository == "" {
+		return nil, kverrors.New("missing the snapshot repository", "env", "REPOSITORY")
+	}
+	if config.Prefix == "" {
+		return nil, kverrors.New("missing the snapshot prefix", "env", "SNAPSHOT_PREFIX")
+	}
+
+	var err error
+	if value := getenv("SNAPSHOT_MAX_AGE"); value != "" {
+		if config.MaxAge, err = parseMillis("SNAPSHOT_MAX_AGE", value); err != nil {
+			return nil, err
+		}
+	}
+	if value := getenv("SNAPSHOT_MAX_COUNT"); value != "" {
+		if config.MaxCount, err = strconv.Atoi(value); err != nil {
+			return nil, kverrors.Wrap(err, "failed to parse number", "env", "SNAPSHOT_MAX_COUNT")
+		}
+	}
+	return config, nil
+}
+
+// RunSnapshot takes a snapshot and prunes the expired snapshots once it succeeded
+func RunSnapshot(esClient elasticsearch.Client, config *SnapshotConfig, now time.Time) []Result {
+	name := fmt.Sprintf("%s-%s", config.Prefix, now.UTC().Format("2006.01.02-15.04.05"))
+
+	result := Result{Phase: PhaseSnapshot}
+	request := &estypes.SnapshotRequest{Indices: config.Indices}
+	snapshot, err := esClient.CreateSnapshot(config.Repository, name, request)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+		return []Result{result}
+	case snapshot.State != snapshotStateSuccess:
+		result.Error = fmt.Sprintf("snapshot %s completed with state %s: %s", name, snapshot.State, snapshot.Reason)
+		return []Result{result}
+	}
+	result.Indices = snapshot.Indices
+	result.Message = fmt.Sprintf("Created snapshot %s in repository %s", name, config.Repository)
+
+	prune := Result{Phase: PhaseSnapshot}
+	snapshots, err := esClient.GetSnapshots(config.Repository)
+	if err != nil {
+		prune.Error = err.Error()
+		return []Result{result, prune}
+	}
+	expired := expiredSnapshots(snapshots, config, now)
+	for _, name := range expired {
+		if err := esClient.DeleteSnapshot(config.Repository, name); err != nil {
+			prune.Error = err.Error()
+			return []Result{result, prune}
+		}
+	}
+	if len(expired) == 0 {
+		prune.Message = "No expired snapshots to delete"
+	} else {
+		prune.Message = fmt.Sprintf("Deleted expired snapshots %s", strings.Join(expired, ", "))
+	}
+	return []Result{result, prune}
+}
+
+// expiredSnapshots returns the snapshots taken by the job which exceed the retention. The
+// most recent successful snapshot is never pruned
+func expiredSnapshots(snapshots []estypes.Snapshot, config *SnapshotConfig, now time.Time) []string {
+	prefix := config.Prefix + "-"
+	candidates := []estypes.Snapshot{}
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Snapshot, prefix) && snapshot.State != snapshotStateInProgress {
+			candidates = append(candidates, snapshot)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].StartTimeInMillis > candidates[j].StartTimeInMillis
+	})
+
+	latestSuccess := ""
+	for _, snapshot := range candidates {
+		if snapshot.State == snapshotStateSuccess {
+			latestSuccess = snapshot.Snapshot
+			break
+		}
+	}
+
+	nowMillis := now.UnixNano() / int64(time.Millisecond)
+	maxAgeMillis := int64(config.MaxAge / time.Millisecond)
+	expired := []string{}
+	for i, snapshot := range candidates {
+		if snapshot.Snapshot == latestSuccess {
+			continue
+		}
+		if (config.MaxCount > 0 && i >= config.MaxCount) || (maxAgeMillis > 0 && nowMillis-snapshot.StartTimeInMillis > maxAgeMillis) {
+			expired = append(expired, snapshot.Snapshot)
+		}
+	}
+	return expired
+}
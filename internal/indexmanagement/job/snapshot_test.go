@@ -0,0 +1,104 @@
+package job
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+func startedAgo(age time.Duration) int64 {
+	return now.Add(-age).UnixNano() / int64(time.Millisecond)
+}
+
+func TestRunSnapshot(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, map[string]fakeResponse{
+		"PUT _snapshot/backups/scheduled-2021.03.10-12.00.00?wait_for_completion=true": {
+			status: 200,
+			body:   `{"snapshot": {"snapshot": "scheduled-2021.03.10-12.00.00", "state": "SUCCESS", "indices": ["app-000001"]}}`,
+		},
+		"GET _snapshot/backups/_all": {
+			status: 200,
+			body: fmt.Sprintf(`{"snapshots": [
+				{"snapshot": "scheduled-2021.03.10-12.00.00", "state": "SUCCESS", "start_time_in_millis": %d},
+				{"snapshot": "scheduled-2021.03.09-12.00.00", "state": "SUCCESS", "start_time_in_millis": %d},
+				{"snapshot": "scheduled-2021.03.01-12.00.00", "state": "SUCCESS", "start_time_in_millis": %d},
+				{"snapshot": "manual", "state": "SUCCESS", "start_time_in_millis": %d}
+			]}`, startedAgo(0), startedAgo(24*time.Hour), startedAgo(9*24*time.Hour), startedAgo(30*24*time.Hour)),
+		},
+		"DELETE _snapshot/backups/scheduled-2021.03.01-12.00.00": {status: 200, body: `{"acknowledged": true}`},
+	})
+	config := &SnapshotConfig{Repository: "backups", Prefix: "scheduled", Indices: "*", MaxAge: 7 * 24 * time.Hour}
+
+	results := RunSnapshot(esClient, config, now)
+
+	expected := []Result{
+		{Phase: PhaseSnapshot, Indices: []string{"app-000001"}, Message: "Created snapshot scheduled-2021.03.10-12.00.00 in repository backups"},
+		{Phase: PhaseSnapshot, Message: "Deleted expired snapshots scheduled-2021.03.01-12.00.00"},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %v, got %v", expected, results)
+	}
+	if fake.received("DELETE _snapshot/backups/manual") {
+		t.Error("Expected the snapshots not taken by the job to be kept")
+	}
+}
+
+func TestRunSnapshotPartial(t *testing.T) {
+	fake, esClient := newFakeElasticsearch(t, map[string]fakeResponse{
+		"PUT _snapshot/backups/scheduled-2021.03.10-12.00.00?wait_for_completion=true": {
+			status: 200,
+			body:   `{"snapshot": {"snapshot": "scheduled-2021.03.10-12.00.00", "state": "PARTIAL", "reason": "shard failures"}}`,
+		},
+	})
+	config := &SnapshotConfig{Repository: "backups", Prefix: "scheduled", MaxCount: 1}
+
+	results := RunSnapshot(esClient, config, now)
+
+	if len(results) != 1 || results[0].Error != "snapshot scheduled-2021.03.10-12.00.00 completed with state PARTIAL: shard failures" {
+		t.Errorf("Expected the partial snapshot to fail the run, got %v", results)
+	}
+	if fake.received("GET _snapshot/backups/_all") {
+		t.Error("Expected no snapshots to be pruned after a failed snapshot")
+	}
+}
+
+func TestExpiredSnapshotsKeepTheLatestSuccess(t *testing.T) {
+	snapshots := []estypes.Snapshot{
+		{Snapshot: "scheduled-3", State: "FAILED", StartTimeInMillis: startedAgo(time.Hour)},
+		{Snapshot: "scheduled-2", State: "IN_PROGRESS", StartTimeInMillis: startedAgo(2 * time.Hour)},
+		{Snapshot: "scheduled-1", State: "SUCCESS", StartTimeInMillis: startedAgo(30 * 24 * time.Hour)},
+	}
+	config := &SnapshotConfig{Prefix: "scheduled", MaxAge: 24 * time.Hour, MaxCount: 1}
+
+	if expired := expiredSnapshots(snapshots, config, now); len(expired) != 0 {
+		t.Errorf("Expected the only successful snapshot to be kept, got %v", expired)
+	}
+}
+
+func TestNewSnapshotConfigFromEnv(t *testing.T) {
+	env := map[string]string{
+		"REPOSITORY":         "backups",
+		"SNAPSHOT_PREFIX":    "scheduled",
+		"SNAPSHOT_INDICES":   "app-*,infra-*",
+		"SNAPSHOT_MAX_AGE":   "86400000",
+		"SNAPSHOT_MAX_COUNT": "7",
+	}
+
+	config, err := NewSnapshotConfigFromEnv(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &SnapshotConfig{Repository: "backups", Prefix: "scheduled", Indices: "app-*,infra-*", MaxAge: 24 * time.Hour, MaxCount: 7}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected config %+v, got %+v", expected, config)
+	}
+
+	delete(env, "REPOSITORY")
+	if _, err := NewSnapshotConfigFromEnv(func(name string) string { return env[name] }); err == nil {
+		t.Error("Expected an error without a repository")
+	}
+}
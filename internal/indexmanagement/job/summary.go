@@ -0,0 +1,80 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ViaQ/logerr/kverrors"
+)
+
+const (
+	// terminationMessagePath is where the job writes its summary for the operator to read it
+	// from the status of the pod
+	terminationMessagePath = "/dev/termination-log"
+
+	// the termination message is limited to 4096 bytes
+	maxSummaryErrors      = 5
+	maxSummaryErrorLength = 256
+)
+
+// Summary is the outcome of a job run reported in the status of the policy mapping
+type Summary struct {
+	// RolledOverIndices are the new write indices
+	RolledOverIndices []string `json:"rolledOverIndices,omitempty"`
+	DeletedIndices    int32    `json:"deletedIndices"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+// Summarize returns the summary of the results of a job run
+func Summarize(results []Result) Summary {
+	summary := Summary{}
+	for _, result := range results {
+		if result.Failed() {
+			if len(summary.Errors) < maxSummaryErrors {
+				summary.Errors = append(summary.Errors, truncate(formatError(result), maxSummaryErrorLength))
+			}
+		}
+		switch result.Phase {
+		case PhaseDelete:
+			summary.DeletedIndices += int32(len(result.Indices))
+		case PhaseRollover:
+			// the indices are the old and the new write index
+			if len(result.Indices) == 2 {
+				summary.RolledOverIndices = append(summary.RolledOverIndices, result.Indices[1])
+			}
+		}
+	}
+	return summary
+}
+
+// ParseSummary parses the termination message of a job
+func ParseSummary(message string) (*Summary, error) {
+	summary := &Summary{}
+	if err := json.Unmarshal([]byte(message), summary); err != nil {
+		return nil, kverrors.Wrap(err, "failed to parse the index management job summary")
+	}
+	return summary, nil
+}
+
+func writeSummary(summary Summary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to serialize the index management job summary")
+	}
+	return ioutil.WriteFile(terminationMessagePath, body, 0o644)
+}
+
+func formatError(result Result) string {
+	if result.Alias == "" {
+		return fmt.Sprintf("%s: %s", result.Phase, result.Error)
+	}
+	return fmt.Sprintf("%s %s: %s", result.Phase, result.Alias, result.Error)
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length-3] + "..."
+}
@@ -2,12 +2,10 @@ package indexmanagement
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
-	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	batchv1 "k8s.io/api/batch/v1"
@@ -25,22 +23,28 @@ import (
 	"github.com/ViaQ/logerr/log"
 	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
-	"github.com/openshift/elasticsearch-operator/internal/types/k8s"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/job"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
 	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
 )
 
 const (
-	indexManagementConfigmap = "indexmanagement-scripts"
-	defaultShardSize         = int32(40)
-	workingDir               = "/tmp/scripts"
+	defaultShardSize = int32(40)
+
+	// clusterNameLabel is the label of the index management jobs naming their cluster
+	clusterNameLabel = "cluster-name"
+
+	// scriptsConfigmap held the scripts the index management jobs ran before the phases
+	// moved to the operator binary
+	scriptsConfigmap = "indexmanagement-scripts"
 )
 
 var (
 	defaultCPURequest      = resource.MustParse("100m")
 	defaultMemoryRequest   = resource.MustParse("32Mi")
-	jobHistoryLimitFailed  = utils.GetInt32(1)
 	jobHistoryLimitSuccess = utils.GetInt32(1)
+	// failed jobs are kept to count the consecutive failures of the index management jobs
+	jobHistoryLimitFailed = utils.GetInt32(RunsFailingThreshold)
 
 	millisPerSecond = uint64(1000)
 	millisPerMinute = uint64(60 * millisPerSecond)
@@ -48,9 +52,6 @@ var (
 	millisPerDay    = uint64(millisPerHour * 24)
 	millisPerWeek   = uint64(millisPerDay * 7)
 
-	// fullExecMode octal 0777
-	fullExecMode int32 = 0o777
-
 	imLabels = map[string]string{
 		"provider":      "openshift",
 		"component":     "indexManagement",
@@ -64,6 +65,27 @@ type rolloverConditions struct {
 	MaxSize string `json:"max_size,omitempty"`
 }
 
+// RemoveScriptsConfigmap deletes the configmap of the scripts the index management jobs no
+// longer mount, left behind by clusters created by previous versions of the operator
+func RemoveScriptsConfigmap(apiclient client.Client, cluster *apis.Elasticsearch) error {
+	configmap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scriptsConfigmap,
+			Namespace: cluster.Namespace,
+		},
+	}
+	if err := apiclient.Delete(context.TODO(), configmap); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to remove the index management scripts configmap",
+			"namespace", cluster.Namespace,
+			"name", scriptsConfigmap)
+	}
+	return nil
+}
+
 func RemoveCronJobsForMappings(apiclient client.Client, cluster *apis.Elasticsearch, mappings []apis.IndexManagementPolicyMappingSpec, policies apis.PolicyMap) error {
 	expected := sets.NewString()
 	for _, mapping := range mappings {
@@ -105,40 +127,8 @@ func RemoveCronJobsForMappings(apiclient client.Client, cluster *apis.Elasticsea
 	return nil
 }
 
-func ReconcileCurationConfigmap(apiclient client.Client, cluster *apis.Elasticsearch) error {
-	data := scriptMap
-	desired := k8s.NewConfigMap(indexManagementConfigmap, cluster.Namespace, imLabels, data)
-	cluster.AddOwnerRefTo(desired)
-
-	errCtx := kverrors.NewContext("configmap", desired.Name,
-		"cluster", cluster.Name,
-		"namespace", cluster.Namespace,
-	)
-
-	err := apiclient.Create(context.TODO(), desired)
-	if err == nil {
-		return nil
-	}
-	if !apierrors.IsAlreadyExists(err) {
-		return errCtx.Wrap(err, "failed to create cluster configmap")
-	}
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		current := &corev1.ConfigMap{}
-		retryError := apiclient.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
-		if retryError != nil {
-			return retryError
-		}
-		if !reflect.DeepEqual(desired.Data, current.Data) {
-			current.Data = desired.Data
-			return apiclient.Update(context.TODO(), current)
-		}
-		return nil
-	})
-	return errCtx.Wrap(err, "failed to update configmap")
-}
-
 func ReconcileIndexManagementCronjob(apiclient client.Client, cluster *apis.Elasticsearch, policy apis.IndexManagementPolicySpec, mapping apis.IndexManagementPolicyMappingSpec, primaryShards int32) error {
-	if policy.Phases.Delete == nil && policy.Phases.Hot == nil {
+	if policy.Phases.Delete == nil && policy.Phases.Hot == nil && policy.Phases.Warm == nil && policy.Phases.Cold == nil {
 		log.V(1).Info("Skipping indexmanagement cronjob for policymapping; no phases are defined", "policymapping", mapping.Name)
 		return nil
 	}
@@ -159,14 +149,38 @@ func ReconcileIndexManagementCronjob(apiclient client.Client, cluster *apis.Elas
 		log.V(1).Info("Skipping curation management for policymapping; delete phase not defined", "policymapping", mapping.Name)
 	}
 
+	if policy.Phases.Warm != nil {
+		maxAge := apis.TimeUnit("")
+		if policy.Phases.Cold != nil {
+			maxAge = policy.Phases.Cold.MinAge
+		}
+		phaseEnvVars, err := newPhaseEnvVars("WARM", policy.Phases.Warm.MinAge, maxAge, policy.Phases.Warm.Actions)
+		if err != nil {
+			return err
+		}
+		envvars = append(envvars, phaseEnvVars...)
+	} else {
+		log.V(1).Info("Skipping warm phase management for policymapping; warm phase not defined", "policymapping", mapping.Name)
+	}
+
+	if policy.Phases.Cold != nil {
+		phaseEnvVars, err := newPhaseEnvVars("COLD", policy.Phases.Cold.MinAge, "", policy.Phases.Cold.Actions)
+		if err != nil {
+			return err
+		}
+		envvars = append(envvars, phaseEnvVars...)
+	} else {
+		log.V(1).Info("Skipping cold phase management for policymapping; cold phase not defined", "policymapping", mapping.Name)
+	}
+
 	if policy.Phases.Hot != nil {
 		conditions := calculateConditions(policy, primaryShards)
-		payload, err := json.Marshal(map[string]rolloverConditions{"conditions": conditions})
+		payload, err := json.Marshal(conditions)
 		if err != nil {
 			return kverrors.Wrap(err, "failed to serialize the rollover conditions to JSON")
 		}
 		envvars = append(envvars,
-			corev1.EnvVar{Name: "PAYLOAD", Value: base64.StdEncoding.EncodeToString(payload)},
+			corev1.EnvVar{Name: "ROLLOVER_CONDITIONS", Value: string(payload)},
 		)
 
 	} else {
@@ -179,30 +193,68 @@ func ReconcileIndexManagementCronjob(apiclient client.Client, cluster *apis.Elas
 	}
 
 	name := fmt.Sprintf("%s-im-%s", cluster.Name, mapping.Name)
-	script := formatCmd(policy)
-	desired := newCronJob(cluster.Name, cluster.Namespace, name, schedule, script, cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, envvars)
+	container := newJobContainer(cluster.Name, formatPhases(policy), envvars)
+	desired := newCronJob(cluster.Name, cluster.Namespace, name, schedule, imLabels, cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, container, newCertsVolume(cluster.Name))
 
 	cluster.AddOwnerRefTo(desired)
 	return reconcileCronJob(apiclient, cluster, desired, areCronJobsSame)
 }
 
-func formatCmd(policy apis.IndexManagementPolicySpec) string {
-	cmd := []string{}
-	result := []string{}
+// newPhaseEnvVars returns the env vars for the warm or cold phase script identified by prefix.
+// Indices older than maxAge are left to the following phase. An empty maxAge has no upper bound.
+func newPhaseEnvVars(prefix string, minAge, maxAge apis.TimeUnit, actions apis.IndexManagementPhaseActionsSpec) ([]corev1.EnvVar, error) {
+	minAgeMillis, err := calculateMillisForTimeUnit(minAge)
+	if err != nil {
+		return nil, err
+	}
+	envvars := []corev1.EnvVar{
+		{Name: prefix + "_MIN_AGE", Value: strconv.FormatUint(minAgeMillis, 10)},
+	}
+	if maxAge != "" {
+		maxAgeMillis, err := calculateMillisForTimeUnit(maxAge)
+		if err != nil {
+			return nil, err
+		}
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_MAX_AGE", Value: strconv.FormatUint(maxAgeMillis, 10)})
+	}
+	if actions.Replicas != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_NUMBER_OF_REPLICAS", Value: strconv.Itoa(int(actions.Replicas.NumberOfReplicas))})
+	}
+	if actions.ReadOnly {
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_READ_ONLY", Value: "true"})
+	}
+	if actions.Shrink != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_SHRINK_NUMBER_OF_SHARDS", Value: strconv.Itoa(int(actions.Shrink.NumberOfShards))})
+	}
+	if actions.ForceMerge != nil {
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_FORCE_MERGE_MAX_NUM_SEGMENTS", Value: strconv.Itoa(int(actions.ForceMerge.MaxNumSegments))})
+	}
+	if actions.Allocation != nil {
+		require, err := json.Marshal(actions.Allocation.Require)
+		if err != nil {
+			return nil, err
+		}
+		envvars = append(envvars, corev1.EnvVar{Name: prefix + "_ALLOCATION_REQUIRE", Value: string(require)})
+	}
+	return envvars, nil
+}
+
+// formatPhases returns the phases run by the index management job in the order the job runs them
+func formatPhases(policy apis.IndexManagementPolicySpec) []string {
+	phases := []string{}
 	if policy.Phases.Delete != nil {
-		cmd = append(cmd, "./delete", "delete_rc=$?")
-		result = append(result, "exit $delete_rc")
+		phases = append(phases, job.PhaseDelete)
 	}
-	if policy.Phases.Hot != nil {
-		cmd = append(cmd, "./rollover", "rollover_rc=$?")
-		result = append(result, "exit $rollover_rc")
+	if policy.Phases.Cold != nil {
+		phases = append(phases, job.PhaseCold)
 	}
-	if len(cmd) == 0 {
-		return ""
+	if policy.Phases.Warm != nil {
+		phases = append(phases, job.PhaseWarm)
 	}
-	cmd = append(cmd, fmt.Sprintf("$(%s)", strings.Join(result, "&&")))
-	script := strings.Join(cmd, ";")
-	return script
+	if policy.Phases.Hot != nil {
+		phases = append(phases, job.PhaseRollover)
+	}
+	return phases
 }
 
 func reconcileCronJob(apiclient client.Client, cluster *apis.Elasticsearch, desired *batch.CronJob, fnAreCronJobsSame func(lhs, rhs *batch.CronJob) bool) error {
@@ -250,6 +302,12 @@ func areCronJobsSame(lhs, rhs *batch.CronJob) bool {
 	if lhs.Spec.Suspend != nil && rhs.Spec.Suspend != nil && *lhs.Spec.Suspend != *rhs.Spec.Suspend {
 		return false
 	}
+	if !reflect.DeepEqual(lhs.Spec.FailedJobsHistoryLimit, rhs.Spec.FailedJobsHistoryLimit) {
+		return false
+	}
+	if !comparators.AreStringMapsSame(lhs.Spec.JobTemplate.Labels, rhs.Spec.JobTemplate.Labels) {
+		return false
+	}
 	for i, container := range lhs.Spec.JobTemplate.Spec.Template.Spec.Containers {
 		other := rhs.Spec.JobTemplate.Spec.Template.Spec.Containers[i]
 		if !areContainersSame(container, other) {
@@ -284,10 +342,18 @@ func areContainersSame(container, other corev1.Container) bool {
 	return true
 }
 
-func newContainer(clusterName, name, image, scriptPath string, envvars []corev1.EnvVar) corev1.Container {
+// newJobContainer returns the container running the phases of the index management job of the operator image
+func newJobContainer(clusterName string, phases []string, envvars []corev1.EnvVar) corev1.Container {
+	container := newContainer(clusterName, constants.PackagedOperatorImage(), envvars)
+	container.Command = []string{"elasticsearch-operator"}
+	container.Args = append([]string{job.Command}, phases...)
+	return container
+}
+
+func newContainer(clusterName, image string, envvars []corev1.EnvVar) corev1.Container {
 	envvars = append(envvars, corev1.EnvVar{Name: "ES_SERVICE", Value: fmt.Sprintf("https://%s:9200", clusterName)})
 	container := corev1.Container{
-		Name:            name,
+		Name:            "indexmanagement",
 		Image:           image,
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		Resources: corev1.ResourceRequirements{
@@ -296,31 +362,24 @@ func newContainer(clusterName, name, image, scriptPath string, envvars []corev1.
 				corev1.ResourceCPU:    defaultCPURequest,
 			},
 		},
-		WorkingDir: workingDir,
-		Env:        envvars,
-		Command:    []string{"bash"},
-		Args: []string{
-			"-c",
-			scriptPath,
-		},
+		Env: envvars,
 		VolumeMounts: []corev1.VolumeMount{
 			{Name: "certs", ReadOnly: true, MountPath: "/etc/indexmanagement/keys"},
-			{Name: "scripts", ReadOnly: false, MountPath: workingDir},
 		},
 	}
 
 	return container
 }
 
-func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelector map[string]string, tolerations []corev1.Toleration, envvars []corev1.EnvVar) *batch.CronJob {
-	containerName := "indexmanagement"
+func newCertsVolume(clusterName string) corev1.Volume {
+	return corev1.Volume{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: clusterName}}}
+}
+
+func newCronJob(clusterName, namespace, name, schedule string, labels, nodeSelector map[string]string, tolerations []corev1.Toleration, container corev1.Container, volumes ...corev1.Volume) *batch.CronJob {
 	podSpec := corev1.PodSpec{
-		ServiceAccountName: clusterName,
-		Containers:         []corev1.Container{newContainer(clusterName, containerName, constants.PackagedElasticsearchImage(), script, envvars)},
-		Volumes: []corev1.Volume{
-			{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: clusterName}}},
-			{Name: "scripts", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: indexManagementConfigmap}, DefaultMode: &fullExecMode}}},
-		},
+		ServiceAccountName:            clusterName,
+		Containers:                    []corev1.Container{container},
+		Volumes:                       volumes,
 		NodeSelector:                  utils.EnsureLinuxNodeSelector(nodeSelector),
 		Tolerations:                   tolerations,
 		RestartPolicy:                 corev1.RestartPolicyNever,
@@ -334,7 +393,7 @@ func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelec
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
-			Labels:    imLabels,
+			Labels:    labels,
 		},
 		Spec: batch.CronJobSpec{
 			ConcurrencyPolicy:          batch.ForbidConcurrent,
@@ -342,14 +401,17 @@ func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelec
 			FailedJobsHistoryLimit:     jobHistoryLimitFailed,
 			Schedule:                   schedule,
 			JobTemplate: batch.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: newJobLabels(clusterName, labels),
+				},
 				Spec: batchv1.JobSpec{
 					BackoffLimit: utils.GetInt32(0),
 					Parallelism:  utils.GetInt32(1),
 					Template: corev1.PodTemplateSpec{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      containerName,
+							Name:      container.Name,
 							Namespace: namespace,
-							Labels:    imLabels,
+							Labels:    labels,
 						},
 						Spec: podSpec,
 					},
@@ -360,3 +422,14 @@ func newCronJob(clusterName, namespace, name, schedule, script string, nodeSelec
 
 	return cronJob
 }
+
+// newJobLabels returns the labels of the jobs of the cronjobs, which map a job to its cluster
+func newJobLabels(clusterName string, labels map[string]string) map[string]string {
+	jobLabels := map[string]string{
+		clusterNameLabel: clusterName,
+	}
+	for name, value := range labels {
+		jobLabels[name] = value
+	}
+	return jobLabels
+}
@@ -1,6 +1,7 @@
 package indexmanagement
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
@@ -8,7 +9,9 @@ import (
 
 	batch "k8s.io/api/batch/v1beta1"
 	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -51,34 +54,91 @@ var _ = Describe("Index Management", func() {
 		selector := map[string]string{}
 		tolerations := []core.Toleration{}
 		name := fmt.Sprintf("%s-im-%s", cluster.Name, mapping.Name)
-		cronjob = newCronJob(cluster.Name, cluster.Namespace, name, "*/5 * * * *", "", selector, tolerations, []core.EnvVar{})
+		cronjob = newCronJob(cluster.Name, cluster.Namespace, name, "*/5 * * * *", imLabels, selector, tolerations, newJobContainer(cluster.Name, nil, []core.EnvVar{}), newCertsVolume(cluster.Name))
 	})
-	Describe("#formatCmd", func() {
+	Describe("#formatPhases", func() {
 		Context("with no policies", func() {
-			It("should return an empty command", func() {
-				Expect(formatCmd(apis.IndexManagementPolicySpec{})).To(BeEmpty())
+			It("should return no phases", func() {
+				Expect(formatPhases(apis.IndexManagementPolicySpec{})).To(BeEmpty())
 			})
 		})
 		Context("with delete phase", func() {
-			It("should format the command for delete", func() {
+			It("should run the delete phase", func() {
 				policy.Phases.Delete = &apis.IndexManagementDeletePhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./delete;delete_rc=$?;$(exit $delete_rc)"))
+				Expect(formatPhases(policy)).To(Equal([]string{"delete"}))
 			})
 		})
 		Context("with rollover phase", func() {
-			It("should format the command for rollover", func() {
+			It("should run the rollover phase", func() {
 				policy.Phases.Hot = &apis.IndexManagementHotPhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./rollover;rollover_rc=$?;$(exit $rollover_rc)"))
+				Expect(formatPhases(policy)).To(Equal([]string{"rollover"}))
+			})
+		})
+		Context("with warm and cold phases", func() {
+			It("should run the cold phase before the warm phase", func() {
+				policy.Phases.Warm = &apis.IndexManagementWarmPhaseSpec{}
+				policy.Phases.Cold = &apis.IndexManagementColdPhaseSpec{}
+				Expect(formatPhases(policy)).To(Equal([]string{"cold", "warm"}))
 			})
 		})
 		Context("with delete and rollover phases", func() {
-			It("should format the command for all phases", func() {
+			It("should run all phases", func() {
 				policy.Phases.Delete = &apis.IndexManagementDeletePhaseSpec{}
 				policy.Phases.Hot = &apis.IndexManagementHotPhaseSpec{}
-				Expect(formatCmd(policy)).To(Equal("./delete;delete_rc=$?;./rollover;rollover_rc=$?;$(exit $delete_rc&&exit $rollover_rc)"))
+				Expect(formatPhases(policy)).To(Equal([]string{"delete", "rollover"}))
 			})
 		})
 	})
+	Describe("#newJobContainer", func() {
+		It("should run the phases with the operator binary", func() {
+			container := newJobContainer(cluster.Name, []string{"delete", "rollover"}, []core.EnvVar{})
+			Expect(container.Command).To(Equal([]string{"elasticsearch-operator"}))
+			Expect(container.Args).To(Equal([]string{"indexmanagement", "delete", "rollover"}))
+			Expect(container.Env).To(ContainElement(core.EnvVar{Name: "ES_SERVICE", Value: "https://mycluster:9200"}))
+		})
+	})
+	Describe("#newPhaseEnvVars", func() {
+		It("should error for an unsupported minimum age", func() {
+			_, err := newPhaseEnvVars("WARM", "2M", "", apis.IndexManagementPhaseActionsSpec{})
+			Expect(err).ToNot(BeNil())
+		})
+		It("should only define the ages when no actions are spec'd", func() {
+			Expect(newPhaseEnvVars("WARM", "1d", "2d", apis.IndexManagementPhaseActionsSpec{})).To(Equal([]core.EnvVar{
+				{Name: "WARM_MIN_AGE", Value: "86400000"},
+				{Name: "WARM_MAX_AGE", Value: "172800000"},
+			}))
+		})
+		It("should define the env vars for each action", func() {
+			actions := apis.IndexManagementPhaseActionsSpec{
+				ForceMerge: &apis.IndexManagementForceMergeActionSpec{MaxNumSegments: 1},
+				Shrink:     &apis.IndexManagementShrinkActionSpec{NumberOfShards: 2},
+				ReadOnly:   true,
+				Replicas:   &apis.IndexManagementReplicasActionSpec{NumberOfReplicas: 0},
+				Allocation: &apis.IndexManagementAllocationActionSpec{Require: map[string]string{"box_type": "cold"}},
+			}
+			Expect(newPhaseEnvVars("COLD", "1d", "", actions)).To(Equal([]core.EnvVar{
+				{Name: "COLD_MIN_AGE", Value: "86400000"},
+				{Name: "COLD_NUMBER_OF_REPLICAS", Value: "0"},
+				{Name: "COLD_READ_ONLY", Value: "true"},
+				{Name: "COLD_SHRINK_NUMBER_OF_SHARDS", Value: "2"},
+				{Name: "COLD_FORCE_MERGE_MAX_NUM_SEGMENTS", Value: "1"},
+				{Name: "COLD_ALLOCATION_REQUIRE", Value: `{"box_type":"cold"}`},
+			}))
+		})
+	})
+	Describe("#RemoveScriptsConfigmap", func() {
+		It("should delete the configmap of the scripts", func() {
+			configmap := &core.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "indexmanagement-scripts", Namespace: cluster.Namespace}}
+			apiclient = fake.NewFakeClient(configmap)
+
+			Expect(RemoveScriptsConfigmap(apiclient, cluster)).To(Succeed())
+			err := apiclient.Get(context.TODO(), types.NamespacedName{Name: configmap.Name, Namespace: configmap.Namespace}, &core.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+		It("should succeed when the configmap is already deleted", func() {
+			Expect(RemoveScriptsConfigmap(apiclient, cluster)).To(Succeed())
+		})
+	})
 	Describe("#reconcileCronJob", func() {
 		fnCronsAreSame := func(lhs, rhs *batch.CronJob) bool {
 			return true
@@ -129,7 +189,7 @@ var _ = Describe("Index Management", func() {
 			selector := map[string]string{}
 			tolerations := []core.Toleration{}
 			name := fmt.Sprintf("%s-rollover-%s", cluster.Name, policy.Name)
-			cronjob = newCronJob(cluster.Name, cluster.Namespace, name, "*/5 * * * *", "", selector, tolerations, []core.EnvVar{})
+			cronjob = newCronJob(cluster.Name, cluster.Namespace, name, "*/5 * * * *", imLabels, selector, tolerations, newJobContainer(cluster.Name, nil, []core.EnvVar{}), newCertsVolume(cluster.Name))
 			policy.Phases.Hot = &apis.IndexManagementHotPhaseSpec{
 				Actions: apis.IndexManagementActionsSpec{
 					Rollover: &apis.IndexManagementActionSpec{
@@ -0,0 +1,176 @@
+package indexmanagement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/job"
+)
+
+// RunsFailingThreshold is the number of consecutive failed jobs of a mapping after which
+// the runs of the mapping are reported as failing
+const RunsFailingThreshold = 3
+
+type finishedJob struct {
+	job       batchv1.Job
+	succeeded bool
+	time      metav1.Time
+}
+
+// IsIndexManagementJob returns the name of the cluster of an index management job
+func IsIndexManagementJob(labels map[string]string) (string, bool) {
+	for name, value := range imLabels {
+		if labels[name] != value {
+			return "", false
+		}
+	}
+	clusterName, ok := labels[clusterNameLabel]
+	return clusterName, ok
+}
+
+// GetLastRunStatus returns the outcome of the last finished job of the cronjob of the mapping
+// or nil if none finished. The consecutive failures are bound by the failed jobs history limit
+func GetLastRunStatus(apiclient client.Client, cluster *apis.Elasticsearch, mapping apis.IndexManagementPolicyMappingSpec) (*apis.IndexManagementRunStatus, error) {
+	cronJobName := fmt.Sprintf("%s-im-%s", cluster.Name, mapping.Name)
+	jobs, err := listFinishedJobs(apiclient, cluster, cronJobName)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+
+	last := jobs[0]
+	status := &apis.IndexManagementRunStatus{
+		JobName: last.job.Name,
+		Time:    last.time,
+		Outcome: apis.IndexManagementRunSucceeded,
+	}
+	if !last.succeeded {
+		status.Outcome = apis.IndexManagementRunFailed
+	}
+	for _, finished := range jobs {
+		if finished.succeeded {
+			break
+		}
+		status.ConsecutiveFailures++
+	}
+
+	message, err := getTerminationMessage(apiclient, last.job)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := job.ParseSummary(message)
+	if err != nil {
+		// the job did not get to write its summary
+		if !last.succeeded {
+			status.Message = getFailedMessage(last.job)
+		}
+		return status, nil
+	}
+	status.RolledOverIndices = summary.RolledOverIndices
+	status.DeletedIndices = summary.DeletedIndices
+	status.Message = strings.Join(summary.Errors, "; ")
+	return status, nil
+}
+
+// listFinishedJobs returns the finished jobs of the cronjob, latest first
+func listFinishedJobs(apiclient client.Client, cluster *apis.Elasticsearch, cronJobName string) ([]finishedJob, error) {
+	labels := newJobLabels(cluster.Name, imLabels)
+	jobList := &batchv1.JobList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels(labels),
+	}
+	if err := apiclient.List(context.TODO(), jobList, listOpts...); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list index management jobs",
+			"namespace", cluster.Namespace,
+			"labels", labels,
+		)
+	}
+
+	jobs := []finishedJob{}
+	for _, item := range jobList.Items {
+		if !isOwnedByCronJob(item, cronJobName) {
+			continue
+		}
+		for _, condition := range item.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			if condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed {
+				jobs = append(jobs, finishedJob{
+					job:       item,
+					succeeded: condition.Type == batchv1.JobComplete,
+					time:      condition.LastTransitionTime,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[j].time.Before(&jobs[i].time)
+	})
+	return jobs, nil
+}
+
+func isOwnedByCronJob(item batchv1.Job, cronJobName string) bool {
+	for _, ref := range item.OwnerReferences {
+		if ref.Kind == "CronJob" && ref.Name == cronJobName {
+			return true
+		}
+	}
+	return false
+}
+
+// getTerminationMessage returns the termination message of the container of the job
+func getTerminationMessage(apiclient client.Client, item batchv1.Job) (string, error) {
+	podList := &corev1.PodList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(item.Namespace),
+		client.MatchingLabels{"job-name": item.Name},
+	}
+	if err := apiclient.List(context.TODO(), podList, listOpts...); err != nil {
+		return "", kverrors.Wrap(err, "failed to list the pods of the index management job",
+			"namespace", item.Namespace,
+			"job", item.Name,
+		)
+	}
+
+	for _, pod := range podList.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "indexmanagement" && containerStatus.State.Terminated != nil {
+				return containerStatus.State.Terminated.Message, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func getFailedMessage(item batchv1.Job) string {
+	for _, condition := range item.Status.Conditions {
+		if condition.Type == batchv1.JobFailed {
+			return condition.Message
+		}
+	}
+	return ""
+}
+
+// NewRunsFailingCondition returns the condition of a mapping whose jobs keep failing, or nil
+func NewRunsFailingCondition(lastRun *apis.IndexManagementRunStatus) *apis.IndexManagementMappingCondition {
+	if lastRun == nil || lastRun.ConsecutiveFailures < RunsFailingThreshold {
+		return nil
+	}
+	return &apis.IndexManagementMappingCondition{
+		Type:    apis.IndexManagementMappingConditionTypeRunsFailing,
+		Reason:  apis.IndexManagementMappingReasonJobFailed,
+		Status:  corev1.ConditionTrue,
+		Message: fmt.Sprintf("The last %d index management jobs failed", lastRun.ConsecutiveFailures),
+	}
+}
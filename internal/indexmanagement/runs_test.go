@@ -0,0 +1,137 @@
+package indexmanagement
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("Index management runs", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster  *apis.Elasticsearch
+		mapping  = apis.IndexManagementPolicyMappingSpec{Name: "app", PolicyRef: "my-policy"}
+		finished = time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+	)
+	BeforeEach(func() {
+		cluster = &apis.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch",
+				Namespace: "openshift-logging",
+			},
+		}
+	})
+
+	newJob := func(name string, conditionType batchv1.JobConditionType, minutesAgo int) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       cluster.Namespace,
+				Labels:          newJobLabels(cluster.Name, imLabels),
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "elasticsearch-im-app"}},
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:               conditionType,
+						Status:             core.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(finished.Add(-time.Duration(minutesAgo) * time.Minute)),
+						Message:            "Job has reached the specified backoff limit",
+					},
+				},
+			},
+		}
+	}
+	newPod := func(jobName, message string) *core.Pod {
+		return &core.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName + "-abcde",
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"job-name": jobName},
+			},
+			Status: core.PodStatus{
+				ContainerStatuses: []core.ContainerStatus{
+					{
+						Name:  "indexmanagement",
+						State: core.ContainerState{Terminated: &core.ContainerStateTerminated{Message: message}},
+					},
+				},
+			},
+		}
+	}
+
+	Describe("#GetLastRunStatus", func() {
+		It("should return nil when no job finished", func() {
+			apiclient := fake.NewFakeClient()
+			Expect(GetLastRunStatus(apiclient, cluster, mapping)).To(BeNil())
+		})
+
+		It("should report the summary of the last successful job", func() {
+			objs := []runtime.Object{
+				newJob("elasticsearch-im-app-1", batchv1.JobFailed, 15),
+				newJob("elasticsearch-im-app-2", batchv1.JobComplete, 0),
+				newPod("elasticsearch-im-app-2", `{"rolledOverIndices":["app-000004"],"deletedIndices":2}`),
+			}
+			apiclient := fake.NewFakeClient(objs...)
+
+			status, err := GetLastRunStatus(apiclient, cluster, mapping)
+			Expect(err).To(BeNil())
+			Expect(status.JobName).To(Equal("elasticsearch-im-app-2"))
+			Expect(status.Outcome).To(Equal(apis.IndexManagementRunSucceeded))
+			Expect(status.RolledOverIndices).To(Equal([]string{"app-000004"}))
+			Expect(status.DeletedIndices).To(Equal(int32(2)))
+			Expect(status.ConsecutiveFailures).To(BeZero())
+			Expect(NewRunsFailingCondition(status)).To(BeNil())
+		})
+
+		It("should count the failed jobs since the last successful one", func() {
+			objs := []runtime.Object{
+				newJob("elasticsearch-im-app-1", batchv1.JobComplete, 60),
+				newJob("elasticsearch-im-app-2", batchv1.JobFailed, 45),
+				newJob("elasticsearch-im-app-3", batchv1.JobFailed, 30),
+				newJob("elasticsearch-im-app-4", batchv1.JobFailed, 15),
+				newPod("elasticsearch-im-app-4", `{"deletedIndices":0,"errors":["rollover app: failed to rollover index"]}`),
+			}
+			apiclient := fake.NewFakeClient(objs...)
+
+			status, err := GetLastRunStatus(apiclient, cluster, mapping)
+			Expect(err).To(BeNil())
+			Expect(status.JobName).To(Equal("elasticsearch-im-app-4"))
+			Expect(status.Outcome).To(Equal(apis.IndexManagementRunFailed))
+			Expect(status.ConsecutiveFailures).To(Equal(int32(3)))
+			Expect(status.Message).To(Equal("rollover app: failed to rollover index"))
+
+			condition := NewRunsFailingCondition(status)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Type).To(Equal(apis.IndexManagementMappingConditionTypeRunsFailing))
+			Expect(condition.Status).To(Equal(core.ConditionTrue))
+		})
+
+		It("should report the job failure when the job wrote no summary", func() {
+			apiclient := fake.NewFakeClient(newJob("elasticsearch-im-app-1", batchv1.JobFailed, 0))
+
+			status, err := GetLastRunStatus(apiclient, cluster, mapping)
+			Expect(err).To(BeNil())
+			Expect(status.Outcome).To(Equal(apis.IndexManagementRunFailed))
+			Expect(status.Message).To(Equal("Job has reached the specified backoff limit"))
+		})
+
+		It("should ignore the jobs of other mappings", func() {
+			other := newJob("elasticsearch-im-infra-1", batchv1.JobComplete, 0)
+			other.OwnerReferences[0].Name = "elasticsearch-im-infra"
+			apiclient := fake.NewFakeClient(other)
+
+			Expect(GetLastRunStatus(apiclient, cluster, mapping)).To(BeNil())
+		})
+	})
+})
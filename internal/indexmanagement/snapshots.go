@@ -0,0 +1,103 @@
+package indexmanagement
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	batch "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/job"
+)
+
+// SnapshotPrefix prefixes the names of the snapshots taken by the snapshot cronjob. Only
+// snapshots with this prefix are pruned
+const SnapshotPrefix = "scheduled"
+
+var snapshotLabels = map[string]string{
+	"provider":      "openshift",
+	"component":     "snapshot",
+	"logging-infra": "snapshot",
+}
+
+// ReconcileSnapshotCronJob creates or updates the cronjob taking the scheduled snapshots of the cluster
+func ReconcileSnapshotCronJob(apiclient client.Client, cluster *apis.Elasticsearch) error {
+	spec := cluster.Spec.Snapshot
+	envvars, err := newSnapshotEnvVars(spec)
+	if err != nil {
+		return kverrors.Wrap(err, "failed to reconcile snapshot cronjob",
+			"cluster", cluster.Name,
+			"namespace", cluster.Namespace)
+	}
+
+	container := newJobContainer(cluster.Name, []string{job.PhaseSnapshot}, envvars)
+	desired := newCronJob(cluster.Name, cluster.Namespace, snapshotCronJobName(cluster), spec.Schedule, snapshotLabels, cluster.Spec.Spec.NodeSelector, cluster.Spec.Spec.Tolerations, container, newCertsVolume(cluster.Name))
+	cluster.AddOwnerRefTo(desired)
+	return reconcileCronJob(apiclient, cluster, desired, areCronJobsSame)
+}
+
+// RemoveSnapshotCronJob deletes the snapshot cronjob of the cluster if it exists
+func RemoveSnapshotCronJob(apiclient client.Client, cluster *apis.Elasticsearch) error {
+	cronjob := &batch.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: batch.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotCronJobName(cluster),
+			Namespace: cluster.Namespace,
+		},
+	}
+	if err := apiclient.Delete(context.TODO(), cronjob); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to remove snapshot cronjob",
+			"cluster", cluster.Name,
+			"namespace", cluster.Namespace)
+	}
+	return nil
+}
+
+// ValidateSnapshotRetention returns the reasons why the retention of the scheduled snapshots
+// can not be applied
+func ValidateSnapshotRetention(retention apis.ElasticsearchSnapshotRetentionSpec) []string {
+	if retention.MaxAge == "" {
+		return nil
+	}
+	if _, err := calculateMillisForTimeUnit(retention.MaxAge); err != nil {
+		return []string{fmt.Sprintf("Snapshot retention 'maxAge' of '%s' requires a time unit in weeks, days, hours, minutes or seconds (e.g. 30d)", retention.MaxAge)}
+	}
+	return nil
+}
+
+func snapshotCronJobName(cluster *apis.Elasticsearch) string {
+	return fmt.Sprintf("%s-snapshot", cluster.Name)
+}
+
+func newSnapshotEnvVars(spec *apis.ElasticsearchSnapshotSpec) ([]corev1.EnvVar, error) {
+	indices := "*"
+	if len(spec.Indices) > 0 {
+		indices = strings.Join(spec.Indices, ",")
+	}
+	envvars := []corev1.EnvVar{
+		{Name: "REPOSITORY", Value: spec.Repository.Name},
+		{Name: "SNAPSHOT_PREFIX", Value: SnapshotPrefix},
+		{Name: "SNAPSHOT_INDICES", Value: indices},
+	}
+	if spec.Retention.MaxAge != "" {
+		maxAge, err := calculateMillisForTimeUnit(spec.Retention.MaxAge)
+		if err != nil {
+			return nil, err
+		}
+		envvars = append(envvars, corev1.EnvVar{Name: "SNAPSHOT_MAX_AGE", Value: strconv.FormatUint(maxAge, 10)})
+	}
+	if spec.Retention.MaxCount > 0 {
+		envvars = append(envvars, corev1.EnvVar{Name: "SNAPSHOT_MAX_COUNT", Value: strconv.Itoa(int(spec.Retention.MaxCount))})
+	}
+	return envvars, nil
+}
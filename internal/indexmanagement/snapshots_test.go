@@ -0,0 +1,107 @@
+package indexmanagement
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batch "k8s.io/api/batch/v1beta1"
+	core "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apis "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("Snapshots", func() {
+	defer GinkgoRecover()
+
+	var (
+		apiclient client.Client
+		cluster   *apis.Elasticsearch
+		key       = types.NamespacedName{Name: "mycluster-snapshot", Namespace: "somenamespace"}
+	)
+	BeforeEach(func() {
+		apiclient = fake.NewFakeClient()
+		cluster = &apis.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mycluster",
+				Namespace: "somenamespace",
+			},
+			Spec: apis.ElasticsearchSpec{
+				Snapshot: &apis.ElasticsearchSnapshotSpec{
+					Repository: apis.ElasticsearchSnapshotRepositorySpec{Name: "backups"},
+					Schedule:   "0 1 * * *",
+				},
+			},
+		}
+	})
+
+	Describe("#newSnapshotEnvVars", func() {
+		It("should snapshot all indices without retention by default", func() {
+			Expect(newSnapshotEnvVars(cluster.Spec.Snapshot)).To(Equal([]core.EnvVar{
+				{Name: "REPOSITORY", Value: "backups"},
+				{Name: "SNAPSHOT_PREFIX", Value: "scheduled"},
+				{Name: "SNAPSHOT_INDICES", Value: "*"},
+			}))
+		})
+		It("should define the indices and retention", func() {
+			cluster.Spec.Snapshot.Indices = []string{"app-*", "infra-*"}
+			cluster.Spec.Snapshot.Retention = apis.ElasticsearchSnapshotRetentionSpec{MaxAge: "1d", MaxCount: 7}
+			Expect(newSnapshotEnvVars(cluster.Spec.Snapshot)).To(Equal([]core.EnvVar{
+				{Name: "REPOSITORY", Value: "backups"},
+				{Name: "SNAPSHOT_PREFIX", Value: "scheduled"},
+				{Name: "SNAPSHOT_INDICES", Value: "app-*,infra-*"},
+				{Name: "SNAPSHOT_MAX_AGE", Value: "86400000"},
+				{Name: "SNAPSHOT_MAX_COUNT", Value: "7"},
+			}))
+		})
+		It("should error for an unsupported retention age", func() {
+			cluster.Spec.Snapshot.Retention.MaxAge = "1y"
+			_, err := newSnapshotEnvVars(cluster.Spec.Snapshot)
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("#ReconcileSnapshotCronJob", func() {
+		It("should create the cronjob with the spec'd schedule", func() {
+			Expect(ReconcileSnapshotCronJob(apiclient, cluster)).To(Succeed())
+
+			cronjob := &batch.CronJob{}
+			Expect(apiclient.Get(context.TODO(), key, cronjob)).To(Succeed())
+			Expect(cronjob.Spec.Schedule).To(Equal("0 1 * * *"))
+			Expect(cronjob.Labels).To(Equal(snapshotLabels))
+			Expect(cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"indexmanagement", "snapshot"}))
+		})
+		It("should update the schedule of an existing cronjob", func() {
+			Expect(ReconcileSnapshotCronJob(apiclient, cluster)).To(Succeed())
+			cluster.Spec.Snapshot.Schedule = "0 2 * * *"
+			Expect(ReconcileSnapshotCronJob(apiclient, cluster)).To(Succeed())
+
+			cronjob := &batch.CronJob{}
+			Expect(apiclient.Get(context.TODO(), key, cronjob)).To(Succeed())
+			Expect(cronjob.Spec.Schedule).To(Equal("0 2 * * *"))
+		})
+		It("should not be culled with the index management cronjobs", func() {
+			Expect(ReconcileSnapshotCronJob(apiclient, cluster)).To(Succeed())
+			Expect(RemoveCronJobsForMappings(apiclient, cluster, nil, apis.PolicyMap{})).To(Succeed())
+			Expect(apiclient.Get(context.TODO(), key, &batch.CronJob{})).To(Succeed())
+		})
+	})
+
+	Describe("#RemoveSnapshotCronJob", func() {
+		It("should remove the cronjob", func() {
+			Expect(ReconcileSnapshotCronJob(apiclient, cluster)).To(Succeed())
+			Expect(RemoveSnapshotCronJob(apiclient, cluster)).To(Succeed())
+			err := apiclient.Get(context.TODO(), key, &batch.CronJob{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+		It("should ignore a missing cronjob", func() {
+			Expect(RemoveSnapshotCronJob(apiclient, cluster)).To(Succeed())
+		})
+	})
+})
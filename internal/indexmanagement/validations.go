@@ -8,12 +8,18 @@ import (
 	esapi "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 )
 
-var reTimeUnit = regexp.MustCompile("^(?P<number>\\d+)(?P<unit>[yMwdhHms])$")
+var (
+	reTimeUnit = regexp.MustCompile("^(?P<number>\\d+)(?P<unit>[yMwdhHms])$")
+	reByteSize = regexp.MustCompile("^(?i)(?P<number>\\d+)(?P<unit>b|kb|mb|gb|tb|pb)$")
+)
 
 const (
 	pollIntervalFailMessage  = "The pollInterval is missing or requires a valid time unit (e.g. 3d)"
 	phaseTimeUnitFailMessage = "The %s phase '%s' is missing or requires a valid time unit (e.g. 3d)"
 	policyRefFailMessage     = "A policy mapping must reference a defined IndexManagement policy"
+	phaseOrderFailMessage    = "The %s phase 'minAge' must be less than the %s phase 'minAge'"
+	phaseActionFailMessage   = "The %s phase action '%s' requires '%s' to be %s"
+	rolloverFailMessage      = "The hot phase action 'rollover' requires at least one of 'maxAge', 'maxSize' or 'maxDocs'"
 )
 
 // VerifyAndNormalize validates the spec'd indexManagement and returns a spec which removes policies
@@ -63,10 +69,37 @@ func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagemen
 			status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypePollInterval, esapi.IndexManagementPolicyReasonMalformed, pollIntervalFailMessage)
 		}
 		if policy.Phases.Hot != nil {
-			if policy.Phases.Hot.Actions.Rollover == nil || !isValidTimeUnit(policy.Phases.Hot.Actions.Rollover.MaxAge) {
-				message := fmt.Sprintf(phaseTimeUnitFailMessage, "hot", "maxAge")
+			rollover := policy.Phases.Hot.Actions.Rollover
+			if rollover == nil || (rollover.MaxAge == "" && rollover.MaxSize == "" && rollover.MaxDocs == nil) {
+				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMissing, rolloverFailMessage)
+			} else {
+				if rollover.MaxAge != "" && !isValidTimeUnit(rollover.MaxAge) {
+					message := fmt.Sprintf(phaseTimeUnitFailMessage, "hot", "maxAge")
+					status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+				}
+				if rollover.MaxSize != "" && !reByteSize.MatchString(rollover.MaxSize) {
+					message := fmt.Sprintf(phaseActionFailMessage, "hot", "rollover", "maxSize", "a valid byte size (e.g. 50gb)")
+					status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed, message)
+				}
+				if rollover.MaxDocs != nil && *rollover.MaxDocs < 1 {
+					message := fmt.Sprintf(phaseActionFailMessage, "hot", "rollover", "maxDocs", "greater than 0")
+					status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed, message)
+				}
+			}
+		}
+		if policy.Phases.Warm != nil {
+			if !isValidTimeUnit(policy.Phases.Warm.MinAge) {
+				message := fmt.Sprintf(phaseTimeUnitFailMessage, "warm", "minAge")
 				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
 			}
+			validatePhaseActions(status, esapi.IndexManagementPhaseWarm, policy.Phases.Warm.Actions)
+		}
+		if policy.Phases.Cold != nil {
+			if !isValidTimeUnit(policy.Phases.Cold.MinAge) {
+				message := fmt.Sprintf(phaseTimeUnitFailMessage, "cold", "minAge")
+				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
+			}
+			validatePhaseActions(status, esapi.IndexManagementPhaseCold, policy.Phases.Cold.Actions)
 		}
 		if policy.Phases.Delete != nil {
 			if !isValidTimeUnit(policy.Phases.Delete.MinAge) {
@@ -74,6 +107,7 @@ func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagemen
 				status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed, message)
 			}
 		}
+		validatePhaseOrder(status, policy)
 		if len(status.Conditions) > 0 {
 			status.State = esapi.IndexManagementPolicyStateDropped
 			status.Reason = esapi.IndexManagementPolicyReasonConditionsNotMet
@@ -84,6 +118,53 @@ func validatePolicies(cluster *esapi.Elasticsearch, result *esapi.IndexManagemen
 	}
 }
 
+// validatePhaseActions ensures the values of the spec'd warm or cold phase actions
+// can be applied to an index
+func validatePhaseActions(status *esapi.IndexManagementPolicyStatus, phase esapi.IndexManagementPhaseName, actions esapi.IndexManagementPhaseActionsSpec) {
+	if actions.ForceMerge != nil && actions.ForceMerge.MaxNumSegments < 1 {
+		message := fmt.Sprintf(phaseActionFailMessage, phase, "forceMerge", "maxNumSegments", "greater than 0")
+		status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+	if actions.Shrink != nil && actions.Shrink.NumberOfShards < 1 {
+		message := fmt.Sprintf(phaseActionFailMessage, phase, "shrink", "numberOfShards", "greater than 0")
+		status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+	if actions.Replicas != nil && actions.Replicas.NumberOfReplicas < 0 {
+		message := fmt.Sprintf(phaseActionFailMessage, phase, "replicas", "numberOfReplicas", "0 or greater")
+		status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed, message)
+	}
+}
+
+// validatePhaseOrder ensures an index moves through the warm, cold and delete
+// phases in that order. Phases with malformed ages are reported elsewhere and skipped here
+func validatePhaseOrder(status *esapi.IndexManagementPolicyStatus, policy esapi.IndexManagementPolicySpec) {
+	type phaseAge struct {
+		name   esapi.IndexManagementPhaseName
+		millis uint64
+	}
+	ages := []phaseAge{}
+	appendAge := func(name esapi.IndexManagementPhaseName, minAge esapi.TimeUnit) {
+		if millis, err := calculateMillisForTimeUnit(minAge); err == nil {
+			ages = append(ages, phaseAge{name: name, millis: millis})
+		}
+	}
+	if policy.Phases.Warm != nil {
+		appendAge(esapi.IndexManagementPhaseWarm, policy.Phases.Warm.MinAge)
+	}
+	if policy.Phases.Cold != nil {
+		appendAge(esapi.IndexManagementPhaseCold, policy.Phases.Cold.MinAge)
+	}
+	if policy.Phases.Delete != nil {
+		appendAge(esapi.IndexManagementPhaseDelete, policy.Phases.Delete.MinAge)
+	}
+	for i := 1; i < len(ages); i++ {
+		if ages[i-1].millis >= ages[i].millis {
+			message := fmt.Sprintf(phaseOrderFailMessage, ages[i-1].name, ages[i].name)
+			status.AddPolicyCondition(esapi.IndexManagementPolicyConditionTypePhases, esapi.IndexManagementPolicyReasonOutOfOrder, message)
+		}
+	}
+}
+
 func isValidTimeUnit(time esapi.TimeUnit) bool {
 	return reTimeUnit.MatchString(string(time))
 }
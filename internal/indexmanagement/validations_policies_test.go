@@ -67,6 +67,36 @@ var _ = Describe("Index Management", func() {
 					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePollInterval, esapi.IndexManagementPolicyReasonMalformed).
 					withPolicyConditionMessage("The pollInterval is missing or requires a valid time unit (e.g. 3d)")
 			})
+			Context("warm phase", func() {
+				It("should spec a value", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "warm",
+						PollInterval: "15m",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Warm: &esapi.IndexManagementWarmPhaseSpec{},
+						},
+					})
+					expectStatus(cluster).hasPolicy("warm").
+						withPolicyState(esapi.IndexManagementPolicyStateDropped).
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed).
+						withPolicyConditionMessage("The warm phase 'minAge' is missing or requires a valid time unit (e.g. 3d)")
+				})
+			})
+			Context("cold phase", func() {
+				It("should spec a value", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "cold",
+						PollInterval: "15m",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Cold: &esapi.IndexManagementColdPhaseSpec{},
+						},
+					})
+					expectStatus(cluster).hasPolicy("cold").
+						withPolicyState(esapi.IndexManagementPolicyStateDropped).
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed).
+						withPolicyConditionMessage("The cold phase 'minAge' is missing or requires a valid time unit (e.g. 3d)")
+				})
+			})
 			It("should spec an acceptible time unit", func() {
 				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
 					Name:         "foo",
@@ -90,8 +120,40 @@ var _ = Describe("Index Management", func() {
 					})
 					expectStatus(cluster).hasPolicy("foo").
 						withPolicyState(esapi.IndexManagementPolicyStateDropped).
-						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeTimeUnit, esapi.IndexManagementPolicyReasonMalformed).
-						withPolicyConditionMessage("The hot phase 'maxAge' is missing or requires a valid time unit (e.g. 3d)")
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMissing).
+						withPolicyConditionMessage("The hot phase action 'rollover' requires at least one of 'maxAge', 'maxSize' or 'maxDocs'")
+				})
+				It("should spec at least one rollover condition", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "foo",
+						PollInterval: "10s",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Hot: &esapi.IndexManagementHotPhaseSpec{
+								Actions: esapi.IndexManagementActionsSpec{
+									Rollover: &esapi.IndexManagementActionSpec{},
+								},
+							},
+						},
+					})
+					expectStatus(cluster).hasPolicy("foo").
+						withPolicyState(esapi.IndexManagementPolicyStateDropped).
+						withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMissing).
+						withPolicyConditionMessage("The hot phase action 'rollover' requires at least one of 'maxAge', 'maxSize' or 'maxDocs'")
+				})
+				It("should accept a rollover without maxAge", func() {
+					validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+						Name:         "foo",
+						PollInterval: "10s",
+						Phases: esapi.IndexManagementPhasesSpec{
+							Hot: &esapi.IndexManagementHotPhaseSpec{
+								Actions: esapi.IndexManagementActionsSpec{
+									Rollover: &esapi.IndexManagementActionSpec{MaxSize: "50gb"},
+								},
+							},
+						},
+					})
+					expectStatus(cluster).hasPolicy("foo").
+						withPolicyState(esapi.IndexManagementPolicyStateAccepted)
 				})
 			})
 			Context("delete phase", func() {
@@ -114,7 +176,11 @@ var _ = Describe("Index Management", func() {
 					Name:         "foo",
 					PollInterval: "100ds",
 					Phases: esapi.IndexManagementPhasesSpec{
-						Hot: &esapi.IndexManagementHotPhaseSpec{},
+						Hot: &esapi.IndexManagementHotPhaseSpec{
+							Actions: esapi.IndexManagementActionsSpec{
+								Rollover: &esapi.IndexManagementActionSpec{MaxAge: "3dd"},
+							},
+						},
 					},
 				})
 				expectStatus(cluster).hasPolicy("foo").
@@ -123,6 +189,109 @@ var _ = Describe("Index Management", func() {
 					withPolicyConditionMessage("The hot phase 'maxAge' is missing or requires a valid time unit (e.g. 3d)")
 			})
 		})
+		Context("Phase order", func() {
+			It("should require the warm phase before the cold phase", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Warm: &esapi.IndexManagementWarmPhaseSpec{MinAge: "5d"},
+						Cold: &esapi.IndexManagementColdPhaseSpec{MinAge: "2d"},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePhases, esapi.IndexManagementPolicyReasonOutOfOrder).
+					withPolicyConditionMessage("The warm phase 'minAge' must be less than the cold phase 'minAge'")
+			})
+			It("should require the cold phase before the delete phase", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Cold:   &esapi.IndexManagementColdPhaseSpec{MinAge: "7d"},
+						Delete: &esapi.IndexManagementDeletePhaseSpec{MinAge: "7d"},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypePhases, esapi.IndexManagementPolicyReasonOutOfOrder).
+					withPolicyConditionMessage("The cold phase 'minAge' must be less than the delete phase 'minAge'")
+			})
+		})
+		Context("Phase actions", func() {
+			It("should spec a valid byte size for the rollover maxSize", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Hot: &esapi.IndexManagementHotPhaseSpec{
+							Actions: esapi.IndexManagementActionsSpec{
+								Rollover: &esapi.IndexManagementActionSpec{MaxAge: "1d", MaxSize: "50g"},
+							},
+						},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The hot phase action 'rollover' requires 'maxSize' to be a valid byte size (e.g. 50gb)")
+			})
+			It("should spec a positive number of documents for the rollover maxDocs", func() {
+				maxDocs := int32(0)
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Hot: &esapi.IndexManagementHotPhaseSpec{
+							Actions: esapi.IndexManagementActionsSpec{
+								Rollover: &esapi.IndexManagementActionSpec{MaxAge: "1d", MaxDocs: &maxDocs},
+							},
+						},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The hot phase action 'rollover' requires 'maxDocs' to be greater than 0")
+			})
+			It("should spec a positive number of segments for forceMerge", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Warm: &esapi.IndexManagementWarmPhaseSpec{
+							MinAge: "2d",
+							Actions: esapi.IndexManagementPhaseActionsSpec{
+								ForceMerge: &esapi.IndexManagementForceMergeActionSpec{},
+							},
+						},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The warm phase action 'forceMerge' requires 'maxNumSegments' to be greater than 0")
+			})
+			It("should spec a positive number of shards for shrink", func() {
+				validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
+					Name:         "foo",
+					PollInterval: "15m",
+					Phases: esapi.IndexManagementPhasesSpec{
+						Cold: &esapi.IndexManagementColdPhaseSpec{
+							MinAge: "2d",
+							Actions: esapi.IndexManagementPhaseActionsSpec{
+								Shrink: &esapi.IndexManagementShrinkActionSpec{},
+							},
+						},
+					},
+				})
+				expectStatus(cluster).hasPolicy("foo").
+					withPolicyState(esapi.IndexManagementPolicyStateDropped).
+					withPolicyCondition(esapi.IndexManagementPolicyConditionTypeActions, esapi.IndexManagementPolicyReasonMalformed).
+					withPolicyConditionMessage("The cold phase action 'shrink' requires 'numberOfShards' to be greater than 0")
+			})
+		})
 		It("should accept a valid policy", func() {
 			validatePoliciesForSpec(esapi.IndexManagementPolicySpec{
 				Name:         "foo",
@@ -131,10 +300,25 @@ var _ = Describe("Index Management", func() {
 					Hot: &esapi.IndexManagementHotPhaseSpec{
 						Actions: esapi.IndexManagementActionsSpec{
 							Rollover: &esapi.IndexManagementActionSpec{
-								MaxAge: "3d",
+								MaxAge:  "3d",
+								MaxSize: "50GB",
 							},
 						},
 					},
+					Warm: &esapi.IndexManagementWarmPhaseSpec{
+						MinAge: "3d",
+						Actions: esapi.IndexManagementPhaseActionsSpec{
+							ForceMerge: &esapi.IndexManagementForceMergeActionSpec{MaxNumSegments: 1},
+							ReadOnly:   true,
+						},
+					},
+					Cold: &esapi.IndexManagementColdPhaseSpec{
+						MinAge: "5d",
+						Actions: esapi.IndexManagementPhaseActionsSpec{
+							Shrink:   &esapi.IndexManagementShrinkActionSpec{NumberOfShards: 1},
+							Replicas: &esapi.IndexManagementReplicasActionSpec{NumberOfReplicas: 0},
+						},
+					},
 					Delete: &esapi.IndexManagementDeletePhaseSpec{
 						MinAge: "7d",
 					},
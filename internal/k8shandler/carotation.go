@@ -0,0 +1,177 @@
+package k8shandler
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"math/big"
+	"time"
+
+	"github.com/ViaQ/logerr/log"
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// caRotationPhase is the step of the CA rotation the nodes are restarted for. Every step keeps
+// the nodes trusting each other, so the nodes restart one at a time
+type caRotationPhase string
+
+const (
+	caRotationNone caRotationPhase = ""
+	// caRotationDistributingTrust adds the next CA to the trust bundle
+	caRotationDistributingTrust caRotationPhase = "DistributingTrust"
+	// caRotationReissuingCerts issues the certificates with the next CA, which becomes the CA
+	caRotationReissuingCerts caRotationPhase = "ReissuingCerts"
+	// caRotationDroppingTrust removes the previous CA from the trust bundle
+	caRotationDroppingTrust caRotationPhase = "DroppingTrust"
+)
+
+// trustBundle returns the previous, current and next CA, in that order so the bundle only
+// changes when a CA is added or removed
+func (ca *certCA) trustBundle() []byte {
+	bundle := [][]byte{}
+	if len(ca.previousCert) > 0 {
+		bundle = append(bundle, ca.previousCert)
+	}
+	bundle = append(bundle, ca.cert)
+	if ca.next != nil {
+		bundle = append(bundle, ca.next.cert)
+	}
+	return bytes.Join(bundle, nil)
+}
+
+// parseNextCA returns the CA staged to replace the CA of the signing secret or nil if
+// there is no valid one
+func parseNextCA(secret *v1.Secret) *certCA {
+	next := &certificate{}
+	if err := unmarshalCert(secret.Data[esCANextCertName], secret.Data[esCANextKeyName], next); err != nil {
+		return nil
+	}
+	if !isValidCA(next.x509Cert, next.privKey) {
+		return nil
+	}
+	pubKeySHA1 := sha1.Sum(x509.MarshalPKCS1PublicKey(&next.privKey.PublicKey))
+	return &certCA{
+		certificate: certificate{
+			cert:     next.cert,
+			key:      next.key,
+			x509Cert: next.x509Cert,
+			privKey:  next.privKey,
+		},
+		serial:     big.NewInt(0),
+		pubKeySHA1: pubKeySHA1[:],
+	}
+}
+
+// rotateCABefore returns how long before it expires the CA is rotated. The certificates
+// issued until then do not outlive the CA
+func (cr *CertificateRequest) rotateCABefore(ca *x509.Certificate) time.Duration {
+	certLifetime := cr.certNotAfter(ca.NotBefore, nil).Sub(ca.NotBefore)
+	halfCALifetime := ca.NotAfter.Sub(ca.NotBefore) / 2
+	if certLifetime < halfCALifetime {
+		return certLifetime
+	}
+	return halfCALifetime
+}
+
+// RotateCA advances the rotation of the CA by one step. Rolled out is true when every node
+// runs with the current secret, which is required before taking the next step
+func (cr *CertificateRequest) RotateCA(rolledOut bool) error {
+	ca := &certCA{}
+	if err := cr.ensureCA(ca); err != nil {
+		return err
+	}
+
+	switch ca.rotation {
+	case caRotationNone:
+		if time.Now().Before(ca.x509Cert.NotAfter.Add(-cr.rotateCABefore(ca.x509Cert))) {
+			return nil
+		}
+		next, err := genCA(cr.caNotAfter(time.Now()))
+		if err != nil {
+			return err
+		}
+		ca.next = next
+		ca.rotation = caRotationDistributingTrust
+
+	case caRotationDistributingTrust:
+		if ca.next == nil {
+			next, err := genCA(cr.caNotAfter(time.Now()))
+			if err != nil {
+				return err
+			}
+			ca.next = next
+			break
+		}
+		if !rolledOut {
+			return nil
+		}
+		ca.previousCert = ca.cert
+		ca.certificate = certificate{
+			cert:     ca.next.cert,
+			key:      ca.next.key,
+			x509Cert: ca.next.x509Cert,
+			privKey:  ca.next.privKey,
+		}
+		ca.serial = ca.next.serial
+		ca.pubKeySHA1 = ca.next.pubKeySHA1
+		ca.next = nil
+		ca.rotation = caRotationReissuingCerts
+
+	case caRotationReissuingCerts:
+		if !rolledOut {
+			return nil
+		}
+		ca.previousCert = nil
+		ca.rotation = caRotationDroppingTrust
+
+	case caRotationDroppingTrust:
+		if !rolledOut {
+			return nil
+		}
+		ca.rotation = caRotationNone
+
+	default:
+		log.Info("Restarting the rotation of the CA from an unknown phase", "phase", ca.rotation)
+		ca.next = nil
+		ca.previousCert = nil
+		ca.rotation = caRotationNone
+	}
+
+	log.Info("Rotating the CA", "cluster", cr.ClusterName, "namespace", cr.Namespace, "phase", ca.rotation)
+	return cr.persistCA(ca)
+}
+
+// isCARotationInProgress returns true while the secret changes for a step of the rotation of the CA
+func (er *ElasticsearchRequest) isCARotationInProgress() bool {
+	cr := NewCertificateRequest(er.cluster.Name, er.cluster.Namespace, er.cluster.GetOwnerRef(), er.client)
+	secret, err := getSecret(cr.getSigningSecretName(), er.cluster.Namespace, er.client)
+	if err != nil {
+		return false
+	}
+	return caRotationPhase(secret.Data[esCARotationName]) != caRotationNone
+}
+
+// isSecretRolledOut returns true if every node runs with the current secret of the cluster
+func (er *ElasticsearchRequest) isSecretRolledOut() bool {
+	clusterNodes := nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)]
+	if len(clusterNodes) == 0 {
+		return false
+	}
+
+	secretHash := getSecretDataHash(er.cluster.Name, er.cluster.Namespace, er.client)
+	for _, node := range clusterNodes {
+		if node.getSecretHash() != secretHash {
+			return false
+		}
+	}
+	for _, nodeStatus := range er.cluster.Status.Nodes {
+		if nodeStatus.UpgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue ||
+			nodeStatus.UpgradeStatus.UnderUpgrade == v1.ConditionTrue {
+			return false
+		}
+	}
+	return !containsClusterCondition(api.Restarting, v1.ConditionTrue, &er.cluster.Status) &&
+		!containsClusterCondition(api.Recovering, v1.ConditionTrue, &er.cluster.Status)
+}
@@ -0,0 +1,174 @@
+package k8shandler
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("carotation.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		cr        *CertificateRequest
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+		k8sClient = fake.NewFakeClient()
+		cr = NewCertificateRequest(cluster.Name, cluster.Namespace, cluster.GetOwnerRef(), k8sClient)
+	})
+
+	getSigningSecretData := func() map[string][]byte {
+		secret, err := getSecret(cr.getSigningSecretName(), cluster.Namespace, k8sClient)
+		Expect(err).To(BeNil())
+		return secret.Data
+	}
+	setRotation := func(phase caRotationPhase) {
+		data := getSigningSecretData()
+		data[esCARotationName] = []byte(phase)
+		Expect(CreateOrUpdateSecretWithOwnerRef(cr.getSigningSecretName(), cluster.Namespace, data, k8sClient, cr.OwnerRef)).To(Succeed())
+	}
+
+	Describe("#RotateCA", func() {
+		BeforeEach(func() {
+			Expect(cr.RotateCA(true)).To(Succeed())
+		})
+
+		It("should not rotate a CA far from expiring", func() {
+			data := getSigningSecretData()
+			Expect(data).ToNot(HaveKey(esCARotationName))
+			Expect(data).ToNot(HaveKey(esCANextCertName))
+		})
+
+		It("should keep trusting the current CA through every step of the rotation", func() {
+			current := getSigningSecretData()[esCACertName]
+			setRotation(caRotationDistributingTrust)
+
+			By("staging the next CA")
+			Expect(cr.RotateCA(true)).To(Succeed())
+			data := getSigningSecretData()
+			Expect(data[esCANextCertName]).ToNot(BeEmpty())
+			next := data[esCANextCertName]
+
+			bundle, err := cr.getCACertBytes()
+			Expect(err).To(BeNil())
+			Expect(bundle).To(Equal(append(append([]byte{}, current...), next...)))
+
+			By("waiting for the trust bundle to roll out")
+			Expect(cr.RotateCA(false)).To(Succeed())
+			Expect(getSigningSecretData()[esCANextCertName]).To(Equal(next))
+
+			By("signing with the next CA")
+			Expect(cr.RotateCA(true)).To(Succeed())
+			data = getSigningSecretData()
+			Expect(string(data[esCARotationName])).To(Equal(string(caRotationReissuingCerts)))
+			Expect(data[esCACertName]).To(Equal(next))
+			Expect(data[esCAPreviousCertName]).To(Equal(current))
+			Expect(data).ToNot(HaveKey(esCANextCertName))
+
+			bundle, err = cr.getCACertBytes()
+			Expect(err).To(BeNil())
+			Expect(bundle).To(Equal(append(append([]byte{}, current...), next...)))
+
+			By("dropping the previous CA")
+			Expect(cr.RotateCA(true)).To(Succeed())
+			data = getSigningSecretData()
+			Expect(string(data[esCARotationName])).To(Equal(string(caRotationDroppingTrust)))
+			Expect(data).ToNot(HaveKey(esCAPreviousCertName))
+
+			bundle, err = cr.getCACertBytes()
+			Expect(err).To(BeNil())
+			Expect(bundle).To(Equal(next))
+
+			By("completing the rotation")
+			Expect(cr.RotateCA(true)).To(Succeed())
+			Expect(getSigningSecretData()).ToNot(HaveKey(esCARotationName))
+		})
+
+		It("should reissue the certificates with the next CA", func() {
+			// the certificates are read back from their secret on every reconcile
+			ensureCert := func(cert *certificate) *certificate {
+				Expect(cr.EnsureCert(esComponentName, cert)).To(Succeed())
+				stored := &certificate{}
+				Expect(unmarshalCert(cert.cert, cert.key, stored)).To(Succeed())
+				return stored
+			}
+			cert := ensureCert(&certificate{})
+			original := cert.cert
+
+			setRotation(caRotationDistributingTrust)
+			Expect(cr.RotateCA(true)).To(Succeed())
+			cert = ensureCert(cert)
+			Expect(cert.cert).To(Equal(original))
+
+			Expect(cr.RotateCA(true)).To(Succeed())
+			cert = ensureCert(cert)
+			Expect(bytes.Equal(cert.cert, original)).To(BeFalse())
+		})
+	})
+
+	Describe("lifetimes", func() {
+		It("should issue the CA and the certificates with the configured lifetimes", func() {
+			cr.SetCertificatesSpec(&api.ElasticsearchCertificatesSpec{
+				CALifetime: &metav1.Duration{Duration: 720 * time.Hour},
+				Lifetime:   &metav1.Duration{Duration: 72 * time.Hour},
+			})
+			cert := &certificate{}
+			Expect(cr.EnsureCert(esComponentName, cert)).To(Succeed())
+
+			ca := &certCA{}
+			Expect(cr.ensureCA(ca)).To(Succeed())
+			Expect(ca.x509Cert.NotAfter).To(BeTemporally("~", time.Now().Add(720*time.Hour), time.Minute))
+			Expect(cert.x509Cert.NotAfter).To(BeTemporally("~", time.Now().Add(72*time.Hour), time.Minute))
+		})
+
+		It("should not issue certificates that outlive the CA", func() {
+			cr.SetCertificatesSpec(&api.ElasticsearchCertificatesSpec{
+				CALifetime: &metav1.Duration{Duration: 72 * time.Hour},
+			})
+			cert := &certificate{}
+			Expect(cr.EnsureCert(esComponentName, cert)).To(Succeed())
+
+			ca := &certCA{}
+			Expect(cr.ensureCA(ca)).To(Succeed())
+			Expect(cert.x509Cert.NotAfter).To(BeTemporally("<=", ca.x509Cert.NotAfter))
+		})
+
+		It("should skip invalid lifetimes for the defaults", func() {
+			cr.SetCertificatesSpec(&api.ElasticsearchCertificatesSpec{
+				Lifetime:    &metav1.Duration{Duration: time.Hour},
+				RenewBefore: &metav1.Duration{Duration: 2 * time.Hour},
+				CertManager: &api.CertManagerSpec{IssuerRef: api.CertManagerIssuerReference{Name: "issuer"}},
+			})
+			Expect(cr.Lifetimes).To(Equal(CertLifetimes{}))
+			Expect(cr.IssuerRef).ToNot(BeNil())
+		})
+
+		It("should reject lifetimes the CA can not rotate with", func() {
+			Expect(validateCertificates(&api.ElasticsearchCertificatesSpec{
+				CALifetime: &metav1.Duration{Duration: 72 * time.Hour},
+				Lifetime:   &metav1.Duration{Duration: 720 * time.Hour},
+			})).To(ConsistOf(ContainSubstring("'lifetime' must be shorter than 'caLifetime'")))
+			Expect(validateCertificates(&api.ElasticsearchCertificatesSpec{
+				Lifetime:    &metav1.Duration{Duration: time.Hour},
+				RenewBefore: &metav1.Duration{Duration: 2 * time.Hour},
+			})).To(ConsistOf(ContainSubstring("'renewBefore' must be shorter than 'lifetime'")))
+			Expect(validateCertificates(&api.ElasticsearchCertificatesSpec{
+				RenewBefore: &metav1.Duration{Duration: -time.Hour},
+			})).To(ConsistOf(ContainSubstring("'renewBefore' must be a positive duration")))
+		})
+	})
+})
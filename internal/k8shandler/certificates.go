@@ -14,10 +14,12 @@ import (
 	mathRand "math/rand"
 	"net"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +38,11 @@ type certCA struct {
 	certificate
 	serial     *big.Int
 	pubKeySHA1 []byte
+
+	// the CA replacing this one and the CA it replaced while the CA rotates
+	next         *certCA
+	previousCert []byte
+	rotation     caRotationPhase
 }
 
 type x509v3Ext struct {
@@ -64,6 +71,11 @@ const (
 	esCACertName      = "cert"
 	esCASerialName    = "serial"
 
+	esCANextCertName     = "next-cert"
+	esCANextKeyName      = "next-key"
+	esCAPreviousCertName = "previous-cert"
+	esCARotationName     = "rotation"
+
 	esComponentName     = "elasticsearch"
 	esComponentKeyName  = "elasticsearch.key"
 	esComponentCertName = "elasticsearch.crt"
@@ -90,6 +102,8 @@ const (
 	kibanaSecretName = "kibana"
 
 	kibanaSessionSecretLength = 32
+
+	defaultRenewBefore = time.Hour
 )
 
 var (
@@ -120,6 +134,11 @@ type CertificateRequest struct {
 	OwnerRef    metav1.OwnerReference
 	K8sClient   client.Client
 
+	// IssuerRef is the cert-manager issuer of the certificates. The operator's CA issues
+	// them when nil
+	IssuerRef *logging.CertManagerIssuerReference
+	Lifetimes CertLifetimes
+
 	Extensions map[string]x509v3Ext
 }
 
@@ -160,11 +179,115 @@ func NewCertificateRequest(clusterName, namespace string, ownerRef metav1.OwnerR
 	}
 }
 
+// CertLifetimes are the validity periods of the certificates. Zero values are the defaults
+type CertLifetimes struct {
+	CA          time.Duration
+	Cert        time.Duration
+	RenewBefore time.Duration
+}
+
+// SetCertificatesSpec configures the issuer and the lifetimes of the certificates. Invalid
+// lifetimes are skipped for the defaults and reported by the InvalidCertificates condition
+func (cr *CertificateRequest) SetCertificatesSpec(spec *logging.ElasticsearchCertificatesSpec) {
+	cr.IssuerRef = nil
+	cr.Lifetimes = CertLifetimes{}
+	if spec == nil {
+		return
+	}
+	if spec.CertManager != nil {
+		cr.IssuerRef = &spec.CertManager.IssuerRef
+	}
+	if len(validateCertificates(spec)) == 0 {
+		cr.Lifetimes = newCertLifetimes(spec)
+	}
+}
+
+func newCertLifetimes(spec *logging.ElasticsearchCertificatesSpec) CertLifetimes {
+	lifetimes := CertLifetimes{}
+	if spec.CALifetime != nil {
+		lifetimes.CA = spec.CALifetime.Duration
+	}
+	if spec.Lifetime != nil {
+		lifetimes.Cert = spec.Lifetime.Duration
+	}
+	if spec.RenewBefore != nil {
+		lifetimes.RenewBefore = spec.RenewBefore.Duration
+	}
+	return lifetimes
+}
+
+func (cr *CertificateRequest) renewBefore() time.Duration {
+	if cr.Lifetimes.RenewBefore > 0 {
+		return cr.Lifetimes.RenewBefore
+	}
+	return defaultRenewBefore
+}
+
+// caNotAfter returns the expiration of a CA issued at now
+func (cr *CertificateRequest) caNotAfter(now time.Time) time.Time {
+	if cr.Lifetimes.CA > 0 {
+		return now.Add(cr.Lifetimes.CA)
+	}
+	return now.AddDate(caNotAfterYears, 0, 0)
+}
+
+// certNotAfter returns the expiration of a certificate issued at now, which does not outlive its CA
+func (cr *CertificateRequest) certNotAfter(now time.Time, ca *x509.Certificate) time.Time {
+	notAfter := now.AddDate(compNotAfterYears, 0, -1)
+	if cr.Lifetimes.Cert > 0 {
+		notAfter = now.Add(cr.Lifetimes.Cert)
+	}
+	if ca != nil && notAfter.After(ca.NotAfter) {
+		return ca.NotAfter
+	}
+	return notAfter
+}
+
+// validateCertificates returns the reasons why the lifetimes of the certificates can not be used
+func validateCertificates(spec *logging.ElasticsearchCertificatesSpec) []string {
+	if spec == nil {
+		return nil
+	}
+
+	var reasons []string
+	for name, lifetime := range map[string]*metav1.Duration{
+		"caLifetime":  spec.CALifetime,
+		"lifetime":    spec.Lifetime,
+		"renewBefore": spec.RenewBefore,
+	} {
+		if lifetime != nil && lifetime.Duration <= 0 {
+			reasons = append(reasons, fmt.Sprintf("Certificates '%s' must be a positive duration", name))
+		}
+	}
+	if len(reasons) > 0 {
+		sort.Strings(reasons)
+		return reasons
+	}
+
+	cr := &CertificateRequest{Lifetimes: newCertLifetimes(spec)}
+	now := time.Now()
+	certNotAfter := cr.certNotAfter(now, nil)
+	if spec.CertManager == nil && !certNotAfter.Before(cr.caNotAfter(now)) {
+		reasons = append(reasons, "Certificates 'lifetime' must be shorter than 'caLifetime'")
+	}
+	if !now.Add(cr.renewBefore()).Before(certNotAfter) {
+		reasons = append(reasons, "Certificates 'renewBefore' must be shorter than 'lifetime'")
+	}
+	return reasons
+}
+
 func (cr *CertificateRequest) getSigningSecretName() string {
 	return fmt.Sprintf("signing-%s", cr.ClusterName)
 }
 
 func (cr *CertificateRequest) GenerateComponentCerts(secretName, cn string) {
+	if cr.IssuerRef != nil {
+		if err := cr.generateComponentCertsWithCertManager(secretName, cn); err != nil {
+			log.Error(err, "Unable to issue cert for component with cert-manager")
+		}
+		return
+	}
+
 	secret, err := getSecret(secretName, cr.Namespace, cr.K8sClient)
 	if err != nil && !apierrors.IsNotFound(err) {
 		log.Error(err, "unable to get secret")
@@ -201,6 +324,13 @@ func (cr *CertificateRequest) GenerateComponentCerts(secretName, cn string) {
 }
 
 func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
+	if cr.IssuerRef != nil {
+		if err := cr.generateKibanaCertsWithCertManager(); err != nil {
+			log.Error(err, "Unable to issue certs for kibana with cert-manager")
+		}
+		return
+	}
+
 	secret, err := getSecret(kibanaSecretName, cr.Namespace, cr.K8sClient)
 	if err != nil && !apierrors.IsNotFound(err) {
 		log.Error(err, "unable to get secret")
@@ -275,6 +405,13 @@ func (cr *CertificateRequest) GenerateKibanaCerts(componentName string) {
 }
 
 func (cr *CertificateRequest) GenerateElasticsearchCerts(clusterName string) {
+	if cr.IssuerRef != nil {
+		if err := cr.generateElasticsearchCertsWithCertManager(clusterName); err != nil {
+			log.Error(err, "Unable to issue certs for elasticsearch with cert-manager")
+		}
+		return
+	}
+
 	// get from secret
 	secret, err := getSecret(clusterName, cr.Namespace, cr.K8sClient)
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -346,6 +483,16 @@ func (cr *CertificateRequest) persistCA(caCert *certCA) error {
 		esCAKeyName:    caCert.key,
 		esCASerialName: []byte(caCert.serial.Text(10)),
 	}
+	if caCert.next != nil {
+		secretData[esCANextCertName] = caCert.next.cert
+		secretData[esCANextKeyName] = caCert.next.key
+	}
+	if len(caCert.previousCert) > 0 {
+		secretData[esCAPreviousCertName] = caCert.previousCert
+	}
+	if caCert.rotation != caRotationNone {
+		secretData[esCARotationName] = []byte(caCert.rotation)
+	}
 
 	return CreateOrUpdateSecretWithOwnerRef(secretName, cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef)
 }
@@ -374,12 +521,15 @@ func (cr *CertificateRequest) ensureCA(caCert *certCA) error {
 		caCert.key = ca.key
 		caCert.pubKeySHA1 = ca.pubKeySHA1
 		caCert.serial = ca.serial
+		caCert.next = ca.next
+		caCert.previousCert = ca.previousCert
+		caCert.rotation = ca.rotation
 	}
 
 	// check if the CA cert is invalid
 	if !isValidCA(caCert.x509Cert, caCert.privKey) {
 		// generate new CLO CA and populate the CA secret with it
-		ca, err := genCA()
+		ca, err := genCA(cr.caNotAfter(time.Now()))
 		if err != nil {
 			return err
 		}
@@ -390,6 +540,10 @@ func (cr *CertificateRequest) ensureCA(caCert *certCA) error {
 		caCert.key = ca.key
 		caCert.pubKeySHA1 = ca.pubKeySHA1
 		caCert.serial = ca.serial
+		// the certificates of an invalid CA are not trusted anymore, there is nothing to overlap with
+		caCert.next = nil
+		caCert.previousCert = nil
+		caCert.rotation = caRotationNone
 
 		return cr.persistCA(caCert)
 	}
@@ -412,6 +566,8 @@ func (cr *CertificateRequest) incrementCertSerial(ca *certCA) (*big.Int, error)
 	return serial, nil
 }
 
+// getCACertBytes returns the trust bundle of the CA, which includes the CA it replaces and
+// the CA replacing it while the CA rotates
 func (cr *CertificateRequest) getCACertBytes() ([]byte, error) {
 	ca := &certCA{}
 	err := cr.ensureCA(ca)
@@ -419,12 +575,18 @@ func (cr *CertificateRequest) getCACertBytes() ([]byte, error) {
 		return []byte{}, err
 	}
 
-	return ca.cert, nil
+	return ca.trustBundle(), nil
 }
 
 func (cr *CertificateRequest) EnsureCert(componentName string, cert *certificate) error {
-	// validate that the cert isn't expired
-	if !isValidCert(cert.x509Cert, cert.privKey, componentName) {
+	ca := &certCA{}
+	if err := cr.ensureCA(ca); err != nil {
+		return err
+	}
+
+	// validate that the cert isn't expired and is issued by the current CA
+	if !isValidCert(cert.x509Cert, cert.privKey, componentName, cr.renewBefore()) ||
+		cert.x509Cert.CheckSignatureFrom(ca.x509Cert) != nil {
 		err := cr.generateCert(componentName, cert)
 		if err != nil {
 			return err
@@ -465,7 +627,7 @@ func (cr *CertificateRequest) generateCert(componentName string, cert *certifica
 			CommonName:         componentName,
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(compNotAfterYears, 0, -1),
+		NotAfter:              cr.certNotAfter(time.Now(), ca.x509Cert),
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		BasicConstraintsValid: true,
 		SubjectKeyId:          pubKeySHA1[:],
@@ -536,12 +698,12 @@ func (cr *CertificateRequest) generateCert(componentName string, cert *certifica
 	return nil
 }
 
-func certWillExpireSoon(cert *x509.Certificate) bool {
+func certWillExpireSoon(cert *x509.Certificate, renewBefore time.Duration) bool {
 	certExpiration := cert.NotAfter
-	return time.Now().After(certExpiration.Add(time.Hour * -1))
+	return time.Now().After(certExpiration.Add(-renewBefore))
 }
 
-func genCA() (*certCA, error) {
+func genCA(notAfter time.Time) (*certCA, error) {
 	caPrivKey, err := rsa.GenerateKey(rand.Reader, rsaKeyLength)
 	if err != nil {
 		return nil, err
@@ -558,7 +720,7 @@ func genCA() (*certCA, error) {
 			CommonName:         caCN,
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(caNotAfterYears, 0, 0),
+		NotAfter:              notAfter,
 		IsCA:                  true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
 		BasicConstraintsValid: true,
@@ -578,19 +740,18 @@ func genCA() (*certCA, error) {
 		return nil, err
 	}
 	return &certCA{
-		certificate{
-			caPEMBytes,
-			keyPEMBytes,
-			ca,
-			caPrivKey,
-			sync.Mutex{},
+		certificate: certificate{
+			cert:     caPEMBytes,
+			key:      keyPEMBytes,
+			x509Cert: ca,
+			privKey:  caPrivKey,
 		},
-		serial,
-		caPubKeySHA1[:],
+		serial:     serial,
+		pubKeySHA1: caPubKeySHA1[:],
 	}, nil
 }
 
-func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonName string) bool {
+func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonName string, renewBefore time.Duration) bool {
 	if x509Cert == nil {
 		return false
 	}
@@ -599,7 +760,7 @@ func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonN
 		return false
 	}
 
-	if certWillExpireSoon(x509Cert) {
+	if certWillExpireSoon(x509Cert, renewBefore) {
 		return false
 	}
 
@@ -624,7 +785,7 @@ func isValidCert(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey, commonN
 }
 
 func isValidCA(x509Cert *x509.Certificate, rsaPrivKey *rsa.PrivateKey) bool {
-	if !isValidCert(x509Cert, rsaPrivKey, caCN) {
+	if !isValidCert(x509Cert, rsaPrivKey, caCN, defaultRenewBefore) {
 		return false
 	}
 
@@ -779,15 +940,20 @@ func validateCASecret(secret *v1.Secret) (*certCA, error) {
 	if !isValidCA(x509Cert, rsaKey) {
 		return nil, fmt.Errorf("invalid CA")
 	}
-	return &certCA{
-		certificate{
-			certBytes,
-			keyBytes,
-			x509Cert,
-			rsaKey,
-			sync.Mutex{},
+	ca := &certCA{
+		certificate: certificate{
+			cert:     certBytes,
+			key:      keyBytes,
+			x509Cert: x509Cert,
+			privKey:  rsaKey,
 		},
-		serial,
-		pubKeySHA1[:],
-	}, nil
+		serial:       serial,
+		pubKeySHA1:   pubKeySHA1[:],
+		previousCert: secret.Data[esCAPreviousCertName],
+		rotation:     caRotationPhase(secret.Data[esCARotationName]),
+	}
+
+	// an invalid next CA is staged again by the rotation
+	ca.next = parseNextCA(secret)
+	return ca, nil
 }
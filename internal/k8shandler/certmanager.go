@@ -0,0 +1,274 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	logging "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+const (
+	certManagerGroup = "cert-manager.io"
+
+	// the keys of the secrets cert-manager issues the certificates into
+	certManagerCertName = "tls.crt"
+	certManagerKeyName  = "tls.key"
+	certManagerCAName   = "ca.crt"
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   certManagerGroup,
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// issuedCert is the material of a certificate issued by cert-manager
+type issuedCert struct {
+	cert []byte
+	key  []byte
+	ca   []byte
+}
+
+// GetCertManagerIssuerRef returns the cert-manager issuer of the certificates of the cluster or nil
+// if the operator issues them
+func GetCertManagerIssuerRef(cluster *logging.Elasticsearch) *logging.CertManagerIssuerReference {
+	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.CertManager == nil {
+		return nil
+	}
+	return &cluster.Spec.Certificates.CertManager.IssuerRef
+}
+
+// getNodesDN returns the distinguished names of the node certificates when they are issued by
+// cert-manager, which is unable to add the OID the nodes are otherwise identified by
+func getNodesDN(cluster *logging.Elasticsearch) []string {
+	if GetCertManagerIssuerRef(cluster) == nil {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("CN=%s,OU=%s,O=%s", esComponentName, componentOrganizationUnit[0], certOrganization[0]),
+	}
+}
+
+func (cr *CertificateRequest) getIssuedCertName(componentName string) string {
+	return fmt.Sprintf("%s-issued-%s", cr.ClusterName, componentName)
+}
+
+// newCertManagerCertificate returns the cert-manager Certificate of the component. The subject
+// matches the certificates of the operator's CA which the Elasticsearch configuration relies on
+func (cr *CertificateRequest) newCertManagerCertificate(name, commonName string) *unstructured.Unstructured {
+	issuerKind := cr.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = logging.CertManagerIssuer
+	}
+
+	spec := map[string]interface{}{
+		"secretName": name,
+		"commonName": commonName,
+		"subject": map[string]interface{}{
+			"organizations":       toInterfaceSlice(certOrganization),
+			"organizationalUnits": toInterfaceSlice(componentOrganizationUnit),
+		},
+		"privateKey": map[string]interface{}{
+			"algorithm": "RSA",
+			"encoding":  "PKCS1",
+			"size":      int64(rsaKeyLength),
+		},
+		"usages": []interface{}{"digital signature", "key encipherment", "server auth", "client auth"},
+		"issuerRef": map[string]interface{}{
+			"name":  cr.IssuerRef.Name,
+			"kind":  string(issuerKind),
+			"group": certManagerGroup,
+		},
+	}
+
+	if cr.Lifetimes.Cert > 0 {
+		spec["duration"] = cr.Lifetimes.Cert.String()
+	}
+	if cr.Lifetimes.RenewBefore > 0 {
+		spec["renewBefore"] = cr.Lifetimes.RenewBefore.String()
+	}
+
+	ext := cr.Extensions[commonName]
+	if len(ext.dns) > 0 {
+		spec["dnsNames"] = toInterfaceSlice(ext.dns)
+	}
+	if len(ext.ips) > 0 {
+		ips := make([]interface{}, 0, len(ext.ips))
+		for _, ip := range ext.ips {
+			ips = append(ips, ip.String())
+		}
+		spec["ipAddresses"] = ips
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certManagerCertificateGVK)
+	certificate.SetName(name)
+	certificate.SetNamespace(cr.Namespace)
+	certificate.SetOwnerReferences(append(certificate.GetOwnerReferences(), cr.OwnerRef))
+	certificate.Object["spec"] = spec
+	return certificate
+}
+
+// issueCert ensures the cert-manager Certificate of the component exists and returns the issued
+// material, or nil while cert-manager has not issued the certificate yet
+func (cr *CertificateRequest) issueCert(componentName string) (*issuedCert, error) {
+	name := cr.getIssuedCertName(componentName)
+	if err := cr.createOrUpdateCertManagerCertificate(cr.newCertManagerCertificate(name, componentName)); err != nil {
+		return nil, err
+	}
+
+	secret, err := getSecret(name, cr.Namespace, cr.K8sClient)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, kverrors.Wrap(err, "failed to get the issued certificate", "secret", name)
+	}
+
+	issued := &issuedCert{
+		cert: secret.Data[certManagerCertName],
+		key:  secret.Data[certManagerKeyName],
+		ca:   secret.Data[certManagerCAName],
+	}
+	if len(issued.cert) == 0 || len(issued.key) == 0 {
+		return nil, nil
+	}
+	if len(issued.ca) == 0 {
+		return nil, kverrors.New("the issuer does not provide its CA with the certificate", "secret", name)
+	}
+	return issued, nil
+}
+
+func (cr *CertificateRequest) createOrUpdateCertManagerCertificate(desired *unstructured.Unstructured) error {
+	err := cr.K8sClient.Create(context.TODO(), desired)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return kverrors.Wrap(err, "failed to create cert-manager certificate",
+			"name", desired.GetName(),
+			"namespace", desired.GetNamespace())
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(certManagerCertificateGVK)
+		if err := cr.K8sClient.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+			return nil
+		}
+		current.Object["spec"] = desired.Object["spec"]
+		return cr.K8sClient.Update(context.TODO(), current)
+	})
+	return kverrors.Wrap(err, "failed to update cert-manager certificate",
+		"name", desired.GetName(),
+		"namespace", desired.GetNamespace())
+}
+
+// issueCerts returns the issued certificates of the components or nil until all are issued
+func (cr *CertificateRequest) issueCerts(componentNames ...string) (map[string]*issuedCert, error) {
+	certs := map[string]*issuedCert{}
+	issued := true
+	for _, componentName := range componentNames {
+		cert, err := cr.issueCert(componentName)
+		if err != nil {
+			return nil, err
+		}
+		if cert == nil {
+			issued = false
+			continue
+		}
+		certs[componentName] = cert
+	}
+	if !issued {
+		log.Info("Waiting for cert-manager to issue the certificates", "cluster", cr.ClusterName, "components", componentNames)
+		return nil, nil
+	}
+	return certs, nil
+}
+
+func (cr *CertificateRequest) generateElasticsearchCertsWithCertManager(secretName string) error {
+	certs, err := cr.issueCerts(esAdminComponentName, esComponentName, esInternalComponentName)
+	if err != nil || certs == nil {
+		return err
+	}
+
+	secretData := map[string][]byte{
+		esComponentKeyName:  certs[esComponentName].key,
+		esComponentCertName: certs[esComponentName].cert,
+		esInternalKeyName:   certs[esInternalComponentName].key,
+		esInternalCertname:  certs[esInternalComponentName].cert,
+		esAdminKeyName:      certs[esAdminComponentName].key,
+		esAdminCertName:     certs[esAdminComponentName].cert,
+		esAdminCAName:       certs[esAdminComponentName].ca,
+	}
+	return CreateOrUpdateSecretWithOwnerRef(secretName, cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef)
+}
+
+func (cr *CertificateRequest) generateComponentCertsWithCertManager(secretName, cn string) error {
+	certs, err := cr.issueCerts(cn)
+	if err != nil || certs == nil {
+		return err
+	}
+
+	componentSecretData := map[string][]byte{
+		componentKeyName:  certs[cn].key,
+		componentCertName: certs[cn].cert,
+		componentCAName:   certs[cn].ca,
+	}
+	return CreateOrUpdateSecretWithOwnerRef(secretName, cr.Namespace, componentSecretData, cr.K8sClient, cr.OwnerRef)
+}
+
+func (cr *CertificateRequest) generateKibanaCertsWithCertManager() error {
+	certs, err := cr.issueCerts(kibanaComponentName, kibanaInternalComponentName)
+	if err != nil || certs == nil {
+		return err
+	}
+
+	kibanaSecretData := map[string][]byte{
+		kibanaComponentKeyName:  certs[kibanaComponentName].key,
+		kibanaComponentCertName: certs[kibanaComponentName].cert,
+		kibanaComponentCAName:   certs[kibanaComponentName].ca,
+	}
+	if err := CreateOrUpdateSecretWithOwnerRef(kibanaSecretName, cr.Namespace, kibanaSecretData, cr.K8sClient, cr.OwnerRef); err != nil {
+		return err
+	}
+
+	proxySecretName := getKibanaProxySecretName(kibanaSecretName)
+	secret, err := getSecret(proxySecretName, cr.Namespace, cr.K8sClient)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	kibanaProxySessionSecret := &sessionSecret{}
+	kibanaProxySessionSecret.secret = secret.Data[kibanaInternalSessionSecretName]
+	if err := ensureSessionSecret(kibanaSessionSecretLength, allowedRunes, kibanaProxySessionSecret); err != nil {
+		return err
+	}
+
+	secretData := map[string][]byte{
+		kibanaInternalSessionSecretName: kibanaProxySessionSecret.secret,
+		kibanaInternalCertName:          certs[kibanaInternalComponentName].cert,
+		kibanaInternalKeyName:           certs[kibanaInternalComponentName].key,
+	}
+	return CreateOrUpdateSecretWithOwnerRef(proxySecretName, cr.Namespace, secretData, cr.K8sClient, cr.OwnerRef)
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+package k8shandler
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+)
+
+var _ = Describe("certmanager.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		cr        *CertificateRequest
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Certificates: &api.ElasticsearchCertificatesSpec{
+					CertManager: &api.CertManagerSpec{
+						IssuerRef: api.CertManagerIssuerReference{Name: "logging-ca", Kind: api.CertManagerClusterIssuer},
+					},
+				},
+			},
+		}
+		k8sClient = fake.NewFakeClient()
+		cr = NewCertificateRequest(cluster.Name, cluster.Namespace, cluster.GetOwnerRef(), k8sClient)
+		cr.IssuerRef = GetCertManagerIssuerRef(cluster)
+	})
+
+	getCertificate := func(name string) *unstructured.Unstructured {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certManagerCertificateGVK)
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cluster.Namespace}, certificate)).To(Succeed())
+		return certificate
+	}
+	nestedField := func(certificate *unstructured.Unstructured, fields ...string) interface{} {
+		value, _, err := unstructured.NestedFieldCopy(certificate.Object, fields...)
+		Expect(err).To(BeNil())
+		return value
+	}
+	issue := func(name, cert string) {
+		secret := newSecret(name, cluster.Namespace, map[string][]byte{
+			certManagerCertName: []byte(cert),
+			certManagerKeyName:  []byte(cert + "-key"),
+			certManagerCAName:   []byte("issuer-ca"),
+		})
+		Expect(k8sClient.Create(context.TODO(), secret)).To(Succeed())
+	}
+
+	Describe("#GenerateElasticsearchCerts", func() {
+		It("should request the certificates from the issuer", func() {
+			cr.GenerateElasticsearchCerts(cluster.Name)
+
+			certificate := getCertificate("elasticsearch-issued-elasticsearch")
+			Expect(nestedField(certificate, "spec", "commonName")).To(Equal("elasticsearch"))
+			Expect(nestedField(certificate, "spec", "secretName")).To(Equal("elasticsearch-issued-elasticsearch"))
+			Expect(nestedField(certificate, "spec", "issuerRef")).To(Equal(map[string]interface{}{
+				"name":  "logging-ca",
+				"kind":  "ClusterIssuer",
+				"group": "cert-manager.io",
+			}))
+			Expect(nestedField(certificate, "spec", "dnsNames")).To(ConsistOf(
+				"localhost", "elasticsearch-cluster", "elasticsearch-cluster.openshift-logging.svc",
+			))
+			Expect(certificate.GetOwnerReferences()).To(HaveLen(1))
+
+			certificate = getCertificate("elasticsearch-issued-system.admin")
+			Expect(nestedField(certificate, "spec", "subject", "organizationalUnits")).To(Equal([]interface{}{"Logging"}))
+			Expect(nestedField(certificate, "spec", "subject", "organizations")).To(Equal([]interface{}{"OpenShift"}))
+			getCertificate("elasticsearch-issued-logging-es")
+		})
+
+		It("should not create the secret until all certificates are issued", func() {
+			issue("elasticsearch-issued-elasticsearch", "elasticsearch-cert")
+
+			cr.GenerateElasticsearchCerts(cluster.Name)
+
+			_, err := getSecret(cluster.Name, cluster.Namespace, k8sClient)
+			Expect(err).ToNot(BeNil())
+		})
+
+		It("should assemble the secret from the issued certificates", func() {
+			issue("elasticsearch-issued-elasticsearch", "elasticsearch-cert")
+			issue("elasticsearch-issued-logging-es", "logging-es-cert")
+			issue("elasticsearch-issued-system.admin", "admin-cert")
+
+			cr.GenerateElasticsearchCerts(cluster.Name)
+
+			secret, err := getSecret(cluster.Name, cluster.Namespace, k8sClient)
+			Expect(err).To(BeNil())
+			for _, key := range constants.ExpectedSecretKeys {
+				Expect(secret.Data).To(HaveKey(key))
+			}
+			Expect(string(secret.Data["elasticsearch.crt"])).To(Equal("elasticsearch-cert"))
+			Expect(string(secret.Data["logging-es.key"])).To(Equal("logging-es-cert-key"))
+			Expect(string(secret.Data["admin-cert"])).To(Equal("admin-cert"))
+			Expect(string(secret.Data["admin-ca"])).To(Equal("issuer-ca"))
+		})
+
+		It("should update the certificates when the issuer changes", func() {
+			cr.GenerateElasticsearchCerts(cluster.Name)
+			cr.IssuerRef = &api.CertManagerIssuerReference{Name: "other-ca"}
+
+			cr.GenerateElasticsearchCerts(cluster.Name)
+
+			certificate := getCertificate("elasticsearch-issued-elasticsearch")
+			Expect(nestedField(certificate, "spec", "issuerRef", "name")).To(Equal("other-ca"))
+			Expect(nestedField(certificate, "spec", "issuerRef", "kind")).To(Equal("Issuer"))
+		})
+	})
+
+	Describe("#GenerateKibanaCerts", func() {
+		It("should assemble the kibana secrets from the issued certificates", func() {
+			issue("elasticsearch-issued-system.logging.kibana", "kibana-cert")
+			issue("elasticsearch-issued-kibana-internal", "kibana-internal-cert")
+
+			cr.GenerateKibanaCerts("kibana")
+
+			secret, err := getSecret("kibana", cluster.Namespace, k8sClient)
+			Expect(err).To(BeNil())
+			Expect(string(secret.Data["cert"])).To(Equal("kibana-cert"))
+			Expect(string(secret.Data["ca"])).To(Equal("issuer-ca"))
+
+			secret, err = getSecret("kibana-proxy", cluster.Namespace, k8sClient)
+			Expect(err).To(BeNil())
+			Expect(string(secret.Data["server-cert"])).To(Equal("kibana-internal-cert"))
+			Expect(secret.Data["session-secret"]).To(HaveLen(kibanaSessionSecretLength))
+		})
+	})
+
+	Describe("#getNodesDN", func() {
+		It("should identify the nodes by their DN with cert-manager", func() {
+			nodesDN := getNodesDN(cluster)
+			Expect(nodesDN).To(Equal([]string{"CN=elasticsearch,OU=Logging,O=OpenShift"}))
+
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, nil, nil, nodesDN, false)).To(Succeed())
+			Expect(result.String()).To(ContainSubstring("  nodes_dn:\n  - CN=elasticsearch,OU=Logging,O=OpenShift\n"))
+		})
+
+		It("should rely on the OID of the operator's certificates", func() {
+			cluster.Spec.Certificates = nil
+			Expect(getNodesDN(cluster)).To(BeNil())
+		})
+	})
+
+	It("should keep the secret data of the component secrets", func() {
+		issue("elasticsearch-issued-fluentd", "fluentd-cert")
+
+		cr.GenerateComponentCerts("fluentd", "fluentd")
+
+		secret, err := getSecret("fluentd", cluster.Namespace, k8sClient)
+		Expect(err).To(BeNil())
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			"tls.crt":       []byte("fluentd-cert"),
+			"tls.key":       []byte("fluentd-cert-key"),
+			"ca-bundle.crt": []byte("issuer-ca"),
+		}))
+	})
+})
@@ -0,0 +1,164 @@
+package k8shandler
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// certReloadTimeout bounds how long the nodes are asked to reload their certificates. The
+// kubelet updates the mounted secret within its sync period and the security plugin refuses to
+// reload the certificates until then
+var certReloadTimeout = 90 * time.Second
+
+const (
+	certReloadPendingMessage = "Waiting for the nodes to reload the renewed certificates"
+
+	// elasticsearchKeystoresPath holds the keystores of the security plugin, which the nodes
+	// build from the mounted secret when they start
+	elasticsearchKeystoresPath = "/etc/elasticsearch/secret"
+)
+
+// canReloadCertificates returns true if the nodes trust the CA of the secret, which leaves
+// only renewed certificates to push to the nodes
+func (er *ElasticsearchRequest) canReloadCertificates(certNodes []NodeTypeInterface) bool {
+	// the keystores of the nodes are rebuilt in their pods
+	if er.podExec == nil {
+		return false
+	}
+
+	caHash := getCADataHash(er.cluster.Name, er.cluster.Namespace, er.client)
+	for _, node := range certNodes {
+		if node.getCAHash() == "" || node.getCAHash() != caHash {
+			return false
+		}
+	}
+	return true
+}
+
+// reloadCertificates asks every pod of the nodes to reload the certificates of the secret
+// instead of restarting the nodes. It returns false while the nodes still refuse the renewed
+// certificates, to be asked again on the next reconcile, and an error once they refused them
+// for longer than certReloadTimeout
+func (er *ElasticsearchRequest) reloadCertificates(certNodes []NodeTypeInterface) (bool, error) {
+	pods := []v1.Pod{}
+	for _, node := range certNodes {
+		podList, err := GetPodList(er.cluster.Namespace, map[string]string{
+			"cluster-name": er.cluster.Name,
+			"node-name":    node.name(),
+		}, er.client)
+		if err != nil {
+			return false, kverrors.Wrap(err, "failed to list the pods of the node",
+				"node", node.name())
+		}
+		if len(podList.Items) == 0 {
+			return false, kverrors.New("no pods found for the node",
+				"node", node.name())
+		}
+		for _, pod := range podList.Items {
+			if pod.Status.PodIP == "" {
+				return false, kverrors.New("pod has no address to reload the certificates",
+					"pod", pod.Name)
+			}
+			pods = append(pods, pod)
+		}
+	}
+
+	// the security plugin reloads the keystores it started with, which are rebuilt from the
+	// renewed certificates of the mounted secret first
+	var reloadErr error
+	failed := []string{}
+	for _, pod := range pods {
+		if _, err := er.podExec(pod.Namespace, pod.Name, "elasticsearch", newRebuildKeystoresCommand()); err != nil {
+			reloadErr = err
+			failed = append(failed, pod.Status.PodIP)
+			continue
+		}
+		if err := er.esClient.ReloadCertificates(pod.Status.PodIP); err != nil {
+			reloadErr = err
+			failed = append(failed, pod.Status.PodIP)
+		}
+	}
+
+	if len(failed) > 0 {
+		_, condition := getESNodeCondition(er.cluster.Status.Conditions, api.ReloadingCertificates)
+		if condition == nil {
+			if err := er.updateReloadingCertificatesCondition(v1.ConditionTrue); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+		if time.Since(condition.LastTransitionTime.Time) < certReloadTimeout {
+			return false, nil
+		}
+		if err := er.updateReloadingCertificatesCondition(v1.ConditionFalse); err != nil {
+			return false, err
+		}
+		return false, kverrors.Wrap(reloadErr, "timed out reloading the certificates",
+			"addresses", failed)
+	}
+
+	if err := er.updateReloadingCertificatesCondition(v1.ConditionFalse); err != nil {
+		return false, err
+	}
+
+	log.Info("Reloaded the certificates of the cluster", "cluster", er.cluster.Name, "namespace", er.cluster.Namespace)
+	for _, node := range certNodes {
+		node.refreshHashes()
+
+		clusterStatus := er.cluster.Status.DeepCopy()
+		_, nodeStatus := getNodeStatus(node.name(), clusterStatus)
+		nodeStatus.UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionFalse
+		if err := er.setNodeStatus(node, nodeStatus, clusterStatus); err != nil {
+			log.Error(err, "unable to update node status", "namespace", er.cluster.Namespace, "name", er.cluster.Name)
+		}
+	}
+	return true, nil
+}
+
+// updateReloadingCertificatesCondition records when the nodes were first asked to reload the
+// renewed certificates, so the timeout holds across reconciles and restarts of the operator
+func (er *ElasticsearchRequest) updateReloadingCertificatesCondition(value v1.ConditionStatus) error {
+	message := ""
+	if value == v1.ConditionTrue {
+		message = certReloadPendingMessage
+	}
+	return updateConditionWithRetry(er.cluster, value, func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+		return updateESNodeCondition(status, &api.ClusterCondition{
+			Type:    api.ReloadingCertificates,
+			Status:  value,
+			Message: message,
+		})
+	}, er.client)
+}
+
+// newRebuildKeystoresCommand returns the command rebuilding the transport and HTTP keystores of
+// a node from the certificates of the mounted secret. The truststores are left as is, the
+// certificates are only reloaded for an unchanged CA
+func newRebuildKeystoresCommand() []string {
+	keystores := []struct{ cert, key, name, keystore string }{
+		{esComponentCertName, esComponentKeyName, esComponentName, "searchguard-key.p12"},
+		{esInternalCertname, esInternalKeyName, esInternalComponentName, "key.p12"},
+	}
+
+	script := []string{"set -e"}
+	for _, k := range keystores {
+		keystore := path.Join(elasticsearchKeystoresPath, k.keystore)
+		script = append(script,
+			fmt.Sprintf("openssl pkcs12 -export -in %s -inkey %s -certfile %s -name %s -passout pass:kspass -out %s.new",
+				path.Join(elasticsearchCertsPath, k.cert),
+				path.Join(elasticsearchCertsPath, k.key),
+				path.Join(elasticsearchCertsPath, esAdminCAName),
+				k.name,
+				keystore),
+			fmt.Sprintf("mv -f %s.new %s", keystore, keystore))
+	}
+	return []string{"/bin/bash", "-c", strings.Join(script, "\n")}
+}
@@ -0,0 +1,196 @@
+package k8shandler
+
+import (
+	"context"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("certreload.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		chatter   *helpers.FakeElasticsearchChatter
+		request   *ElasticsearchRequest
+		node      NodeTypeInterface
+		execErr   error
+		executed  [][]string
+		key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+		nodeName  = "elasticsearch-cdm-abc-1"
+		roles     = map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleMaster: true, api.ElasticsearchRoleData: true}
+	)
+
+	updateSecret := func(data map[string][]byte) {
+		Expect(CreateOrUpdateSecretWithOwnerRef(key.Name, key.Namespace, data, k8sClient, cluster.GetOwnerRef())).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{
+						DeploymentName: nodeName,
+						UpgradeStatus:  api.ElasticsearchNodeUpgradeStatus{ScheduledForCertRedeploy: v1.ConditionTrue},
+					},
+				},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nodeName + "-xyz",
+				Namespace: key.Namespace,
+				Labels:    newLabels(key.Name, nodeName, roles),
+			},
+			Status: v1.PodStatus{PodIP: "10.128.2.12"},
+		}
+		k8sClient = fake.NewFakeClient(cluster, pod)
+		updateSecret(map[string][]byte{esAdminCAName: []byte("ca"), esComponentCertName: []byte("cert")})
+
+		esNode := api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData}}
+		node = newDeploymentNode(nodeName, esNode, cluster, roles, k8sClient, nil)
+		node.refreshHashes()
+		nodes = map[string][]NodeTypeInterface{nodeMapKey(key.Name, key.Namespace): {node}}
+
+		certReloadTimeout = time.Second
+		execErr = nil
+		executed = nil
+	})
+
+	JustBeforeEach(func() {
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(key.Name, key.Namespace, k8sClient, chatter),
+			podExec: func(namespace, pod, container string, command []string) (string, error) {
+				executed = append(executed, append([]string{pod, container}, command...))
+				return "", execErr
+			},
+		}
+	})
+
+	Describe("#canReloadCertificates", func() {
+		It("should reload renewed certificates", func() {
+			updateSecret(map[string][]byte{esAdminCAName: []byte("ca"), esComponentCertName: []byte("renewed")})
+			Expect(request.canReloadCertificates([]NodeTypeInterface{node})).To(BeTrue())
+		})
+
+		It("should restart the nodes for a new CA", func() {
+			updateSecret(map[string][]byte{esAdminCAName: []byte("new-ca"), esComponentCertName: []byte("renewed")})
+			Expect(request.canReloadCertificates([]NodeTypeInterface{node})).To(BeFalse())
+		})
+
+		It("should restart the nodes when the keystores can not be rebuilt in the pods", func() {
+			updateSecret(map[string][]byte{esAdminCAName: []byte("ca"), esComponentCertName: []byte("renewed")})
+			request.podExec = nil
+			Expect(request.canReloadCertificates([]NodeTypeInterface{node})).To(BeFalse())
+		})
+	})
+
+	Describe("#reloadCertificates", func() {
+		BeforeEach(func() {
+			updateSecret(map[string][]byte{esAdminCAName: []byte("ca"), esComponentCertName: []byte("renewed")})
+		})
+
+		Context("when the nodes reload the certificates", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+					"_opendistro/_security/api/ssl/transport/reloadcerts": {
+						{StatusCode: 500, Body: `{"error": "New certificates should not expire before the current ones."}`},
+						{StatusCode: 200, Body: `{"message": "updated transport certs"}`},
+					},
+					"_opendistro/_security/api/ssl/http/reloadcerts": {
+						{StatusCode: 200, Body: `{"message": "updated http certs"}`},
+					},
+				})
+			})
+
+			It("should retry until the renewed certificates are mounted and clear the cert redeploy", func() {
+				reloaded, err := request.reloadCertificates([]NodeTypeInterface{node})
+				Expect(err).To(BeNil())
+				Expect(reloaded).To(BeFalse())
+				_, condition := getESNodeCondition(cluster.Status.Conditions, api.ReloadingCertificates)
+				Expect(condition).ToNot(BeNil())
+
+				reloaded, err = request.reloadCertificates([]NodeTypeInterface{node})
+				Expect(err).To(BeNil())
+				Expect(reloaded).To(BeTrue())
+
+				req, found := chatter.GetRequest("_opendistro/_security/api/ssl/http/reloadcerts")
+				Expect(found).To(BeTrue())
+				Expect(req.Address).To(Equal("10.128.2.12"))
+
+				Expect(executed).To(HaveLen(2))
+				Expect(executed[1][:2]).To(Equal([]string{nodeName + "-xyz", "elasticsearch"}))
+				script := executed[1][len(executed[1])-1]
+				Expect(script).To(ContainSubstring("-in /etc/openshift/elasticsearch/secret/elasticsearch.crt -inkey /etc/openshift/elasticsearch/secret/elasticsearch.key"))
+				Expect(script).To(ContainSubstring("mv -f /etc/elasticsearch/secret/searchguard-key.p12.new /etc/elasticsearch/secret/searchguard-key.p12"))
+				Expect(script).To(ContainSubstring("mv -f /etc/elasticsearch/secret/key.p12.new /etc/elasticsearch/secret/key.p12"))
+
+				Expect(node.getSecretHash()).To(Equal(getSecretDataHash(key.Name, key.Namespace, k8sClient)))
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(current.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionFalse))
+				Expect(containsClusterCondition(api.ReloadingCertificates, v1.ConditionTrue, &current.Status)).To(BeFalse())
+			})
+		})
+
+		Context("when the nodes refuse the certificates", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+					"_opendistro/_security/api/ssl/transport/reloadcerts": {
+						{StatusCode: 500, Body: `{"error": "failed"}`},
+						{StatusCode: 500, Body: `{"error": "failed"}`},
+					},
+				})
+			})
+
+			It("should keep the nodes scheduled for a cert redeploy once the reload timed out", func() {
+				certReloadTimeout = 0
+				reloaded, err := request.reloadCertificates([]NodeTypeInterface{node})
+				Expect(err).To(BeNil())
+				Expect(reloaded).To(BeFalse())
+
+				_, err = request.reloadCertificates([]NodeTypeInterface{node})
+				Expect(err).ToNot(BeNil())
+				Expect(containsClusterCondition(api.ReloadingCertificates, v1.ConditionTrue, &cluster.Status)).To(BeFalse())
+
+				Expect(node.getSecretHash()).ToNot(Equal(getSecretDataHash(key.Name, key.Namespace, k8sClient)))
+				Expect(cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionTrue))
+			})
+		})
+
+		Context("when the keystores can not be rebuilt", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+				execErr = kverrors.New("openssl failed")
+			})
+
+			It("should not ask the nodes to reload the certificates", func() {
+				reloaded, err := request.reloadCertificates([]NodeTypeInterface{node})
+				Expect(err).To(BeNil())
+				Expect(reloaded).To(BeFalse())
+
+				Expect(chatter.Requests).To(BeEmpty())
+				Expect(cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionTrue))
+			})
+		})
+	})
+})
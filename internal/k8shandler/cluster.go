@@ -30,6 +30,26 @@ var aliasNeededMap map[string]bool
 
 func FlushNodes(clusterName, namespace string) {
 	nodes[nodeMapKey(clusterName, namespace)] = []NodeTypeInterface{}
+	delete(drainingNodes, nodeMapKey(clusterName, namespace))
+}
+
+// IsOperationInProgress returns true while the cluster restarts, upgrades, expands its volumes or
+// drains removed nodes. These operations wait on Elasticsearch, which no watch reports on
+func IsOperationInProgress(cluster *api.Elasticsearch) bool {
+	for _, conditionType := range []api.ClusterConditionType{api.Restarting, api.Recovering, api.UpdatingESSettings, api.StorageResizing} {
+		if containsClusterCondition(conditionType, v1.ConditionTrue, &cluster.Status) {
+			return true
+		}
+	}
+	for _, nodeStatus := range cluster.Status.Nodes {
+		upgradeStatus := nodeStatus.UpgradeStatus
+		if upgradeStatus.UnderUpgrade == v1.ConditionTrue ||
+			upgradeStatus.ScheduledForUpgrade == v1.ConditionTrue ||
+			upgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue {
+			return true
+		}
+	}
+	return len(drainingNodes[nodeMapKey(cluster.Name, cluster.Namespace)]) > 0
 }
 
 func nodeMapKey(clusterName, namespace string) string {
@@ -50,6 +70,7 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 			return nil
 		}
 		wrongConfig = true
+		er.recordEvent(v1.EventTypeWarning, eventReasonInvalidConfig, "Skipping the changes of the spec: %s", err.Error())
 		return err
 	}
 	wrongConfig = false
@@ -69,16 +90,55 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 	if err := er.UpdateClusterStatus(); err != nil {
 		return err
 	}
+
+	// Move the shards off the removed data nodes before deleting them
+	if err := er.decommissionRemovedNodes(); err != nil {
+		ll.Error(err, "unable to decommission removed nodes")
+	}
+
 	if err := er.progressUnschedulableNodes(); err != nil {
 		ll.Error(err, "unable to progress unschedulable nodes")
 		return er.UpdateClusterStatus()
 	}
 
+	// finish a restart interrupted by a restart of the operator before starting another one
+	if err := er.resumeRestart(); err != nil {
+		ll.Error(err, "unable to resume the restart of the cluster")
+		return er.UpdateClusterStatus()
+	}
+
 	certRestartNodes := er.getScheduledCertRedeployNodes()
 	stillRecovering := containsClusterCondition(api.Recovering, v1.ConditionTrue, &er.cluster.Status)
-	if len(certRestartNodes) > 0 || stillRecovering {
+	if len(certRestartNodes) > 0 && !stillRecovering && er.canReloadCertificates(certRestartNodes) {
+		// the CA is unchanged, the nodes can take the renewed certificates without a restart
+		reloaded, err := er.reloadCertificates(certRestartNodes)
+		switch {
+		case err != nil:
+			ll.Error(err, "unable to reload the certificates, restarting the nodes instead")
+			er.recordEvent(v1.EventTypeWarning, eventReasonCertsReloadFailed, "Unable to reload the certificates, restarting the nodes instead: %s", err.Error())
+		case !reloaded:
+			// the nodes are asked again on the next reconcile, once the kubelet mounted the renewed certificates
+			certRestartNodes = nil
+		default:
+			er.recordEvent(v1.EventTypeNormal, eventReasonCertsReloaded, "Reloaded the renewed certificates on %d nodes", len(certRestartNodes))
+			certRestartNodes = nil
+			_ = er.UpdateClusterStatus()
+		}
+	}
+	if len(certRestartNodes) > 0 && !stillRecovering && er.isCARotationInProgress() {
+		// the nodes keep trusting each other during the steps of the CA rotation
+		if er.getNodeUpgradeInProgress() == nil {
+			if err := er.PerformRollingCertRestart(certRestartNodes); err != nil {
+				logRestartError(ll, err, "unable to complete rolling restart for the CA rotation")
+				return er.UpdateClusterStatus()
+			}
+
+			metrics.IncrementRestartCounterCert()
+			_ = er.UpdateClusterStatus()
+		}
+	} else if len(certRestartNodes) > 0 || stillRecovering {
 		if err := er.PerformFullClusterCertRestart(certRestartNodes); err != nil {
-			ll.Error(err, "unable to complete full cluster restart")
+			logRestartError(ll, err, "unable to complete full cluster restart")
 			return er.UpdateClusterStatus()
 		}
 
@@ -129,12 +189,12 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 		if comparison > 0 {
 			// perform a full cluster update
 			if err := er.PerformFullClusterUpdate(scheduledNodes); err != nil {
-				log.Error(err, "failed to perform full cluster update")
+				logRestartError(ll, err, "failed to perform full cluster update")
 				return er.UpdateClusterStatus()
 			}
 		} else {
 			if err := er.PerformRollingUpdate(scheduledNodes); err != nil {
-				log.Error(err, "failed to perform rolling update")
+				logRestartError(ll, err, "failed to perform rolling update")
 				return er.UpdateClusterStatus()
 			}
 			metrics.IncrementRestartCounterRolling()
@@ -143,6 +203,18 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 		_ = er.UpdateClusterStatus()
 	}
 
+	// Restart the nodes with a volume waiting for a restart to finish expanding
+	if er.getNodeUpgradeInProgress() == nil {
+		if resizeNodes := er.getVolumeResizeRestartNodes(); len(resizeNodes) > 0 {
+			if err := er.PerformRollingRestart(resizeNodes); err != nil {
+				logRestartError(ll, err, "failed to perform rolling restart to expand volumes")
+				return er.UpdateClusterStatus()
+			}
+			metrics.IncrementRestartCounterRolling()
+			_ = er.UpdateClusterStatus()
+		}
+	}
+
 	if er.getNodeUpgradeInProgress() == nil {
 		// We have no updates or restarts in progress
 		// create any nodes we are missing and perform any required operations to ensure state
@@ -150,9 +222,13 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 			clusterStatus := er.cluster.Status.DeepCopy()
 			_, nodeStatus := getNodeStatus(node.name(), clusterStatus)
 
+			isMissing := node.isMissing()
 			if err := node.create(); err != nil {
 				return err
 			}
+			if isMissing {
+				er.recordEvent(v1.EventTypeNormal, eventReasonNodeCreated, "Created node %s", node.name())
+			}
 
 			addNodeState(node, nodeStatus)
 
@@ -197,6 +273,11 @@ func (er *ElasticsearchRequest) CreateOrUpdateElasticsearchCluster() error {
 		}
 	}
 
+	// the held restarts returned early, none is left for the maintenance windows
+	if err := er.clearMaintenancePending(); err != nil {
+		ll.Error(err, "unable to clear the pending maintenance")
+	}
+
 	// Scrape cluster health from elasticsearch every time
 	return er.UpdateClusterStatus()
 }
@@ -302,6 +383,9 @@ func (er *ElasticsearchRequest) populateNodes() error {
 	if nodes == nil {
 		nodes = make(map[string][]NodeTypeInterface)
 	}
+	if drainingNodes == nil {
+		drainingNodes = make(map[string][]NodeTypeInterface)
+	}
 
 	cluster := er.cluster
 	currentNodes := []NodeTypeInterface{}
@@ -322,36 +406,53 @@ func (er *ElasticsearchRequest) populateNodes() error {
 	}
 
 	minMasterUpdated := false
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+
+	// the removed data nodes are rebuilt from the deployments of the cluster so they are
+	// drained across restarts of the operator. Nodes added back are no longer drained
+	removed, err := er.removedDataNodes(currentNodes)
+	if err != nil {
+		return err
+	}
+	drainingNodes[key] = removed
 
 	// we want to only keep nodes that were generated and purge/delete any other ones...
 	// make sure cluster is green/yellow before we delete nodes
-	for _, node := range nodes[nodeMapKey(cluster.Name, cluster.Namespace)] {
+	for _, node := range nodes[key] {
 		if _, ok := containsNodeTypeInterface(node, currentNodes); !ok {
-			if status, _ := er.esClient.GetClusterHealthStatus(); !utils.Contains(desiredClusterStates, status) {
-				log.Info("Unable to delete/scale down any Elasticsearch nodes because of current cluster health", "currentHealth", status, "desiredHealth", desiredClusterStates)
-				break
-			}
+			// data nodes are deleted by decommissionRemovedNodes once they hold no shards
+			if !isDataNodeType(node) {
+				if status, _ := er.esClient.GetClusterHealthStatus(); !utils.Contains(desiredClusterStates, status) {
+					log.Info("Unable to delete/scale down any Elasticsearch nodes because of current cluster health", "currentHealth", status, "desiredHealth", desiredClusterStates)
+					break
+				}
 
-			if !minMasterUpdated {
-				// if we're removing a node make sure we set a lower min masters to keep cluster functional
-				if er.AnyNodeReady() {
-					er.updateMinMasters()
-					minMasterUpdated = true
+				if !minMasterUpdated {
+					// if we're removing a node make sure we set a lower min masters to keep cluster functional
+					if er.AnyNodeReady() {
+						er.updateMinMasters()
+						minMasterUpdated = true
+					}
 				}
-			}
 
-			if err := node.delete(); err != nil {
-				log.Error(err, "unable to delete node")
+				if err := node.delete(); err != nil {
+					log.Error(err, "unable to delete node")
+				} else {
+					er.recordEvent(v1.EventTypeNormal, eventReasonNodeDeleted, "Deleted node %s removed from the spec", node.name())
+				}
 			}
 
-			// remove from status.Nodes
+			// remove from status.Nodes, draining data nodes keep theirs until they are deleted
+			if _, draining := containsNodeTypeInterface(node, removed); draining {
+				continue
+			}
 			if index, _ := getNodeStatus(node.name(), &cluster.Status); index != NotFoundIndex {
 				cluster.Status.Nodes = append(cluster.Status.Nodes[:index], cluster.Status.Nodes[index+1:]...)
 			}
 		}
 	}
 
-	nodes[nodeMapKey(cluster.Name, cluster.Namespace)] = currentNodes
+	nodes[key] = currentNodes
 
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	"github.com/openshift/elasticsearch-operator/test/helpers"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -76,3 +77,29 @@ func populateSingleNode(clusterName string) []NodeTypeInterface {
 	}
 	return nodes
 }
+
+func TestIsOperationInProgress(t *testing.T) {
+	cluster := &elasticsearchv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Status: elasticsearchv1.ElasticsearchStatus{
+			Nodes: []elasticsearchv1.ElasticsearchNodeStatus{{DeploymentName: "elasticsearch-cdm-1-deadbeef"}},
+		},
+	}
+	if IsOperationInProgress(cluster) {
+		t.Error("expected no operation in progress for an idle cluster")
+	}
+
+	cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionTrue
+	if !IsOperationInProgress(cluster) {
+		t.Error("expected the cert redeploy to be in progress")
+	}
+
+	cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionFalse
+	updateESNodeCondition(&cluster.Status, &elasticsearchv1.ClusterCondition{
+		Type:   elasticsearchv1.Recovering,
+		Status: v1.ConditionTrue,
+	})
+	if !IsOperationInProgress(cluster) {
+		t.Error("expected the recovery to be in progress")
+	}
+}
@@ -2,6 +2,7 @@ package k8shandler
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
@@ -9,6 +10,7 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	"github.com/openshift/elasticsearch-operator/internal/utils"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ErrFlushShardsFailed indicates a failure when trying to flush shards
@@ -27,6 +29,18 @@ type Restarter struct {
 	clusterNamespace string
 	clusterStatus    *api.ElasticsearchStatus
 	nodeStatus       *api.ElasticsearchNodeStatus
+	recorder         eventRecorderFunc
+
+	// the progress of the restart is persisted as its steps begin and complete
+	kind     api.ElasticsearchRestartKind
+	progress *api.ElasticsearchRestartStatus
+	persist  func(progress *api.ElasticsearchRestartStatus) error
+
+	// hold returns ErrOutsideMaintenanceWindow while the restart has to wait before it starts
+	hold func() error
+
+	// certRedeploy restarts the nodes for the certificates of a step of the CA rotation
+	certRedeploy bool
 
 	precheck func() error
 	prep     func() error
@@ -59,6 +73,7 @@ func (er *ElasticsearchRequest) PerformFullClusterUpdate(nodes []NodeTypeInterfa
 		scheduledNodes:   nodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
+		recorder:         er.recordEvent,
 		precheck:         r.ensureClusterHealthValid,
 		prep:             r.requiredSetPrimariesShardsAndFlush,
 		main:             r.pushNodeUpdates,
@@ -79,6 +94,7 @@ func (er *ElasticsearchRequest) PerformFullClusterUpdate(nodes []NodeTypeInterfa
 
 	restarter.setClusterConditions(updateStatus)
 	restarter.clusterStatus = &er.cluster.Status
+	er.trackRestart(&restarter, api.FullClusterUpdate)
 	return restarter.restartCluster()
 }
 
@@ -94,6 +110,7 @@ func (er *ElasticsearchRequest) PerformFullClusterCertRestart(nodes []NodeTypeIn
 		scheduledNodes:   nodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
+		recorder:         er.recordEvent,
 		precheck:         r.restartNoop,
 		prep:             r.restartNoop,
 		main:             er.scaleDownThenUpFunc(r),
@@ -114,6 +131,7 @@ func (er *ElasticsearchRequest) PerformFullClusterCertRestart(nodes []NodeTypeIn
 
 	restarter.setClusterConditions(updateStatus)
 	restarter.clusterStatus = &er.cluster.Status
+	er.trackRestart(&restarter, api.FullClusterCertRestart)
 	return restarter.restartCluster()
 }
 
@@ -129,6 +147,7 @@ func (er *ElasticsearchRequest) PerformFullClusterRestart(nodes []NodeTypeInterf
 		scheduledNodes:   nodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
+		recorder:         er.recordEvent,
 		precheck:         r.ensureClusterHealthValid,
 		prep:             r.optionalSetPrimariesShardsAndFlush,
 		main:             er.scaleDownThenUpFunc(r),
@@ -149,23 +168,43 @@ func (er *ElasticsearchRequest) PerformFullClusterRestart(nodes []NodeTypeInterf
 
 	restarter.setClusterConditions(updateStatus)
 	restarter.clusterStatus = &er.cluster.Status
+	er.trackRestart(&restarter, api.FullClusterRestart)
 	return restarter.restartCluster()
 }
 
 func (er *ElasticsearchRequest) PerformNodeRestart(node NodeTypeInterface) error {
-	scheduledNode := []NodeTypeInterface{node}
+	return er.performNodesRestart([]NodeTypeInterface{node})
+}
+
+func (er *ElasticsearchRequest) PerformNodeUpdate(node NodeTypeInterface) error {
+	return er.performNodesUpdate([]NodeTypeInterface{node})
+}
+
+// performNodesRestart restarts a batch of nodes together, the nodes share the upgrade status
+// of the batch
+func (er *ElasticsearchRequest) performNodesRestart(scheduledNodes []NodeTypeInterface) error {
+	return er.restartNodes(scheduledNodes, api.NodeRestart)
+}
 
+// performNodesCertRestart restarts a batch of nodes for the certificates of a step of the CA
+// rotation, the nodes are no longer scheduled for a cert redeploy once they recovered
+func (er *ElasticsearchRequest) performNodesCertRestart(scheduledNodes []NodeTypeInterface) error {
+	return er.restartNodes(scheduledNodes, api.NodeCertRestart)
+}
+
+func (er *ElasticsearchRequest) restartNodes(scheduledNodes []NodeTypeInterface, kind api.ElasticsearchRestartKind) error {
 	r := ClusterRestart{
 		client:           er.esClient,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		scheduledNodes:   scheduledNode,
+		scheduledNodes:   scheduledNodes,
 	}
 
 	restarter := Restarter{
-		scheduledNodes:   scheduledNode,
+		scheduledNodes:   scheduledNodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
+		recorder:         er.recordEvent,
 		precheck:         r.ensureClusterHealthValid,
 		prep:             r.optionalSetPrimariesShardsAndFlush,
 		main:             r.scaleDownThenUpNodes,
@@ -173,32 +212,29 @@ func (er *ElasticsearchRequest) PerformNodeRestart(node NodeTypeInterface) error
 		recovery:         r.ensureClusterHealthValid,
 	}
 
-	updateStatus := func() {
-		if err := er.setNodeStatus(node, restarter.nodeStatus, &er.cluster.Status); err != nil {
-			log.Error(err, "unable to update node status", "namespace", er.cluster.Namespace, "name", er.cluster.Name)
-		}
-	}
-
-	restarter.setNodeConditions(updateStatus)
+	restarter.certRedeploy = kind == api.NodeCertRestart
+	restarter.setNodeConditions(er.updateNodesStatusFunc(&restarter))
 
-	restarter.nodeStatus = er.getNodeState(node)
+	restarter.nodeStatus = er.getNodeState(scheduledNodes[0])
+	er.trackRestart(&restarter, kind)
 	return restarter.restartCluster()
 }
 
-func (er *ElasticsearchRequest) PerformNodeUpdate(node NodeTypeInterface) error {
-	scheduledNode := []NodeTypeInterface{node}
-
+// performNodesUpdate pushes the changes of a batch of nodes together, the nodes share the
+// upgrade status of the batch
+func (er *ElasticsearchRequest) performNodesUpdate(scheduledNodes []NodeTypeInterface) error {
 	r := ClusterRestart{
 		client:           er.esClient,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
-		scheduledNodes:   scheduledNode,
+		scheduledNodes:   scheduledNodes,
 	}
 
 	restarter := Restarter{
-		scheduledNodes:   scheduledNode,
+		scheduledNodes:   scheduledNodes,
 		clusterName:      er.cluster.Name,
 		clusterNamespace: er.cluster.Namespace,
+		recorder:         er.recordEvent,
 		precheck:         r.ensureClusterHealthValid,
 		prep:             r.requiredSetPrimariesShardsAndFlush,
 		main:             r.pushNodeUpdates,
@@ -206,21 +242,33 @@ func (er *ElasticsearchRequest) PerformNodeUpdate(node NodeTypeInterface) error
 		recovery:         r.ensureClusterHealthValid,
 	}
 
-	updateStatus := func() {
-		if err := er.setNodeStatus(node, restarter.nodeStatus, &er.cluster.Status); err != nil {
-			log.Error(err, "unable to update node status", "namespace", er.cluster.Namespace, "name", er.cluster.Name)
-		}
-	}
+	restarter.setNodeConditions(er.updateNodesStatusFunc(&restarter))
 
-	restarter.setNodeConditions(updateStatus)
-
-	restarter.nodeStatus = er.getNodeState(node)
+	restarter.nodeStatus = er.getNodeState(scheduledNodes[0])
+	er.trackRestart(&restarter, api.NodeUpdate)
 	return restarter.restartCluster()
 }
 
+// updateNodesStatusFunc returns the status update of node restarts, which sets the upgrade
+// status of the restarter on every node of the batch
+func (er *ElasticsearchRequest) updateNodesStatusFunc(restarter *Restarter) func() {
+	return func() {
+		for _, node := range restarter.scheduledNodes {
+			nodeStatus := er.getNodeState(node)
+			nodeStatus.UpgradeStatus = restarter.nodeStatus.UpgradeStatus
+
+			if err := er.setNodeStatus(node, nodeStatus, &er.cluster.Status); err != nil {
+				log.Error(err, "unable to update node status", "namespace", er.cluster.Namespace, "name", er.cluster.Name)
+			}
+		}
+	}
+}
+
 func (er *ElasticsearchRequest) PerformRollingUpdate(nodes []NodeTypeInterface) error {
-	for _, node := range nodes {
-		if err := er.PerformNodeUpdate(node); err != nil {
+	for remaining := nodes; len(remaining) > 0; {
+		var batch []NodeTypeInterface
+		batch, remaining = er.nextRestartBatch(remaining)
+		if err := er.performNodesUpdate(batch); err != nil {
 			return err
 		}
 	}
@@ -229,8 +277,24 @@ func (er *ElasticsearchRequest) PerformRollingUpdate(nodes []NodeTypeInterface)
 }
 
 func (er *ElasticsearchRequest) PerformRollingRestart(nodes []NodeTypeInterface) error {
-	for _, node := range nodes {
-		if err := er.PerformNodeRestart(node); err != nil {
+	for remaining := nodes; len(remaining) > 0; {
+		var batch []NodeTypeInterface
+		batch, remaining = er.nextRestartBatch(remaining)
+		if err := er.performNodesRestart(batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PerformRollingCertRestart restarts the nodes in batches for the certificates of a step of
+// the CA rotation
+func (er *ElasticsearchRequest) PerformRollingCertRestart(nodes []NodeTypeInterface) error {
+	for remaining := nodes; len(remaining) > 0; {
+		var batch []NodeTypeInterface
+		batch, remaining = er.nextRestartBatch(remaining)
+		if err := er.performNodesCertRestart(batch); err != nil {
 			return err
 		}
 	}
@@ -393,6 +457,14 @@ func (cr ClusterRestart) pushNodeUpdates() error {
 	return nil
 }
 
+// recordEvent records an event on the custom resource of the restarted cluster
+func (r *Restarter) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder(eventType, reason, messageFmt, args...)
+}
+
 func (r *Restarter) setClusterConditions(updateStatus func()) {
 	// cluster conditions
 	r.precheckCondition = func() bool {
@@ -428,6 +500,7 @@ func (r *Restarter) setClusterConditions(updateStatus func()) {
 	// cluster signalers
 	r.precheckSignaler = func() {
 		log.Info("Beginning restart cluster", "cluster", r.clusterName, "namespace", r.clusterNamespace)
+		r.recordEvent(v1.EventTypeNormal, eventReasonClusterRestarting, "Beginning restart of %d nodes of the cluster", len(r.scheduledNodes))
 		updateUpdatingESSettingsCondition(r.clusterStatus, v1.ConditionTrue)
 	}
 
@@ -451,6 +524,7 @@ func (r *Restarter) setClusterConditions(updateStatus func()) {
 
 	r.recoverySignaler = func() {
 		log.Info("Completed restart of cluster", "cluster", r.clusterName, "namespace", r.clusterNamespace)
+		r.recordEvent(v1.EventTypeNormal, eventReasonClusterRestarted, "Completed restart of the cluster")
 		updateRestartingCondition(r.clusterStatus, v1.ConditionFalse)
 		updateRecoveringCondition(r.clusterStatus, v1.ConditionFalse)
 	}
@@ -483,11 +557,12 @@ func (r *Restarter) setNodeConditions(updateStatus func()) {
 	r.precheckSignaler = func() {
 		r.nodeStatus.UpgradeStatus.UnderUpgrade = v1.ConditionTrue
 
-		// for node restarts there should be only a single node
+		// the nodes of a batch are restarted together
 		log.Info("Beginning restart of node",
-			"node", r.scheduledNodes[0].name(),
+			"nodes", restartNodeNames(r.scheduledNodes),
 			"cluster", r.clusterName,
 			"namespace", r.clusterNamespace)
+		r.recordEvent(v1.EventTypeNormal, eventReasonNodeRestarting, "Beginning restart of node %s", strings.Join(restartNodeNames(r.scheduledNodes), ", "))
 		updateStatus()
 	}
 
@@ -510,16 +585,21 @@ func (r *Restarter) setNodeConditions(updateStatus func()) {
 	}
 
 	r.recoverySignaler = func() {
-		// for node restarts there should be only a single node
+		// the nodes of a batch are restarted together
 		log.Info("Completed restart of node",
-			"node", r.scheduledNodes[0].name(),
+			"nodes", restartNodeNames(r.scheduledNodes),
 			"cluster", r.clusterName,
 			"namespace", r.clusterNamespace)
+		r.recordEvent(v1.EventTypeNormal, eventReasonNodeRestarted, "Completed restart of node %s", strings.Join(restartNodeNames(r.scheduledNodes), ", "))
 
 		r.nodeStatus.UpgradeStatus.UpgradePhase = api.ControllerUpdated
 		r.nodeStatus.UpgradeStatus.UnderUpgrade = ""
 
 		r.nodeStatus.UpgradeStatus.ScheduledForUpgrade = ""
+		if r.certRedeploy {
+			// the node restarted with the current secret
+			r.nodeStatus.UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionFalse
+		}
 
 		updateStatus()
 	}
@@ -527,52 +607,90 @@ func (r *Restarter) setNodeConditions(updateStatus func()) {
 
 // template function used for all restarts
 func (r Restarter) restartCluster() error {
-	if r.precheckCondition() {
-		if err := r.precheck(); err != nil {
-			return err
+	steps := []struct {
+		name      api.ElasticsearchRestartStep
+		condition func() bool
+		run       func() error
+		signaler  func()
+	}{
+		{api.RestartStepPrecheck, r.precheckCondition, r.precheck, r.precheckSignaler},
+		{api.RestartStepPrep, r.prepCondition, r.prep, r.prepSignaler},
+		{api.RestartStepMain, r.mainCondition, r.main, r.mainSignaler},
+		{api.RestartStepPost, r.postCondition, r.post, r.postSignaler},
+		{api.RestartStepRecovery, r.recoveryCondition, r.recovery, r.recoverySignaler},
+	}
+
+	for _, step := range steps {
+		// the conditions of a step are set by the signaler of the previous one
+		if !step.condition() {
+			continue
 		}
 
-		// set conditions here for next check
-		r.precheckSignaler()
-	}
+		// only restarts which did not start yet wait for a maintenance window
+		if step.name == api.RestartStepPrecheck && r.hold != nil {
+			if err := r.hold(); err != nil {
+				return err
+			}
+		}
 
-	if r.prepCondition() {
-		if err := r.prep(); err != nil {
+		if err := r.beginStep(step.name); err != nil {
+			return err
+		}
+
+		if err := step.run(); err != nil {
 			// ignore flush failures
-			if !errors.Is(err, ErrFlushShardsFailed) {
+			if step.name != api.RestartStepPrep || !errors.Is(err, ErrFlushShardsFailed) {
 				return err
 			}
 		}
 
-		r.prepSignaler()
-	}
-
-	if r.mainCondition() {
+		// set conditions here for next check
+		step.signaler()
 
-		if err := r.main(); err != nil {
+		if err := r.completeStep(step.name); err != nil {
 			return err
 		}
+	}
 
-		r.mainSignaler()
+	return nil
+}
+
+// beginStep records that the step began, before it makes any change to the cluster. Nothing is
+// recorded until the precheck passed since it makes no changes
+func (r *Restarter) beginStep(step api.ElasticsearchRestartStep) error {
+	if r.persist == nil || (r.progress == nil && step == api.RestartStepPrecheck) {
+		return nil
 	}
 
-	if r.postCondition() {
+	r.trackProgress().Step = step
+	return r.persist(r.progress)
+}
 
-		if err := r.post(); err != nil {
-			return err
-		}
+// completeStep records that the step completed along with the conditions its signaler set. The
+// progress is cleared once the restart completed
+func (r *Restarter) completeStep(step api.ElasticsearchRestartStep) error {
+	if r.persist == nil {
+		return nil
+	}
 
-		r.postSignaler()
+	if step == api.RestartStepRecovery {
+		r.progress = nil
+		return r.persist(nil)
 	}
 
-	if r.recoveryCondition() {
+	progress := r.trackProgress()
+	progress.Step = step
+	progress.CompletedStep = step
+	return r.persist(progress)
+}
 
-		if err := r.recovery(); err != nil {
-			return err
+func (r *Restarter) trackProgress() *api.ElasticsearchRestartStatus {
+	if r.progress == nil {
+		r.progress = &api.ElasticsearchRestartStatus{
+			Kind:      r.kind,
+			Nodes:     restartNodeNames(r.scheduledNodes),
+			StartTime: metav1.Now(),
 		}
-
-		r.recoverySignaler()
 	}
-
-	return nil
+	return r.progress
 }
@@ -77,6 +77,40 @@ var _ = Describe("clusterrestart", func() {
 		})
 	})
 
+	Context("node restarts of a node scheduled for a cert redeploy", func() {
+		JustBeforeEach(func() {
+			nodeStatus.UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionTrue
+
+			restarter = Restarter{
+				scheduledNodes:   []NodeTypeInterface{&deploymentNode{}},
+				clusterName:      "test-cluster",
+				clusterNamespace: "test-namespace",
+				precheck:         r.restartNoop,
+				prep:             r.restartNoop,
+				main:             r.restartNoop,
+				post:             r.restartNoop,
+				recovery:         r.restartNoop,
+			}
+
+			restarter.nodeStatus = nodeStatus
+		})
+
+		It("should keep the node scheduled for the cert redeploy", func() {
+			restarter.setNodeConditions(updateStatus)
+
+			Expect(restarter.restartCluster()).To(BeNil())
+			Expect(restarter.nodeStatus.UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionTrue))
+		})
+
+		It("should clear the cert redeploy once a CA rotation restart recovered", func() {
+			restarter.certRedeploy = true
+			restarter.setNodeConditions(updateStatus)
+
+			Expect(restarter.restartCluster()).To(BeNil())
+			Expect(restarter.nodeStatus.UpgradeStatus.ScheduledForCertRedeploy).To(Equal(v1.ConditionFalse))
+		})
+	})
+
 	Context("node fails precheck", func() {
 		JustBeforeEach(func() {
 			testNode := &deploymentNode{}
@@ -57,6 +57,8 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 	isClient := false
 	isData := false
 	isMaster := false
+	isIngest := false
+	isCoordinating := false
 
 	for _, role := range node.Roles {
 		if role == api.ElasticsearchRoleClient {
@@ -70,12 +72,49 @@ func getNodeRoleMap(node api.ElasticsearchNode) map[api.ElasticsearchNodeRole]bo
 		if role == api.ElasticsearchRoleMaster {
 			isMaster = true
 		}
+
+		if role == api.ElasticsearchRoleIngest {
+			isIngest = true
+		}
+
+		// coordinating nodes serve HTTP requests like client nodes
+		if role == api.ElasticsearchRoleCoordinating {
+			isClient = true
+			isCoordinating = true
+		}
 	}
 	return map[api.ElasticsearchNodeRole]bool{
-		api.ElasticsearchRoleClient: isClient,
-		api.ElasticsearchRoleData:   isData,
-		api.ElasticsearchRoleMaster: isMaster,
+		api.ElasticsearchRoleClient:       isClient,
+		api.ElasticsearchRoleData:         isData,
+		api.ElasticsearchRoleMaster:       isMaster,
+		api.ElasticsearchRoleIngest:       isIngest,
+		api.ElasticsearchRoleCoordinating: isCoordinating,
+	}
+}
+
+// hasNodeRole returns whether any node of the spec has the role
+func hasNodeRole(nodes []api.ElasticsearchNode, role api.ElasticsearchNodeRole) bool {
+	for _, node := range nodes {
+		for _, r := range node.Roles {
+			if r == role {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// getNodeIngest returns whether the node runs ingest pipelines. It returns nil to keep the
+// default of Elasticsearch, every node, when no node of the cluster has the ingest or
+// coordinating role. Once nodes have the ingest role, only those run pipelines
+func getNodeIngest(nodes []api.ElasticsearchNode, roleMap map[api.ElasticsearchNodeRole]bool) *bool {
+	dedicated := hasNodeRole(nodes, api.ElasticsearchRoleIngest)
+	if !dedicated && !hasNodeRole(nodes, api.ElasticsearchRoleCoordinating) {
+		return nil
+	}
+
+	ingest := roleMap[api.ElasticsearchRoleIngest] || (!dedicated && !roleMap[api.ElasticsearchRoleCoordinating])
+	return &ingest
 }
 
 func isMasterNode(node api.ElasticsearchNode) bool {
@@ -99,6 +138,24 @@ func isDataNode(node api.ElasticsearchNode) bool {
 }
 
 func newAffinity(roleMap map[api.ElasticsearchNodeRole]bool) *v1.Affinity {
+	return &v1.Affinity{
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: v1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchExpressions: newRoleLabelSelectorRequirements(roleMap),
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+}
+
+func newRoleLabelSelectorRequirements(roleMap map[api.ElasticsearchNodeRole]bool) []metav1.LabelSelectorRequirement {
 	labelSelectorReqs := []metav1.LabelSelectorRequirement{}
 	if roleMap[api.ElasticsearchRoleClient] {
 		labelSelectorReqs = append(labelSelectorReqs, metav1.LabelSelectorRequirement{
@@ -121,22 +178,15 @@ func newAffinity(roleMap map[api.ElasticsearchNodeRole]bool) *v1.Affinity {
 			Values:   []string{"true"},
 		})
 	}
-
-	return &v1.Affinity{
-		PodAntiAffinity: &v1.PodAntiAffinity{
-			PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
-				{
-					Weight: 100,
-					PodAffinityTerm: v1.PodAffinityTerm{
-						LabelSelector: &metav1.LabelSelector{
-							MatchExpressions: labelSelectorReqs,
-						},
-						TopologyKey: "kubernetes.io/hostname",
-					},
-				},
-			},
-		},
+	if roleMap[api.ElasticsearchRoleIngest] {
+		labelSelectorReqs = append(labelSelectorReqs, metav1.LabelSelectorRequirement{
+			Key:      "es-node-ingest",
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"true"},
+		})
 	}
+
+	return labelSelectorReqs
 }
 
 func newElasticsearchContainer(imageName string, envVars []v1.EnvVar, resourceRequirements v1.ResourceRequirements) v1.Container {
@@ -317,7 +367,7 @@ func newEnvVars(nodeName, clusterName, instanceRAM string, roleMap map[api.Elast
 
 // TODO: add isChanged check for labels and label selector
 func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
-	return map[string]string{
+	labels := map[string]string{
 		"es-node-client": strconv.FormatBool(roleMap[api.ElasticsearchRoleClient]),
 		"es-node-data":   strconv.FormatBool(roleMap[api.ElasticsearchRoleData]),
 		"es-node-master": strconv.FormatBool(roleMap[api.ElasticsearchRoleMaster]),
@@ -325,6 +375,16 @@ func newLabels(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRo
 		"component":      "elasticsearch",
 		"node-name":      nodeName,
 	}
+
+	// the labels of the roles added later are only set when true, to leave the labels of
+	// existing nodes as they are
+	if roleMap[api.ElasticsearchRoleIngest] {
+		labels["es-node-ingest"] = "true"
+	}
+	if roleMap[api.ElasticsearchRoleCoordinating] {
+		labels["es-node-coordinating"] = "true"
+	}
+	return labels
 }
 
 func newLabelSelector(clusterName, nodeName string, roleMap map[api.ElasticsearchNodeRole]bool) map[string]string {
@@ -337,7 +397,7 @@ func newLabelSelector(clusterName, nodeName string, roleMap map[api.Elasticsearc
 	}
 }
 
-func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.ElasticsearchNode, commonSpec api.ElasticsearchNodeSpec, labels map[string]string, roleMap map[api.ElasticsearchNodeRole]bool, client client.Client, logConfig LogConfig) v1.PodTemplateSpec {
+func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.ElasticsearchNode, commonSpec api.ElasticsearchNodeSpec, snapshotSpec *api.ElasticsearchSnapshotSpec, nodeAttributes []string, ingest *bool, labels map[string]string, roleMap map[api.ElasticsearchNodeRole]bool, logConfig LogConfig) v1.PodTemplateSpec {
 	resourceRequirements := newESResourceRequirements(node.Resources, commonSpec.Resources)
 	proxyResourceRequirements := newESProxyResourceRequirements(node.ProxyResources, commonSpec.ProxyResources)
 
@@ -355,18 +415,45 @@ func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.Elasti
 		},
 	})
 
+	esContainer := newElasticsearchContainer(
+		getESImage(),
+		newEnvVars(nodeName, clusterName, resourceRequirements.Limits.Memory().String(), roleMap),
+		resourceRequirements,
+	)
+	esContainer.Env = append(esContainer.Env, newNodeAttributeEnvVars(nodeAttributes, node.Attributes)...)
+	if ingest != nil {
+		esContainer.Env = append(esContainer.Env, v1.EnvVar{Name: "IS_INGEST", Value: strconv.FormatBool(*ingest)})
+	}
+	volumes := newVolumes(clusterName, nodeName, node)
+	if volume, mount, ok := newSnapshotVolume(snapshotSpec); ok {
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, mount)
+		volumes = append(volumes, volume)
+	}
+
+	var initContainers []v1.Container
+	if initContainer, keystoreVolumes, mount, ok := newSnapshotKeystore(getESImage(), snapshotSpec); ok {
+		esContainer.VolumeMounts = append(esContainer.VolumeMounts, mount)
+		initContainers = append(initContainers, initContainer)
+		volumes = append(volumes, keystoreVolumes...)
+	}
+
+	var topologySpreadConstraints []v1.TopologySpreadConstraint
+	if zoneAwareness := commonSpec.ZoneAwareness; zoneAwareness != nil {
+		esContainer.Env = append(esContainer.Env, newZoneEnvVar())
+		initContainers = append(initContainers, newZoneInitContainer(getESImage()))
+		volumes = append(volumes, newZoneVolume())
+		topologySpreadConstraints = newTopologySpreadConstraints(clusterName, roleMap, zoneAwareness)
+	}
+
 	return v1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: labels,
 		},
 		Spec: v1.PodSpec{
-			Affinity: newAffinity(roleMap),
+			Affinity:       newAffinity(roleMap),
+			InitContainers: initContainers,
 			Containers: []v1.Container{
-				newElasticsearchContainer(
-					getESImage(),
-					newEnvVars(nodeName, clusterName, resourceRequirements.Limits.Memory().String(), roleMap),
-					resourceRequirements,
-				),
+				esContainer,
 				newProxyContainer(
 					getESProxyImage(),
 					clusterName,
@@ -374,10 +461,11 @@ func newPodTemplateSpec(nodeName, clusterName, namespace string, node api.Elasti
 					logConfig,
 					proxyResourceRequirements),
 			},
-			NodeSelector:       selectors,
-			ServiceAccountName: clusterName,
-			Volumes:            newVolumes(clusterName, nodeName, namespace, node, client),
-			Tolerations:        tolerations,
+			NodeSelector:              selectors,
+			ServiceAccountName:        clusterName,
+			Volumes:                   volumes,
+			Tolerations:               tolerations,
+			TopologySpreadConstraints: topologySpreadConstraints,
 		},
 	}
 }
@@ -538,7 +626,7 @@ func newResourceRequirements(nodeResRequirements, commonResRequirements, default
 	}
 }
 
-func newVolumes(clusterName, nodeName, namespace string, node api.ElasticsearchNode, client client.Client) []v1.Volume {
+func newVolumes(clusterName, nodeName string, node api.ElasticsearchNode) []v1.Volume {
 	return []v1.Volume{
 		{
 			Name: "elasticsearch-config",
@@ -552,7 +640,7 @@ func newVolumes(clusterName, nodeName, namespace string, node api.ElasticsearchN
 		},
 		{
 			Name:         "elasticsearch-storage",
-			VolumeSource: newVolumeSource(clusterName, nodeName, namespace, node, client),
+			VolumeSource: newVolumeSource(clusterName, nodeName, node),
 		},
 		{
 			Name: "certificates",
@@ -573,7 +661,7 @@ func newVolumes(clusterName, nodeName, namespace string, node api.ElasticsearchN
 	}
 }
 
-func newVolumeSource(clusterName, nodeName, namespace string, node api.ElasticsearchNode, client client.Client) v1.VolumeSource {
+func newVolumeSource(clusterName, nodeName string, node api.ElasticsearchNode) v1.VolumeSource {
 	specVol := node.Storage
 	volSource := v1.VolumeSource{}
 
@@ -592,13 +680,23 @@ func newVolumeSource(clusterName, nodeName, namespace string, node api.Elasticse
 		return volSource
 	}
 
-	// Persistent storage
+	// Persistent storage, the claim is created along with the node
 	claimName := fmt.Sprintf("%s-%s", clusterName, nodeName)
 	volSource.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
 		ClaimName: claimName,
 	}
+	return volSource
+}
 
-	volSpec := v1.PersistentVolumeClaimSpec{
+// newPersistentVolumeClaimSpec returns the spec of the claim of the node storage, or nil
+// when the node uses ephemeral storage
+func newPersistentVolumeClaimSpec(node api.ElasticsearchNode) *v1.PersistentVolumeClaimSpec {
+	specVol := node.Storage
+	if specVol.Size == nil {
+		return nil
+	}
+
+	return &v1.PersistentVolumeClaimSpec{
 		AccessModes: []v1.PersistentVolumeAccessMode{
 			v1.ReadWriteOnce,
 		},
@@ -609,12 +707,6 @@ func newVolumeSource(clusterName, nodeName, namespace string, node api.Elasticse
 		},
 		StorageClassName: specVol.StorageClassName,
 	}
-
-	err := createOrUpdatePersistentVolumeClaim(volSpec, claimName, namespace, clusterName, client)
-	if err != nil {
-		log.Error(err, "Unable to create PersistentVolumeClaim")
-	}
-	return volSource
 }
 
 func sortDataHashKeys(dataHash map[string][32]byte) []string {
@@ -555,7 +555,7 @@ func TestPodSpecHasTaintTolerations(t *testing.T) {
 		},
 	}
 
-	podTemplateSpec := newPodTemplateSpec("test-node-name", "test-cluster-name", "test-namespace-name", api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{}, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, nil, LogConfig{})
+	podTemplateSpec := newPodTemplateSpec("test-node-name", "test-cluster-name", "test-namespace-name", api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{}, nil, nil, nil, map[string]string{}, map[api.ElasticsearchNodeRole]bool{}, LogConfig{})
 
 	if !reflect.DeepEqual(podTemplateSpec.Spec.Tolerations, expectedTolerations) {
 		t.Errorf("Exp. the tolerations to be %v but was %v", expectedTolerations, podTemplateSpec.Spec.Tolerations)
@@ -752,11 +752,13 @@ func TestNewVolumeSource(t *testing.T) {
 		test := test
 		client := fake.NewFakeClient()
 
-		vs := newVolumeSource(clusterName, nodeName, namespace, test.node, client)
+		vs := newVolumeSource(clusterName, nodeName, test.node)
 		if diff := cmp.Diff(test.vs, vs); diff != "" {
 			t.Errorf("diff: %s", diff)
 		}
 
+		createOrUpdateNodePersistentVolumeClaim(newPersistentVolumeClaimSpec(test.node), nodeName, namespace, clusterName, client)
+
 		if test.pvc != nil {
 			key := types.NamespacedName{Name: claimName, Namespace: namespace}
 			pvc := &v1.PersistentVolumeClaim{}
@@ -782,9 +784,11 @@ func preparePodTemplateSpecProvidingNodeSelectors(selectors map[string]string) v
 		"test-namespace-name",
 		api.ElasticsearchNode{NodeSelector: selectors},
 		api.ElasticsearchNodeSpec{},
+		nil,
+		nil,
+		nil,
 		map[string]string{},
 		map[api.ElasticsearchNodeRole]bool{},
-		nil,
 		LogConfig{})
 }
 
@@ -857,3 +861,66 @@ var _ = Describe("common.go", func() {
 		})
 	})
 })
+
+func TestGetNodeIngest(t *testing.T) {
+	dataNode := api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster}}
+	ingestNode := api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleIngest}}
+	coordinatingNode := api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}}
+	ingest, noIngest := true, false
+
+	tests := []struct {
+		desc  string
+		nodes []api.ElasticsearchNode
+		node  api.ElasticsearchNode
+		want  *bool
+	}{
+		{
+			desc:  "no ingest or coordinating nodes",
+			nodes: []api.ElasticsearchNode{dataNode},
+			node:  dataNode,
+			want:  nil,
+		},
+		{
+			desc:  "data node next to ingest nodes",
+			nodes: []api.ElasticsearchNode{dataNode, ingestNode},
+			node:  dataNode,
+			want:  &noIngest,
+		},
+		{
+			desc:  "ingest node",
+			nodes: []api.ElasticsearchNode{dataNode, ingestNode},
+			node:  ingestNode,
+			want:  &ingest,
+		},
+		{
+			desc:  "data node next to coordinating nodes",
+			nodes: []api.ElasticsearchNode{dataNode, coordinatingNode},
+			node:  dataNode,
+			want:  &ingest,
+		},
+		{
+			desc:  "coordinating node",
+			nodes: []api.ElasticsearchNode{dataNode, coordinatingNode},
+			node:  coordinatingNode,
+			want:  &noIngest,
+		},
+	}
+	for _, test := range tests {
+		got := getNodeIngest(test.nodes, getNodeRoleMap(test.node))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Exp. ingest to be %v but was %v", test.desc, test.want, got)
+		}
+	}
+}
+
+func TestNewLabelsOfCoordinatingNode(t *testing.T) {
+	roleMap := getNodeRoleMap(api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}})
+
+	labels := newLabels("elasticsearch", "elasticsearch-c-abc", roleMap)
+	if labels["es-node-client"] != "true" || labels["es-node-coordinating"] != "true" {
+		t.Errorf("Exp. a coordinating node to be labeled as client and coordinating node but was %v", labels)
+	}
+	if _, found := labels["es-node-ingest"]; found {
+		t.Errorf("Exp. no ingest label for a node without the ingest role but was %v", labels)
+	}
+}
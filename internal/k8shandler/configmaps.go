@@ -18,6 +18,7 @@ import (
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,6 +35,14 @@ type esYmlStruct struct {
 	NodeQuorum           string
 	RecoverExpectedNodes string
 	SystemCallFilter     string
+	PathRepo             string
+	S3Endpoint           string
+	S3Protocol           string
+	ZoneAwareness        bool
+	ForcedZones          string
+	NodeAttributes       []nodeAttribute
+	NodesDN              []string
+	IngestRoles          bool
 }
 
 type log4j2PropertiesStruct struct {
@@ -103,30 +112,10 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMap(cm *v1.ConfigMap) error
 func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() (err error) {
 	dpl := er.cluster
 
-	kibanaIndexMode, err := kibanaIndexMode("")
+	configmap, err := er.newElasticsearchConfigMap()
 	if err != nil {
 		return err
 	}
-	dataNodeCount := int(getDataCount(dpl))
-	masterNodeCount := int(getMasterCount(dpl))
-
-	logConfig := getLogConfig(dpl.GetAnnotations())
-
-	configmap := newConfigMap(
-		dpl.Name,
-		dpl.Namespace,
-		dpl.Labels,
-		kibanaIndexMode,
-		esUnicastHost(dpl.Name, dpl.Namespace),
-		strconv.Itoa(masterNodeCount/2+1),
-		strconv.Itoa(dataNodeCount),
-		strconv.Itoa(calculatePrimaryCount(dpl)),
-		strconv.Itoa(calculateReplicaCount(dpl)),
-		strconv.FormatBool(runtime.GOARCH == "amd64"),
-		logConfig,
-	)
-
-	dpl.AddOwnerRefTo(configmap)
 
 	err = er.client.Create(context.TODO(), configmap)
 	if err == nil {
@@ -181,10 +170,47 @@ func (er *ElasticsearchRequest) CreateOrUpdateConfigMaps() (err error) {
 	return nil
 }
 
-func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) (map[string]string, error) {
+// newElasticsearchConfigMap returns the ConfigMap with the Elasticsearch configuration of the spec
+func (er *ElasticsearchRequest) newElasticsearchConfigMap() (*v1.ConfigMap, error) {
+	dpl := er.cluster
+
+	kibanaIndexMode, err := kibanaIndexMode("")
+	if err != nil {
+		return nil, err
+	}
+	dataNodeCount := int(getDataCount(dpl))
+	masterNodeCount := int(getMasterCount(dpl))
+
+	logConfig := getLogConfig(dpl.GetAnnotations())
+
+	configmap := newConfigMap(
+		dpl.Name,
+		dpl.Namespace,
+		dpl.Labels,
+		kibanaIndexMode,
+		esUnicastHost(dpl.Name, dpl.Namespace),
+		strconv.Itoa(masterNodeCount/2+1),
+		strconv.Itoa(dataNodeCount),
+		strconv.Itoa(calculatePrimaryCount(dpl)),
+		strconv.Itoa(calculateReplicaCount(dpl)),
+		strconv.FormatBool(runtime.GOARCH == "amd64"),
+		dpl.Spec.Snapshot,
+		dpl.Spec.Spec.ZoneAwareness,
+		nodeAttributeNames(dpl.Spec.Nodes),
+		getNodesDN(dpl),
+		hasNodeRole(dpl.Spec.Nodes, api.ElasticsearchRoleIngest) || hasNodeRole(dpl.Spec.Nodes, api.ElasticsearchRoleCoordinating),
+		logConfig,
+	)
+
+	dpl.AddOwnerRefTo(configmap)
+
+	return configmap, nil
+}
+
+func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, snapshot *api.ElasticsearchSnapshotSpec, zoneAwareness *api.ZoneAwarenessSpec, nodeAttributes, nodesDN []string, ingestRoles bool, logConfig LogConfig) (map[string]string, error) {
 	data := map[string]string{}
 	buf := &bytes.Buffer{}
-	if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter); err != nil {
+	if err := renderEsYml(buf, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter, snapshot, zoneAwareness, nodeAttributes, nodesDN, ingestRoles); err != nil {
 		return data, err
 	}
 	data[esConfig] = buf.String()
@@ -206,8 +232,8 @@ func renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes
 
 // newConfigMap returns a v1.ConfigMap object
 func newConfigMap(configMapName, namespace string, labels map[string]string,
-	kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, logConfig LogConfig) *v1.ConfigMap {
-	data, err := renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter, logConfig)
+	kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter string, snapshot *api.ElasticsearchSnapshotSpec, zoneAwareness *api.ZoneAwarenessSpec, nodeAttributes, nodesDN []string, ingestRoles bool, logConfig LogConfig) *v1.ConfigMap {
+	data, err := renderData(kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, primaryShardsCount, replicaShardsCount, systemCallFilter, snapshot, zoneAwareness, nodeAttributes, nodesDN, ingestRoles, logConfig)
 	if err != nil {
 		return nil
 	}
@@ -251,7 +277,7 @@ func configMapContentChanged(old, new *v1.ConfigMap) bool {
 	return false
 }
 
-func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter string) error {
+func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recoverExpectedNodes, systemCallFilter string, snapshot *api.ElasticsearchSnapshotSpec, zoneAwareness *api.ZoneAwarenessSpec, nodeAttributes, nodesDN []string, ingestRoles bool) error {
 	t := template.New("elasticsearch.yml")
 	config := esYmlTmpl
 	t, err := t.Parse(config)
@@ -264,6 +290,20 @@ func renderEsYml(w io.Writer, kibanaIndexMode, esUnicastHost, nodeQuorum, recove
 		NodeQuorum:           nodeQuorum,
 		RecoverExpectedNodes: recoverExpectedNodes,
 		SystemCallFilter:     systemCallFilter,
+		NodeAttributes:       newNodeAttributes(nodeAttributes),
+		NodesDN:              nodesDN,
+		IngestRoles:          ingestRoles,
+	}
+	if snapshot != nil && snapshot.Repository.Filesystem != nil {
+		esy.PathRepo = api.SnapshotPathRepo
+	}
+	if snapshot != nil && snapshot.Repository.S3 != nil {
+		esy.S3Endpoint = snapshot.Repository.S3.Endpoint
+		esy.S3Protocol = snapshot.Repository.S3.Protocol
+	}
+	if zoneAwareness != nil {
+		esy.ZoneAwareness = true
+		esy.ForcedZones = forcedZones(zoneAwareness)
 	}
 
 	return t.Execute(w, esy)
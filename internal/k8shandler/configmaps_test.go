@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/test/helpers"
 )
 
@@ -109,9 +110,14 @@ var _ = Describe("configmaps", func() {
 	defer GinkgoRecover()
 
 	Describe("#renderEsYml", func() {
+		It("should let the roles of the nodes decide whether they run ingest pipelines", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, nil, nil, nil, true)).To(Succeed())
+			Expect(result.String()).To(ContainSubstring("  data: ${HAS_DATA}\n  ingest: ${IS_INGEST}\n"))
+		})
 		It("should produce an elasticsearch.yml for our managed elasticsearch instance", func() {
 			result := &bytes.Buffer{}
-			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false")).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, nil, nil, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
 			helpers.ExpectYaml(result.String()).ToEqual(`
 cluster:
   name: ${CLUSTER_NAME}
@@ -157,6 +163,7 @@ opendistro_security:
   config_index_name: ".security"
   restapi:
     roles_enabled: ["kibana_server"]
+  ssl_cert_reload_enabled: true
   ssl:
     transport:
       enabled: true
@@ -179,3 +186,85 @@ opendistro_security:
 		})
 	})
 })
+
+var _ = Describe("configmaps with a snapshot repository", func() {
+	defer GinkgoRecover()
+
+	Describe("#renderEsYml", func() {
+		It("should register the repository path", func() {
+			snapshot := &api.ElasticsearchSnapshotSpec{
+				Repository: api.ElasticsearchSnapshotRepositorySpec{
+					Filesystem: &api.ElasticsearchSnapshotFilesystemRepositorySpec{ClaimName: "snapshots"},
+				},
+			}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", snapshot, nil, nil, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring(`
+path:
+  data: /elasticsearch/persistent/${CLUSTER_NAME}/data
+  logs: /elasticsearch/persistent/${CLUSTER_NAME}/logs
+  repo: ["/elasticsearch/snapshots"]
+`))
+		})
+		It("should configure the s3 client endpoint", func() {
+			snapshot := &api.ElasticsearchSnapshotSpec{
+				Repository: api.ElasticsearchSnapshotRepositorySpec{
+					S3: &api.ElasticsearchSnapshotS3RepositorySpec{Endpoint: "minio.minio.svc:9000", Protocol: "http"},
+				},
+			}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", snapshot, nil, nil, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring(`
+s3.client.default:
+  endpoint: "minio.minio.svc:9000"
+  protocol: http
+`))
+		})
+	})
+})
+
+var _ = Describe("configmaps with zone awareness", func() {
+	defer GinkgoRecover()
+
+	Describe("#renderEsYml", func() {
+		It("should set the zone attribute and allocation awareness", func() {
+			zoneAwareness := &api.ZoneAwarenessSpec{}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, zoneAwareness, nil, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring(`
+  max_local_storage_nodes: 1
+  attr.zone: ${ZONE}
+`))
+			Expect(result.String()).To(ContainSubstring(`
+cluster.routing.allocation.awareness:
+  attributes: zone
+`))
+			Expect(result.String()).ToNot(ContainSubstring("force.zone.values"))
+		})
+		It("should force awareness for the spec'd zones", func() {
+			zoneAwareness := &api.ZoneAwarenessSpec{Zones: []string{"us-east-1a", "us-east-1b"}}
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, zoneAwareness, nil, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring(`
+cluster.routing.allocation.awareness:
+  attributes: zone
+  force.zone.values: us-east-1a,us-east-1b
+`))
+		})
+	})
+})
+
+var _ = Describe("configmaps with node attributes", func() {
+	defer GinkgoRecover()
+
+	Describe("#renderEsYml", func() {
+		It("should render a placeholder for each node attribute", func() {
+			result := &bytes.Buffer{}
+			Expect(renderEsYml(result, "", "my.unicast.host", "7", "4", "false", nil, nil, []string{"box_type", "rack"}, nil, false)).To(BeNil(), "Exp. no errors when rendering the configuration")
+			Expect(result.String()).To(ContainSubstring(`
+  attr.box_type: ${NODE_ATTR_BOX_TYPE}
+  attr.rack: ${NODE_ATTR_RACK}
+`))
+		})
+	})
+})
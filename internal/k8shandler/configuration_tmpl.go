@@ -11,9 +11,26 @@ node:
   name: ${DC_NAME}
   master: ${IS_MASTER}
   data: ${HAS_DATA}
+{{- if .IngestRoles}}
+  ingest: ${IS_INGEST}
+{{- end}}
   max_local_storage_nodes: 1
+{{- if .ZoneAwareness}}
+  attr.zone: ${ZONE}
+{{- end}}
+{{- range .NodeAttributes}}
+  attr.{{.Name}}: ${ {{- .EnvVar -}} }
+{{- end}}
 
 action.auto_create_index: "-*-write,+*"
+{{- if .ZoneAwareness}}
+
+cluster.routing.allocation.awareness:
+  attributes: zone
+{{- if .ForcedZones}}
+  force.zone.values: {{.ForcedZones}}
+{{- end}}
+{{- end}}
 
 network:
   publish_host: ${POD_IP}
@@ -31,6 +48,17 @@ gateway:
 path:
   data: /elasticsearch/persistent/${CLUSTER_NAME}/data
   logs: /elasticsearch/persistent/${CLUSTER_NAME}/logs
+{{- if .PathRepo}}
+  repo: ["{{.PathRepo}}"]
+{{- end}}
+{{- if .S3Endpoint}}
+
+s3.client.default:
+  endpoint: "{{.S3Endpoint}}"
+{{- if .S3Protocol}}
+  protocol: {{.S3Protocol}}
+{{- end}}
+{{- end}}
 
 prometheus:
   indices: false
@@ -42,9 +70,16 @@ opendistro_security:
   authcz.admin_dn:
   - CN=system.admin,OU=OpenShift,O=Logging
   - CN=system.admin,OU=Logging,O=OpenShift
+{{- if .NodesDN}}
+  nodes_dn:
+{{- range .NodesDN}}
+  - {{.}}
+{{- end}}
+{{- end}}
   config_index_name: ".security"
   restapi:
     roles_enabled: ["kibana_server"]
+  ssl_cert_reload_enabled: true
   ssl:
     transport:
       enabled: true
@@ -0,0 +1,179 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/openshift/elasticsearch-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// drainingNodes holds the data nodes removed from the spec which are deleted once
+// Elasticsearch moved their shards to the remaining nodes. It is rebuilt from the
+// deployments of the cluster by populateNodes
+var drainingNodes map[string][]NodeTypeInterface
+
+// removedDataNodes returns the data nodes of the deployments of the cluster which are
+// not part of the current nodes
+func (er *ElasticsearchRequest) removedDataNodes(currentNodes []NodeTypeInterface) ([]NodeTypeInterface, error) {
+	cluster := er.cluster
+	selector := map[string]string{
+		"cluster-name": cluster.Name,
+		"component":    "elasticsearch",
+		"es-node-data": "true",
+	}
+
+	deployments, err := GetDeploymentList(cluster.Namespace, selector, er.client)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to list the deployments of the data nodes",
+			"cluster", cluster.Name,
+			"namespace", cluster.Namespace)
+	}
+
+	removed := []NodeTypeInterface{}
+	for _, deployment := range deployments.Items {
+		if deployment.DeletionTimestamp != nil {
+			continue
+		}
+		node := &deploymentNode{
+			self:        deployment,
+			clusterName: cluster.Name,
+			client:      er.client,
+			esClient:    er.esClient,
+		}
+		if _, ok := containsNodeTypeInterface(node, currentNodes); !ok {
+			removed = append(removed, node)
+		}
+	}
+	return removed, nil
+}
+
+// decommissionRemovedNodes excludes the removed data nodes from shard allocation, and
+// deletes them along with their claims once they hold no shards
+func (er *ElasticsearchRequest) decommissionRemovedNodes() error {
+	cluster := er.cluster
+	key := nodeMapKey(cluster.Name, cluster.Namespace)
+	draining := drainingNodes[key]
+
+	// the exclusions are also cleared when the drained nodes were added back
+	if len(draining) == 0 && !containsClusterCondition(api.ScalingDown, v1.ConditionTrue, &cluster.Status) {
+		return nil
+	}
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	excluded, err := er.esClient.GetShardAllocationExclude()
+	if err != nil {
+		return err
+	}
+	if desired := desiredShardAllocationExclude(excluded, nodes[key], draining, nil); !reflect.DeepEqual(excluded, desired) {
+		if err := er.esClient.SetShardAllocationExclude(desired); err != nil {
+			return err
+		}
+		excluded = desired
+	}
+
+	if len(draining) == 0 {
+		return updateScalingDownConditionWithMessage(cluster, v1.ConditionFalse, "", er.client)
+	}
+
+	shardCounts, err := er.esClient.GetNodeShardCounts()
+	if err != nil {
+		return err
+	}
+
+	drained, remaining := []NodeTypeInterface{}, []NodeTypeInterface{}
+	for _, node := range draining {
+		if shardCounts[node.name()] > 0 {
+			remaining = append(remaining, node)
+		} else {
+			drained = append(drained, node)
+		}
+	}
+
+	if len(drained) > 0 {
+		if status, _ := er.esClient.GetClusterHealthStatus(); !utils.Contains(desiredClusterStates, status) {
+			er.L().Info("Unable to delete drained Elasticsearch nodes because of current cluster health", "currentHealth", status, "desiredHealth", desiredClusterStates)
+			remaining = draining
+			drained = nil
+		} else {
+			// if we're removing a node make sure we set a lower min masters to keep cluster functional
+			er.updateMinMasters()
+		}
+	}
+
+	for _, node := range drained {
+		er.L().Info("Deleting drained node", "node", node.name())
+		if err := node.delete(); err != nil {
+			er.L().Error(err, "unable to delete node", "node", node.name())
+			remaining = append(remaining, node)
+			continue
+		}
+		er.recordEvent(v1.EventTypeNormal, eventReasonNodeDeleted, "Deleted node %s after moving its shards to the other nodes", node.name())
+		if index, _ := getNodeStatus(node.name(), &cluster.Status); index != NotFoundIndex {
+			cluster.Status.Nodes = append(cluster.Status.Nodes[:index], cluster.Status.Nodes[index+1:]...)
+		}
+		claimName := fmt.Sprintf("%s-%s", cluster.Name, node.name())
+		if err := deletePersistentVolumeClaim(claimName, cluster.Namespace, er.client); err != nil {
+			er.L().Error(err, "unable to release the claim of node", "node", node.name())
+		}
+	}
+	drainingNodes[key] = remaining
+
+	if desired := desiredShardAllocationExclude(excluded, nodes[key], remaining, drained); !reflect.DeepEqual(excluded, desired) {
+		if err := er.esClient.SetShardAllocationExclude(desired); err != nil {
+			return err
+		}
+	}
+
+	if len(remaining) == 0 {
+		return updateScalingDownConditionWithMessage(cluster, v1.ConditionFalse, "", er.client)
+	}
+	return updateScalingDownConditionWithMessage(cluster, v1.ConditionTrue, drainingMessage(remaining, shardCounts), er.client)
+}
+
+// desiredShardAllocationExclude returns the exclusions with the draining nodes added and
+// the nodes of the cluster or deleted nodes removed
+func desiredShardAllocationExclude(excluded []string, clusterNodes, draining, deleted []NodeTypeInterface) []string {
+	removed := map[string]bool{}
+	for _, node := range clusterNodes {
+		removed[node.name()] = true
+	}
+	for _, node := range deleted {
+		removed[node.name()] = true
+	}
+
+	desired := []string{}
+	for _, name := range excluded {
+		if !removed[name] {
+			desired = append(desired, name)
+		}
+	}
+	for _, node := range draining {
+		if !utils.Contains(desired, node.name()) {
+			desired = append(desired, node.name())
+		}
+	}
+	return desired
+}
+
+func drainingMessage(draining []NodeTypeInterface, shardCounts map[string]int32) string {
+	counts := make([]string, len(draining))
+	for i, node := range draining {
+		counts[i] = fmt.Sprintf("%s (%d shards)", node.name(), shardCounts[node.name()])
+	}
+	sort.Strings(counts)
+	return fmt.Sprintf("Moving the shards off the removed nodes: %s", strings.Join(counts, ", "))
+}
+
+// isDataNodeType returns true for nodes holding shards. Data nodes are always deployments
+func isDataNodeType(node NodeTypeInterface) bool {
+	deployment, ok := node.(*deploymentNode)
+	return ok && deployment.self.Labels["es-node-data"] == "true"
+}
@@ -0,0 +1,207 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("decommission.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		chatter   *helpers.FakeElasticsearchChatter
+		request   *ElasticsearchRequest
+		removed   NodeTypeInterface
+		key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+		nodeKey   = types.NamespacedName{Name: "elasticsearch-cd-abc-2", Namespace: "openshift-logging"}
+		claimKey  = types.NamespacedName{Name: "elasticsearch-elasticsearch-cd-abc-2", Namespace: "openshift-logging"}
+		dataRoles = map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleClient: true, api.ElasticsearchRoleData: true}
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		readyPod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "elasticsearch-cd-abc-1-xyz",
+				Namespace: key.Namespace,
+				Labels:    newLabels(key.Name, "elasticsearch-cd-abc-1", dataRoles),
+			},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{Name: "elasticsearch", Ready: true}},
+			},
+		}
+		claim := &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: claimKey.Name, Namespace: claimKey.Namespace},
+		}
+		k8sClient = fake.NewFakeClient(cluster, readyPod, claim)
+		esNode := api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData}}
+		removed = newDeploymentNode(nodeKey.Name, esNode, cluster, dataRoles, k8sClient, nil)
+		Expect(k8sClient.Create(context.TODO(), &removed.(*deploymentNode).self)).To(Succeed())
+
+		nodes = map[string][]NodeTypeInterface{nodeMapKey(key.Name, key.Namespace): {}}
+		drainingNodes = map[string][]NodeTypeInterface{nodeMapKey(key.Name, key.Namespace): {removed}}
+	})
+
+	JustBeforeEach(func() {
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(key.Name, key.Namespace, k8sClient, chatter),
+		}
+	})
+
+	Describe("#decommissionRemovedNodes", func() {
+		Context("when the removed node holds shards", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+					"_cluster/settings": {
+						{StatusCode: 200, Body: `{"persistent": {}, "transient": {}}`},
+						{StatusCode: 200, Body: `{"acknowledged": true}`},
+					},
+					"_cat/allocation?format=json&h=shards,node": {
+						{StatusCode: 200, Body: `[{"shards": "3", "node": "elasticsearch-cd-abc-2"}]`},
+					},
+				})
+			})
+
+			It("should exclude the node from allocation and keep it", func() {
+				Expect(request.decommissionRemovedNodes()).To(Succeed())
+
+				chatter.GetRequest("_cluster/settings")
+				req, _ := chatter.GetRequest("_cluster/settings")
+				Expect(req.Body).To(Equal(`{"persistent":{"cluster.routing.allocation.exclude._name":"elasticsearch-cd-abc-2"}}`))
+
+				Expect(k8sClient.Get(context.TODO(), nodeKey, &apps.Deployment{})).To(Succeed())
+				Expect(drainingNodes[nodeMapKey(key.Name, key.Namespace)]).To(HaveLen(1))
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				_, condition := getESNodeCondition(current.Status.Conditions, api.ScalingDown)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(v1.ConditionTrue))
+				Expect(condition.Message).To(ContainSubstring("elasticsearch-cd-abc-2 (3 shards)"))
+			})
+		})
+
+		Context("when the removed node is drained", func() {
+			BeforeEach(func() {
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+					"_cluster/settings": {
+						{StatusCode: 200, Body: `{"persistent": {"cluster": {"routing": {"allocation": {"exclude": {"_name": "elasticsearch-cd-abc-2"}}}}}}`},
+						{StatusCode: 200, Body: `{"persistent": {"discovery.zen.minimum_master_nodes": 1}}`},
+						{StatusCode: 200, Body: `{"acknowledged": true}`},
+					},
+					"_cat/allocation?format=json&h=shards,node": {
+						{StatusCode: 200, Body: `[{"shards": "0", "node": "elasticsearch-cd-abc-2"}]`},
+					},
+					"_cluster/health": {
+						{StatusCode: 200, Body: `{"status": "green"}`},
+						{StatusCode: 200, Body: `{"status": "green", "number_of_nodes": 1}`},
+					},
+				})
+			})
+
+			It("should delete the node and its claim and clear the exclusion", func() {
+				cluster.Status.Nodes = []api.ElasticsearchNodeStatus{{DeploymentName: nodeKey.Name}}
+				Expect(request.decommissionRemovedNodes()).To(Succeed())
+				Expect(cluster.Status.Nodes).To(BeEmpty())
+
+				Expect(apierrors.IsNotFound(k8sClient.Get(context.TODO(), nodeKey, &apps.Deployment{}))).To(BeTrue())
+				Expect(apierrors.IsNotFound(k8sClient.Get(context.TODO(), claimKey, &v1.PersistentVolumeClaim{}))).To(BeTrue())
+				Expect(drainingNodes[nodeMapKey(key.Name, key.Namespace)]).To(BeEmpty())
+
+				chatter.GetRequest("_cluster/settings")
+				chatter.GetRequest("_cluster/settings")
+				req, _ := chatter.GetRequest("_cluster/settings")
+				Expect(req.Body).To(Equal(`{"persistent":{"cluster.routing.allocation.exclude._name":null}}`))
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(containsClusterCondition(api.ScalingDown, v1.ConditionTrue, &current.Status)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("#updateDrainingNodeConditions", func() {
+		BeforeEach(func() {
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+		})
+
+		It("should keep the status of the draining nodes with the draining condition", func() {
+			status := &api.ElasticsearchStatus{Nodes: []api.ElasticsearchNodeStatus{{DeploymentName: nodeKey.Name}}}
+
+			Expect(request.pruneMissingNodes(status)).To(Succeed())
+			request.updateDrainingNodeConditions(status)
+
+			Expect(status.Nodes).To(HaveLen(1))
+			_, condition := getPodCondition(&status.Nodes[0], api.ScalingDown)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(v1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("Draining"))
+		})
+
+		It("should clear the draining condition of the nodes added back", func() {
+			drainingNodes = map[string][]NodeTypeInterface{}
+			nodes = map[string][]NodeTypeInterface{nodeMapKey(key.Name, key.Namespace): {removed}}
+			status := &api.ElasticsearchStatus{Nodes: []api.ElasticsearchNodeStatus{{
+				DeploymentName: nodeKey.Name,
+				Conditions:     []api.ClusterCondition{{Type: api.ScalingDown, Status: v1.ConditionTrue, Reason: "Draining"}},
+			}}}
+
+			Expect(request.pruneMissingNodes(status)).To(Succeed())
+			request.updateDrainingNodeConditions(status)
+
+			Expect(status.Nodes).To(HaveLen(1))
+			Expect(status.Nodes[0].Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("#removedDataNodes", func() {
+		BeforeEach(func() {
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+		})
+
+		It("should rebuild the removed nodes from the deployments of the cluster", func() {
+			drainingNodes = map[string][]NodeTypeInterface{}
+
+			removedNodes, err := request.removedDataNodes(nil)
+			Expect(err).To(BeNil())
+			Expect(removedNodes).To(HaveLen(1))
+			Expect(removedNodes[0].name()).To(Equal(nodeKey.Name))
+		})
+
+		It("should not drain the nodes which are part of the spec", func() {
+			removedNodes, err := request.removedDataNodes([]NodeTypeInterface{removed})
+			Expect(err).To(BeNil())
+			Expect(removedNodes).To(BeEmpty())
+		})
+	})
+
+	Describe("#desiredShardAllocationExclude", func() {
+		It("should keep other exclusions and remove the nodes of the cluster", func() {
+			Expect(desiredShardAllocationExclude([]string{"other", nodeKey.Name}, []NodeTypeInterface{removed}, nil, nil)).To(Equal([]string{"other"}))
+			Expect(desiredShardAllocationExclude([]string{"other"}, nil, []NodeTypeInterface{removed}, nil)).To(Equal([]string{"other", nodeKey.Name}))
+		})
+	})
+})
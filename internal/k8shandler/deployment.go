@@ -26,11 +26,16 @@ type deploymentNode struct {
 	configmapHash string
 	// prior hash for secret content
 	secretHash string
+	// prior hash for the CA bundle in the secret
+	caHash string
 
 	clusterName string
 
 	replicas int32
 
+	// spec of the claim of the node storage, nil for ephemeral storage
+	claimSpec *v1.PersistentVolumeClaimSpec
+
 	client client.Client
 
 	esClient elasticsearch.Client
@@ -65,13 +70,14 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 		},
 		ProgressDeadlineSeconds: &progressDeadlineSeconds,
 		Paused:                  false,
-		Template:                newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, n, cluster.Spec.Spec, labels, roleMap, client, logConfig),
+		Template:                newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, n, cluster.Spec.Spec, cluster.Spec.Snapshot, nodeAttributeNames(cluster.Spec.Nodes), getNodeIngest(cluster.Spec.Nodes, roleMap), labels, roleMap, logConfig),
 	}
 
 	cluster.AddOwnerRefTo(&deployment)
 
 	node.self = deployment
 	node.clusterName = cluster.Name
+	node.claimSpec = newPersistentVolumeClaimSpec(n)
 
 	node.client = client
 	node.esClient = esClient
@@ -79,6 +85,7 @@ func (node *deploymentNode) populateReference(nodeName string, n api.Elasticsear
 
 func (node *deploymentNode) updateReference(n NodeTypeInterface) {
 	node.self = n.(*deploymentNode).self
+	node.claimSpec = n.(*deploymentNode).claimSpec
 }
 
 func (node *deploymentNode) scaleDown() error {
@@ -97,6 +104,10 @@ func (node *deploymentNode) getSecretHash() string {
 	return node.secretHash
 }
 
+func (node *deploymentNode) getCAHash() string {
+	return node.caHash
+}
+
 func (node *deploymentNode) state() api.ElasticsearchNodeStatus {
 	// var rolloutForReload v1.ConditionStatus
 	var rolloutForUpdate v1.ConditionStatus
@@ -114,6 +125,7 @@ func (node *deploymentNode) state() api.ElasticsearchNodeStatus {
 		// the current hash -- we should have already had our upgradeStatus set if
 		// we required a restart...
 		node.secretHash = newSecretHash
+		node.caHash = getCADataHash(node.clusterName, node.self.Namespace, node.client)
 	} else {
 		// check if the secretHash changed
 		if newSecretHash != node.secretHash {
@@ -135,6 +147,8 @@ func (node *deploymentNode) delete() error {
 }
 
 func (node *deploymentNode) create() error {
+	createOrUpdateNodePersistentVolumeClaim(node.claimSpec, node.name(), node.self.Namespace, node.clusterName, node.client)
+
 	if node.self.ObjectMeta.ResourceVersion == "" {
 		err := node.client.Create(context.TODO(), &node.self)
 		if err != nil {
@@ -154,6 +168,7 @@ func (node *deploymentNode) create() error {
 		// update the hashmaps
 		node.configmapHash = getConfigmapDataHash(node.clusterName, node.self.Namespace, node.client)
 		node.secretHash = getSecretDataHash(node.clusterName, node.self.Namespace, node.client)
+		node.caHash = getCADataHash(node.clusterName, node.self.Namespace, node.client)
 	}
 
 	return node.pause()
@@ -409,6 +424,8 @@ func (node *deploymentNode) refreshHashes() {
 	if newSecretHash != node.secretHash {
 		node.secretHash = newSecretHash
 	}
+
+	node.caHash = getCADataHash(node.clusterName, node.self.Namespace, node.client)
 }
 
 func (node *deploymentNode) isChanged() bool {
@@ -4,6 +4,7 @@ import (
 	"github.com/ViaQ/logerr/kverrors"
 	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	v1 "k8s.io/api/core/v1"
 )
 
 // this function should be called before we try doing operations to make sure all our nodes are
@@ -135,9 +136,11 @@ func (er *ElasticsearchRequest) unblockIndex(index string) error {
 	}
 
 	if err := er.esClient.UpdateIndexSettings(index, settings); err != nil {
+		er.recordEvent(v1.EventTypeWarning, eventReasonIndexUnblockFailed, "Unable to remove the read-only block of index %s", index)
 		return kverrors.Wrap(err, "failed to unblock index",
 			"index", index)
 	}
 
+	er.recordEvent(v1.EventTypeNormal, eventReasonIndexUnblocked, "Removed the read-only block of index %s as the disk usage is below the flood stage watermark", index)
 	return nil
 }
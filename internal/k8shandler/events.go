@@ -0,0 +1,36 @@
+package k8shandler
+
+// Reasons of the events recorded on the Elasticsearch custom resource
+const (
+	eventReasonClusterRestarting     = "ClusterRestarting"
+	eventReasonClusterRestarted      = "ClusterRestarted"
+	eventReasonNodeRestarting        = "NodeRestarting"
+	eventReasonNodeRestarted         = "NodeRestarted"
+	eventReasonNodeCreated           = "NodeCreated"
+	eventReasonNodeDeleted           = "NodeDeleted"
+	eventReasonNodeAdopted           = "NodeAdopted"
+	eventReasonCertsReloaded         = "CertificatesReloaded"
+	eventReasonCertsReloadFailed     = "CertificatesReloadFailed"
+	eventReasonIndexUnblocked        = "IndexUnblocked"
+	eventReasonIndexUnblockFailed    = "IndexUnblockFailed"
+	eventReasonStorageChangeIgnored  = "StorageChangeIgnored"
+	eventReasonInvalidConfig         = "InvalidConfiguration"
+	eventReasonSecurityObjectSynced  = "SecurityObjectSynced"
+	eventReasonSecurityObjectDeleted = "SecurityObjectDeleted"
+	eventReasonIndexTemplateSynced   = "IndexTemplateSynced"
+	eventReasonIndexTemplateDeleted  = "IndexTemplateDeleted"
+	eventReasonIngestPipelineSynced  = "IngestPipelineSynced"
+	eventReasonIngestPipelineDeleted = "IngestPipelineDeleted"
+)
+
+// eventRecorderFunc records an event on the custom resource of a request
+type eventRecorderFunc func(eventType, reason, messageFmt string, args ...interface{})
+
+// recordEvent records an event on the Elasticsearch custom resource. Requests created without
+// a recorder, such as the ones of the secret controller, record nothing
+func (er *ElasticsearchRequest) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if er.recorder == nil {
+		return
+	}
+	er.recorder.Eventf(er.cluster, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,82 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("events.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		recorder *record.FakeRecorder
+		chatter  *helpers.FakeElasticsearchChatter
+		request  *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+	})
+
+	JustBeforeEach(func() {
+		cluster := &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		}
+		k8sClient := fake.NewFakeClient(cluster)
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+			recorder: recorder,
+		}
+	})
+
+	It("should record the restart of a node", func() {
+		restarter := Restarter{
+			scheduledNodes: []NodeTypeInterface{&deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1"}}}},
+			recorder:       request.recordEvent,
+			precheck:       r.restartNoop,
+			prep:           r.restartNoop,
+			main:           r.restartNoop,
+			post:           r.restartNoop,
+			recovery:       r.restartNoop,
+			nodeStatus:     &api.ElasticsearchNodeStatus{},
+		}
+		restarter.setNodeConditions(func() {})
+
+		Expect(restarter.restartCluster()).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal("Normal NodeRestarting Beginning restart of node elasticsearch-cdm-abc-1")))
+		Expect(recorder.Events).To(Receive(Equal("Normal NodeRestarted Completed restart of node elasticsearch-cdm-abc-1")))
+	})
+
+	Context("when unblocking an index", func() {
+		BeforeEach(func() {
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+				"app-000001/_settings": {
+					{StatusCode: 200, Body: `{"acknowledged": true}`},
+				},
+			})
+		})
+
+		It("should record the removed block", func() {
+			Expect(request.unblockIndex("app-000001")).To(Succeed())
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal IndexUnblocked Removed the read-only block of index app-000001")))
+		})
+	})
+
+	It("should record nothing without a recorder", func() {
+		request.recorder = nil
+		request.recordEvent(v1.EventTypeWarning, eventReasonInvalidConfig, "Skipping the changes of the spec")
+		Expect(recorder.Events).ToNot(Receive())
+	})
+})
@@ -1,20 +1,30 @@
 package k8shandler
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/ViaQ/logerr/kverrors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/ViaQ/logerr/log"
 	logging "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	"github.com/openshift/elasticsearch-operator/internal/constants"
 	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
 	esapi "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (er *ElasticsearchRequest) CreateOrUpdateIndexManagement() error {
 	cluster := er.cluster
+	if err := indexmanagement.RemoveScriptsConfigmap(er.client, cluster); err != nil {
+		log.Error(err, "Unable to remove the index management scripts configmap")
+	}
 	if cluster.Spec.IndexManagement == nil {
 		return nil
 	}
@@ -26,7 +36,7 @@ func (er *ElasticsearchRequest) CreateOrUpdateIndexManagement() error {
 		for _, mapping := range spec.Mappings {
 			ll := log.WithValues("mapping", mapping.Name)
 			// create or update template
-			if err := er.createOrUpdateIndexTemplate(mapping); err != nil {
+			if err := er.createOrUpdateIndexTemplate(mapping, policies[mapping.PolicyRef]); err != nil {
 				ll.Error(err, "failed to create index template")
 				return err
 			}
@@ -36,11 +46,10 @@ func (er *ElasticsearchRequest) CreateOrUpdateIndexManagement() error {
 				return err
 			}
 		}
-	}
 
-	if err := indexmanagement.ReconcileCurationConfigmap(er.client, er.cluster); err != nil {
-		return err
+		er.updatePolicyPhaseStatus(spec)
 	}
+
 	primaryShards := getDataCount(er.cluster)
 	for _, mapping := range spec.Mappings {
 		policy := policies[mapping.PolicyRef]
@@ -50,10 +59,131 @@ func (er *ElasticsearchRequest) CreateOrUpdateIndexManagement() error {
 			return err
 		}
 	}
+	er.updateMappingRunStatus(spec)
+
+	return er.updateIndexManagementStatus()
+}
+
+// updateMappingRunStatus reports the outcome of the last index management job of every accepted mapping
+func (er *ElasticsearchRequest) updateMappingRunStatus(spec *logging.IndexManagementSpec) {
+	status := er.cluster.Status.IndexManagementStatus
+	mappings := map[string]logging.IndexManagementPolicyMappingSpec{}
+	for _, mapping := range spec.Mappings {
+		mappings[mapping.Name] = mapping
+	}
+
+	for i, mappingStatus := range status.Mappings {
+		mapping, ok := mappings[mappingStatus.Name]
+		if !ok || mappingStatus.State != logging.IndexManagementMappingStateAccepted {
+			continue
+		}
+
+		lastRun, err := indexmanagement.GetLastRunStatus(er.client, er.cluster, mapping)
+		if err != nil {
+			log.Error(err, "Unable to get the last index management run", "mapping", mapping.Name)
+			continue
+		}
+		status.Mappings[i].LastRun = lastRun
+		if condition := indexmanagement.NewRunsFailingCondition(lastRun); condition != nil {
+			status.Mappings[i].Conditions = append(status.Mappings[i].Conditions, *condition)
+		}
+	}
+}
+
+// updatePolicyPhaseStatus reports the number of indices in each phase for every accepted policy
+func (er *ElasticsearchRequest) updatePolicyPhaseStatus(spec *logging.IndexManagementSpec) {
+	status := er.cluster.Status.IndexManagementStatus
+	policies := spec.PolicyMap()
+	now := time.Now()
+
+	for i, policyStatus := range status.Policies {
+		policy, ok := policies[policyStatus.Name]
+		if !ok || policyStatus.State != logging.IndexManagementPolicyStateAccepted {
+			continue
+		}
+
+		creationDates := map[string]time.Time{}
+		for _, mapping := range spec.Mappings {
+			if mapping.PolicyRef != policy.Name {
+				continue
+			}
+			dates, err := er.esClient.GetIndicesCreationDate(formatWriteAlias(mapping))
+			if err != nil {
+				log.Error(err, "Unable to get index creation dates for policy phase status", "mapping", mapping.Name)
+				continue
+			}
+			for index, created := range dates {
+				creationDates[index] = created
+			}
+		}
+		status.Policies[i].Phases = indexmanagement.CalculatePhaseStatus(policy, creationDates, now)
+	}
+}
 
+// updateIndexManagementStatus persists the index management status when it changed
+// other than by its last updated timestamps
+func (er *ElasticsearchRequest) updateIndexManagementStatus() error {
+	cluster := er.cluster
+	status := cluster.Status.IndexManagementStatus.DeepCopy()
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		current := &logging.Elasticsearch{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, current); err != nil {
+			return err
+		}
+
+		if isIndexManagementStatusSame(current.Status.IndexManagementStatus, status) {
+			return nil
+		}
+
+		current.Status.IndexManagementStatus = status
+		return er.client.Status().Update(context.TODO(), current)
+	})
+
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update index management status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
 	return nil
 }
 
+func isIndexManagementStatusSame(lhs, rhs *logging.IndexManagementStatus) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+
+	withoutTimestamps := func(status *logging.IndexManagementStatus) *logging.IndexManagementStatus {
+		status = status.DeepCopy()
+		status.LastUpdated = metav1.Time{}
+		for i := range status.Policies {
+			status.Policies[i].LastUpdated = metav1.Time{}
+		}
+		for i := range status.Mappings {
+			status.Mappings[i].LastUpdated = metav1.Time{}
+		}
+		return status
+	}
+
+	return reflect.DeepEqual(withoutTimestamps(lhs), withoutTimestamps(rhs))
+}
+
+func isIndexTemplateRoutingSame(current, desired *esapi.IndexRoutingSettings) bool {
+	var currentRequire, desiredRequire map[string]string
+	if current != nil {
+		currentRequire = current.Allocation.Require
+	}
+	if desired != nil {
+		desiredRequire = desired.Allocation.Require
+	}
+	if len(currentRequire) == 0 && len(desiredRequire) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(currentRequire, desiredRequire)
+}
+
 func (er *ElasticsearchRequest) cullIndexManagement(mappings []logging.IndexManagementPolicyMappingSpec, policies logging.PolicyMap) {
 	cluster := er.cluster
 	client := er.client
@@ -115,7 +245,10 @@ func formatWriteAlias(mapping logging.IndexManagementPolicyMappingSpec) string {
 	return fmt.Sprintf("%s-write", mapping.Name)
 }
 
-func (er *ElasticsearchRequest) createOrUpdateIndexTemplate(mapping logging.IndexManagementPolicyMappingSpec) error {
+// createOrUpdateIndexTemplate creates the template of the indices of the mapping. New indices
+// are created on the nodes required by the hot phase of the policy and run documents through
+// the default pipeline of the mapping once it is synced
+func (er *ElasticsearchRequest) createOrUpdateIndexTemplate(mapping logging.IndexManagementPolicyMappingSpec, policy logging.IndexManagementPolicySpec) error {
 	cluster := er.cluster
 	esClient := er.esClient
 
@@ -125,6 +258,19 @@ func (er *ElasticsearchRequest) createOrUpdateIndexTemplate(mapping logging.Inde
 	replicas := int32(calculateReplicaCount(cluster))
 	aliases := append(mapping.Aliases, mapping.Name)
 	template := esapi.NewIndexTemplate(pattern, aliases, primaryShards, replicas)
+	if hot := policy.Phases.Hot; hot != nil && hot.Actions.Allocation != nil {
+		template.Settings.Index.Routing = &esapi.IndexRoutingSettings{
+			Allocation: esapi.IndexRoutingAllocationSettings{Require: hot.Actions.Allocation.Require},
+		}
+	}
+	// only pipelines written to the cluster are bound, indexing into new indices fails otherwise
+	if mapping.DefaultPipeline != "" {
+		if isIngestPipelineSynced(mapping.DefaultPipeline, cluster.Status.IngestPipelines) {
+			template.Settings.Index.DefaultPipeline = mapping.DefaultPipeline
+		} else {
+			er.L().Info("Not binding the ingest pipeline which is not synced", "mapping", mapping.Name, "pipeline", mapping.DefaultPipeline)
+		}
+	}
 
 	// check to compare the current index templates vs what we just generated
 	templates, err := esClient.GetIndexTemplates()
@@ -132,8 +278,10 @@ func (er *ElasticsearchRequest) createOrUpdateIndexTemplate(mapping logging.Inde
 		return err
 	}
 
-	for templateName := range templates {
-		if templateName == name {
+	for templateName, current := range templates {
+		if templateName == name &&
+			isIndexTemplateRoutingSame(current.Settings.Index.Routing, template.Settings.Index.Routing) &&
+			current.Settings.Index.DefaultPipeline == template.Settings.Index.DefaultPipeline {
 			return nil
 		}
 	}
@@ -117,9 +117,91 @@ var _ = Describe("Index Management", func() {
 				},
 			)
 			request.esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", request.client, chatter)
+			request.cluster.Status.IngestPipelines = nil
 		})
 		It("should create an elasticsearch index template to support the index", func() {
-			Expect(request.createOrUpdateIndexTemplate(mapping)).To(BeNil())
+			Expect(request.createOrUpdateIndexTemplate(mapping, elasticsearch.IndexManagementPolicySpec{})).To(BeNil())
+			req, _ := chatter.GetRequest("_template/ocp-gen-node.infra")
+			helpers.ExpectJSON(req.Body).ToEqual(
+				`{
+					"aliases": {
+						"infra": {},
+						"node.infra" : {}
+					},
+					"settings": {
+						"index": {
+							"number_of_replicas": "1",
+							"number_of_shards": "3"
+						}
+					},
+					"template": "node.infra*"
+				}`)
+		})
+		It("should pin new indices to the nodes required by the hot phase", func() {
+			policy := elasticsearch.IndexManagementPolicySpec{
+				Phases: elasticsearch.IndexManagementPhasesSpec{
+					Hot: &elasticsearch.IndexManagementHotPhaseSpec{
+						Actions: elasticsearch.IndexManagementActionsSpec{
+							Allocation: &elasticsearch.IndexManagementAllocationActionSpec{
+								Require: map[string]string{"box_type": "hot"},
+							},
+						},
+					},
+				},
+			}
+			Expect(request.createOrUpdateIndexTemplate(mapping, policy)).To(BeNil())
+			req, _ := chatter.GetRequest("_template/ocp-gen-node.infra")
+			helpers.ExpectJSON(req.Body).ToEqual(
+				`{
+					"aliases": {
+						"infra": {},
+						"node.infra" : {}
+					},
+					"settings": {
+						"index": {
+							"number_of_replicas": "1",
+							"number_of_shards": "3",
+							"routing": {
+								"allocation": {
+									"require": {"box_type": "hot"}
+								}
+							}
+						}
+					},
+					"template": "node.infra*"
+				}`)
+		})
+		It("should bind new indices to the default pipeline of the mapping", func() {
+			withPipeline := mapping
+			withPipeline.DefaultPipeline = "lowercase-level"
+			request.cluster.Status.IngestPipelines = []elasticsearch.ElasticsearchIngestPipelineStatus{
+				{Name: "lowercase-level", State: elasticsearch.IngestPipelineSynced},
+			}
+			Expect(request.createOrUpdateIndexTemplate(withPipeline, elasticsearch.IndexManagementPolicySpec{})).To(BeNil())
+			req, _ := chatter.GetRequest("_template/ocp-gen-node.infra")
+			helpers.ExpectJSON(req.Body).ToEqual(
+				`{
+					"aliases": {
+						"infra": {},
+						"node.infra" : {}
+					},
+					"settings": {
+						"index": {
+							"number_of_replicas": "1",
+							"number_of_shards": "3",
+							"default_pipeline": "lowercase-level"
+						}
+					},
+					"template": "node.infra*"
+				}`)
+		})
+		It("should not bind new indices to a default pipeline which is not synced", func() {
+			withPipeline := mapping
+			withPipeline.DefaultPipeline = "lowercase-level"
+			request.cluster.Status.IngestPipelines = []elasticsearch.ElasticsearchIngestPipelineStatus{
+				{Name: "lowercase-level", State: elasticsearch.IngestPipelineFailed},
+			}
+			Expect(request.createOrUpdateIndexTemplate(withPipeline, elasticsearch.IndexManagementPolicySpec{})).To(BeNil())
 			req, _ := chatter.GetRequest("_template/ocp-gen-node.infra")
 			helpers.ExpectJSON(req.Body).ToEqual(
 				`{
@@ -0,0 +1,230 @@
+package k8shandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/constants"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+// reservedTemplatePrefixes are the prefixes of the templates generated or updated by the operator
+var reservedTemplatePrefixes = []string{constants.OcpTemplatePrefix, "common."}
+
+var indexTemplateKind = managedObjectKind{name: "index template", syncedReason: eventReasonIndexTemplateSynced, deletedReason: eventReasonIndexTemplateDeleted}
+
+// CreateOrUpdateIndexTemplates writes the index templates of the spec whenever the ones in the
+// cluster differ, and deletes the ones removed from the spec
+func (er *ElasticsearchRequest) CreateOrUpdateIndexTemplates() error {
+	cluster := er.cluster
+	previous := cluster.Status.IndexTemplates
+	if len(cluster.Spec.IndexTemplates) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	// the status is kept until the templates can be reached
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	// the templates with reserved names are only reported by the validating webhook, which is
+	// optional, so they are skipped here instead of overwriting the ones of the operator
+	templates := []api.ElasticsearchIndexTemplate{}
+	names := []string{}
+	for _, spec := range cluster.Spec.IndexTemplates {
+		if isReservedTemplateName(spec.Name) {
+			continue
+		}
+		templates = append(templates, spec)
+		names = append(names, spec.Name)
+	}
+	previousTemplates := fromIndexTemplateStatuses(previous)
+
+	synced := er.syncManagedObjects(indexTemplateKind, names, previousTemplates, func(i int) (bool, bool, error) {
+		written, err := er.syncIndexTemplate(templates[i], ownsManagedObject(templates[i].Name, previousTemplates))
+		return written, false, err
+	})
+	synced = append(synced, er.deleteManagedObjects(indexTemplateKind, names, previousTemplates, er.esClient.DeleteIndexTemplate)...)
+
+	var statuses []api.ElasticsearchIndexTemplateStatus
+	if len(synced) != 0 {
+		statuses = toIndexTemplateStatuses(synced)
+	}
+	return er.updateStatusWithRetry("index templates", func(current *api.ElasticsearchStatus) bool {
+		if reflect.DeepEqual(current.IndexTemplates, statuses) {
+			return false
+		}
+		current.IndexTemplates = statuses
+		return true
+	})
+}
+
+// syncIndexTemplate writes the template unless the one in the cluster matches the spec or was
+// not written by the operator. It returns whether it wrote the template
+func (er *ElasticsearchRequest) syncIndexTemplate(spec api.ElasticsearchIndexTemplate, owned bool) (bool, error) {
+	desired, err := newRawIndexTemplate(spec)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := er.esClient.GetRawIndexTemplate(spec.Name)
+	if err != nil {
+		return false, err
+	}
+	if current != nil && !owned {
+		return false, newManagedObjectNotOwnedError(indexTemplateKind, spec.Name)
+	}
+	if current != nil && isRawIndexTemplateSame(current, desired) {
+		return false, nil
+	}
+	return true, er.esClient.PutRawIndexTemplate(spec.Name, desired)
+}
+
+func newRawIndexTemplate(spec api.ElasticsearchIndexTemplate) (*estypes.RawIndexTemplate, error) {
+	settings, err := decodeRawObject(spec.Settings)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid settings", "template", spec.Name)
+	}
+	mappings, err := decodeRawObject(spec.Mappings)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid mappings", "template", spec.Name)
+	}
+
+	return &estypes.RawIndexTemplate{
+		Order:         spec.Order,
+		IndexPatterns: spec.IndexPatterns,
+		Settings:      settings,
+		Mappings:      mappings,
+	}, nil
+}
+
+// decodeRawObject decodes a JSON object of the spec, nil when it is not set
+func decodeRawObject(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(raw.Raw, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// isRawIndexTemplateSame compares the templates the way Elasticsearch stores them: the order of
+// the patterns does not matter and the settings are flattened, prefixed with index. and strings
+func isRawIndexTemplateSame(current, desired *estypes.RawIndexTemplate) bool {
+	if current.Order != desired.Order {
+		return false
+	}
+	if !sets.NewString(current.IndexPatterns...).Equal(sets.NewString(desired.IndexPatterns...)) {
+		return false
+	}
+	if !reflect.DeepEqual(flattenIndexSettings(current.Settings), flattenIndexSettings(desired.Settings)) {
+		return false
+	}
+	if len(current.Mappings) == 0 && len(desired.Mappings) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(current.Mappings, desired.Mappings)
+}
+
+// flattenIndexSettings returns the settings keyed by their full name, e.g. index.refresh_interval
+func flattenIndexSettings(settings map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+	flattenSettings("", settings, flat)
+
+	normalized := map[string]interface{}{}
+	for key, value := range flat {
+		if !strings.HasPrefix(key, "index.") {
+			key = "index." + key
+		}
+		normalized[key] = value
+	}
+	return normalized
+}
+
+func flattenSettings(prefix string, settings map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenSettings(key, nested, flat)
+			continue
+		}
+		flat[key] = normalizeSettingValue(value)
+	}
+}
+
+// normalizeSettingValue returns the value of a setting as Elasticsearch returns it, as strings
+func normalizeSettingValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = normalizeSettingValue(item)
+		}
+		return values
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func isReservedTemplateName(name string) bool {
+	for _, prefix := range reservedTemplatePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func fromIndexTemplateStatuses(statuses []api.ElasticsearchIndexTemplateStatus) []managedObjectStatus {
+	objs := []managedObjectStatus{}
+	for _, status := range statuses {
+		objs = append(objs, managedObjectStatus{Name: status.Name, State: string(status.State), Message: status.Message, LastSyncTime: status.LastSyncTime})
+	}
+	return objs
+}
+
+func toIndexTemplateStatuses(objs []managedObjectStatus) []api.ElasticsearchIndexTemplateStatus {
+	statuses := []api.ElasticsearchIndexTemplateStatus{}
+	for _, obj := range objs {
+		statuses = append(statuses, api.ElasticsearchIndexTemplateStatus{Name: obj.Name, State: api.ElasticsearchIndexTemplateState(obj.State), Message: obj.Message, LastSyncTime: obj.LastSyncTime})
+	}
+	return statuses
+}
+
+// validateIndexTemplates returns the reasons why index templates of the spec would be ignored
+func validateIndexTemplates(templates []api.ElasticsearchIndexTemplate) []string {
+	var reasons []string
+
+	names := sets.NewString()
+	for _, template := range templates {
+		if names.Has(template.Name) {
+			reasons = append(reasons, fmt.Sprintf("Index template '%s' is defined more than once", template.Name))
+		}
+		names.Insert(template.Name)
+
+		if isReservedTemplateName(template.Name) {
+			reasons = append(reasons, fmt.Sprintf("Index template '%s' uses a name reserved for the templates of the operator (%s)", template.Name, strings.Join(reservedTemplatePrefixes, ", ")))
+		}
+		if _, err := newRawIndexTemplate(template); err != nil {
+			reasons = append(reasons, fmt.Sprintf("Index template '%s' must define its settings and mappings as JSON objects", template.Name))
+		}
+	}
+
+	return reasons
+}
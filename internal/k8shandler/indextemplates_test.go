@@ -0,0 +1,161 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("indextemplates.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	teamA := api.ElasticsearchIndexTemplate{
+		Name:          "team-a",
+		IndexPatterns: []string{"app-team-a-*", "app-team-a2-*"},
+		Order:         10,
+		Settings:      &runtime.RawExtension{Raw: []byte(`{"index": {"refresh_interval": "30s", "number_of_replicas": 1}}`)},
+		Mappings:      &runtime.RawExtension{Raw: []byte(`{"_doc": {"properties": {"team": {"type": "keyword"}}}}`)},
+	}
+
+	Describe("#isRawIndexTemplateSame", func() {
+		var desired *estypes.RawIndexTemplate
+
+		BeforeEach(func() {
+			var err error
+			desired, err = newRawIndexTemplate(teamA)
+			Expect(err).To(BeNil())
+		})
+
+		It("should match the template as stored by Elasticsearch", func() {
+			current := &estypes.RawIndexTemplate{
+				Order:         10,
+				IndexPatterns: []string{"app-team-a2-*", "app-team-a-*"},
+				Settings:      map[string]interface{}{"index.refresh_interval": "30s", "index.number_of_replicas": "1"},
+				Mappings:      map[string]interface{}{"_doc": map[string]interface{}{"properties": map[string]interface{}{"team": map[string]interface{}{"type": "keyword"}}}},
+			}
+			Expect(isRawIndexTemplateSame(current, desired)).To(BeTrue())
+		})
+
+		It("should detect the settings which drifted", func() {
+			current := &estypes.RawIndexTemplate{
+				Order:         10,
+				IndexPatterns: []string{"app-team-a-*", "app-team-a2-*"},
+				Settings:      map[string]interface{}{"index.refresh_interval": "5s", "index.number_of_replicas": "1"},
+				Mappings:      desired.Mappings,
+			}
+			Expect(isRawIndexTemplateSame(current, desired)).To(BeFalse())
+		})
+
+		It("should detect the mappings which drifted", func() {
+			current := &estypes.RawIndexTemplate{
+				Order:         10,
+				IndexPatterns: []string{"app-team-a-*", "app-team-a2-*"},
+				Settings:      desired.Settings,
+				Mappings:      map[string]interface{}{"_doc": map[string]interface{}{"properties": map[string]interface{}{"team": map[string]interface{}{"type": "text"}}}},
+			}
+			Expect(isRawIndexTemplateSame(current, desired)).To(BeFalse())
+		})
+	})
+
+	Describe("#CreateOrUpdateIndexTemplates", func() {
+		const (
+			getURI = "_template/team-a?flat_settings=true"
+			putURI = "_template/team-a"
+		)
+
+		var (
+			cluster *api.Elasticsearch
+			chatter *helpers.FakeElasticsearchChatter
+			request *ElasticsearchRequest
+		)
+
+		BeforeEach(func() {
+			cluster = newManagedObjectsCluster(api.ElasticsearchSpec{
+				IndexTemplates: []api.ElasticsearchIndexTemplate{teamA, {Name: "ocp-gen-app", IndexPatterns: []string{"app-*"}}},
+			})
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+				getURI: {
+					{StatusCode: 404, Body: `{}`},
+				},
+				putURI: {
+					{StatusCode: 200, Body: `{"acknowledged": true}`},
+				},
+			})
+		})
+
+		JustBeforeEach(func() {
+			request = newManagedObjectsRequest(cluster, chatter)
+		})
+
+		It("should write the missing template and leave the ones of the operator as is", func() {
+			Expect(request.CreateOrUpdateIndexTemplates()).To(Succeed())
+
+			req, found := chatter.GetRequest(putURI)
+			Expect(found).To(BeTrue())
+			Expect(req.Method).To(Equal(http.MethodPut))
+			Expect(chatter.Requests).ToNot(HaveKey("_template/ocp-gen-app?flat_settings=true"))
+
+			status := getClusterStatus(request).IndexTemplates
+			Expect(status).To(HaveLen(1))
+			Expect(status[0].Name).To(Equal("team-a"))
+			Expect(status[0].LastSyncTime).ToNot(BeNil())
+		})
+
+		Context("when a template of the same name was not written by the operator", func() {
+			BeforeEach(func() {
+				chatter.Responses[getURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"team-a": {"order": 0, "index_patterns": ["app-*"], "settings": {}, "mappings": {}}}`},
+				}
+			})
+
+			It("should not overwrite the template", func() {
+				Expect(request.CreateOrUpdateIndexTemplates()).To(Succeed())
+
+				Expect(chatter.Requests).ToNot(HaveKey(putURI))
+				status := getClusterStatus(request).IndexTemplates
+				Expect(status[0].State).To(Equal(api.IndexTemplateFailed))
+				Expect(status[0].Message).To(ContainSubstring("not written by the operator"))
+				Expect(status[0].LastSyncTime).To(BeNil())
+			})
+		})
+
+		Context("when the templates are removed from the spec", func() {
+			BeforeEach(func() {
+				lastSync := metav1.Now()
+				cluster.Spec.IndexTemplates = nil
+				cluster.Status.IndexTemplates = []api.ElasticsearchIndexTemplateStatus{
+					{Name: "team-a", State: api.IndexTemplateSynced, LastSyncTime: &lastSync},
+					{Name: "team-b", State: api.IndexTemplateFailed, Message: "Unable to write the index template: the index template exists and was not written by the operator"},
+				}
+			})
+
+			It("should only delete the templates written by the operator", func() {
+				Expect(request.CreateOrUpdateIndexTemplates()).To(Succeed())
+
+				req, found := chatter.GetRequest(putURI)
+				Expect(found).To(BeTrue())
+				Expect(req.Method).To(Equal(http.MethodDelete))
+				Expect(chatter.Requests).ToNot(HaveKey("_template/team-b"))
+				Expect(getClusterStatus(request).IndexTemplates).To(BeNil())
+			})
+		})
+	})
+
+	Describe("#validateIndexTemplates", func() {
+		It("should reject the names of the templates of the operator", func() {
+			templates := []api.ElasticsearchIndexTemplate{{Name: "ocp-gen-app", IndexPatterns: []string{"app-*"}}}
+			Expect(validateIndexTemplates(templates)).To(ConsistOf(ContainSubstring("reserved for the templates of the operator")))
+		})
+	})
+})
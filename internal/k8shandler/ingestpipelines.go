@@ -0,0 +1,199 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+var ingestPipelineKind = managedObjectKind{name: "ingest pipeline", syncedReason: eventReasonIngestPipelineSynced, deletedReason: eventReasonIngestPipelineDeleted}
+
+// CreateOrUpdateIngestPipelines writes the ingest pipelines of the spec whenever the ones in the
+// cluster differ, and deletes the ones removed from the spec
+func (er *ElasticsearchRequest) CreateOrUpdateIngestPipelines() error {
+	cluster := er.cluster
+	previous := cluster.Status.IngestPipelines
+	if len(cluster.Spec.IngestPipelines) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	// the status is kept until the pipelines can be reached
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	names := []string{}
+	for _, spec := range cluster.Spec.IngestPipelines {
+		names = append(names, spec.Name)
+	}
+	previousPipelines := fromIngestPipelineStatuses(previous)
+
+	synced := er.syncManagedObjects(ingestPipelineKind, names, previousPipelines, func(i int) (bool, bool, error) {
+		written, err := er.syncIngestPipeline(cluster.Spec.IngestPipelines[i], ownsManagedObject(names[i], previousPipelines))
+		return written, false, err
+	})
+	synced = append(synced, er.deleteManagedObjects(ingestPipelineKind, names, previousPipelines, er.deleteIngestPipeline)...)
+
+	var statuses []api.ElasticsearchIngestPipelineStatus
+	if len(synced) != 0 {
+		statuses = toIngestPipelineStatuses(synced)
+	}
+	return er.updateStatusWithRetry("ingest pipelines", func(current *api.ElasticsearchStatus) bool {
+		if reflect.DeepEqual(current.IngestPipelines, statuses) {
+			return false
+		}
+		current.IngestPipelines = statuses
+		return true
+	})
+}
+
+// syncIngestPipeline writes the pipeline unless the one in the cluster matches the spec or was
+// not written by the operator. It returns whether it wrote the pipeline
+func (er *ElasticsearchRequest) syncIngestPipeline(spec api.ElasticsearchIngestPipeline, owned bool) (bool, error) {
+	desired, err := newIngestPipeline(spec)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := er.esClient.GetIngestPipeline(spec.Name)
+	if err != nil {
+		return false, err
+	}
+	if current != nil && !owned {
+		return false, newManagedObjectNotOwnedError(ingestPipelineKind, spec.Name)
+	}
+	if current != nil && isIngestPipelineSame(current, desired) {
+		return false, nil
+	}
+	return true, er.esClient.PutIngestPipeline(spec.Name, desired)
+}
+
+// deleteIngestPipeline deletes the pipeline unless it is still the default pipeline of indices,
+// which would reject documents without it. It is deleted once these indices are
+func (er *ElasticsearchRequest) deleteIngestPipeline(name string) error {
+	pipelines, err := er.esClient.GetIndicesDefaultPipeline("*")
+	if err != nil {
+		return err
+	}
+
+	indices := []string{}
+	for index, pipeline := range pipelines {
+		if pipeline == name {
+			indices = append(indices, index)
+		}
+	}
+	if len(indices) > 0 {
+		sort.Strings(indices)
+		return kverrors.New(fmt.Sprintf("still the default pipeline of the indices %s", strings.Join(indices, ", ")),
+			"pipeline", name)
+	}
+	return er.esClient.DeleteIngestPipeline(name)
+}
+
+func newIngestPipeline(spec api.ElasticsearchIngestPipeline) (*estypes.IngestPipeline, error) {
+	processors, err := decodeProcessors(spec.Processors)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid processors", "pipeline", spec.Name)
+	}
+	onFailure, err := decodeProcessors(spec.OnFailure)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid failure processors", "pipeline", spec.Name)
+	}
+
+	return &estypes.IngestPipeline{
+		Description: spec.Description,
+		Processors:  processors,
+		OnFailure:   onFailure,
+	}, nil
+}
+
+func decodeProcessors(processors []api.ElasticsearchIngestProcessor) ([]map[string]interface{}, error) {
+	if len(processors) == 0 {
+		return nil, nil
+	}
+	objs := make([]map[string]interface{}, 0, len(processors))
+	for i := range processors {
+		obj, err := decodeRawObject(&processors[i].RawExtension)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// isIngestPipelineSame compares the pipelines, the order of the processors matters
+func isIngestPipelineSame(current, desired *estypes.IngestPipeline) bool {
+	if current.Description != desired.Description {
+		return false
+	}
+	if !reflect.DeepEqual(current.Processors, desired.Processors) {
+		return false
+	}
+	if len(current.OnFailure) == 0 && len(desired.OnFailure) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(current.OnFailure, desired.OnFailure)
+}
+
+func fromIngestPipelineStatuses(statuses []api.ElasticsearchIngestPipelineStatus) []managedObjectStatus {
+	objs := []managedObjectStatus{}
+	for _, status := range statuses {
+		objs = append(objs, managedObjectStatus{Name: status.Name, State: string(status.State), Message: status.Message, LastSyncTime: status.LastSyncTime})
+	}
+	return objs
+}
+
+func toIngestPipelineStatuses(objs []managedObjectStatus) []api.ElasticsearchIngestPipelineStatus {
+	statuses := []api.ElasticsearchIngestPipelineStatus{}
+	for _, obj := range objs {
+		statuses = append(statuses, api.ElasticsearchIngestPipelineStatus{Name: obj.Name, State: api.ElasticsearchIngestPipelineState(obj.State), Message: obj.Message, LastSyncTime: obj.LastSyncTime})
+	}
+	return statuses
+}
+
+// isIngestPipelineSynced returns whether the status reports the pipeline as written to the cluster
+func isIngestPipelineSynced(name string, statuses []api.ElasticsearchIngestPipelineStatus) bool {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status.State == api.IngestPipelineSynced
+		}
+	}
+	return false
+}
+
+// validateIngestPipelines returns the reasons why ingest pipelines of the spec would be ignored
+// or indices would be bound to pipelines which are not managed
+func validateIngestPipelines(cluster *api.Elasticsearch) []string {
+	var reasons []string
+
+	names := sets.NewString()
+	for _, pipeline := range cluster.Spec.IngestPipelines {
+		if names.Has(pipeline.Name) {
+			reasons = append(reasons, fmt.Sprintf("Ingest pipeline '%s' is defined more than once", pipeline.Name))
+		}
+		names.Insert(pipeline.Name)
+
+		if _, err := newIngestPipeline(pipeline); err != nil {
+			reasons = append(reasons, fmt.Sprintf("Ingest pipeline '%s' must define its processors as JSON objects", pipeline.Name))
+		}
+	}
+
+	if cluster.Spec.IndexManagement == nil {
+		return reasons
+	}
+	for _, mapping := range cluster.Spec.IndexManagement.Mappings {
+		if mapping.DefaultPipeline != "" && !names.Has(mapping.DefaultPipeline) {
+			reasons = append(reasons, fmt.Sprintf("Index management mapping '%s' uses the ingest pipeline '%s' which is not defined", mapping.Name, mapping.DefaultPipeline))
+		}
+	}
+
+	return reasons
+}
@@ -0,0 +1,149 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("ingestpipelines.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	lowercaseLevel := api.ElasticsearchIngestPipeline{
+		Name:        "lowercase-level",
+		Description: "Lowercase the level",
+		Processors: []api.ElasticsearchIngestProcessor{
+			{RawExtension: runtime.RawExtension{Raw: []byte(`{"lowercase": {"field": "level", "ignore_missing": true}}`)}},
+			{RawExtension: runtime.RawExtension{Raw: []byte(`{"trim": {"field": "level"}}`)}},
+		},
+	}
+
+	Describe("#isIngestPipelineSame", func() {
+		var desired *estypes.IngestPipeline
+
+		BeforeEach(func() {
+			var err error
+			desired, err = newIngestPipeline(lowercaseLevel)
+			Expect(err).To(BeNil())
+		})
+
+		It("should match the pipeline without failure processors", func() {
+			current := &estypes.IngestPipeline{
+				Description: desired.Description,
+				Processors:  desired.Processors,
+				OnFailure:   []map[string]interface{}{},
+			}
+			Expect(isIngestPipelineSame(current, desired)).To(BeTrue())
+		})
+
+		It("should detect the processors which were reordered", func() {
+			current := &estypes.IngestPipeline{
+				Description: desired.Description,
+				Processors:  []map[string]interface{}{desired.Processors[1], desired.Processors[0]},
+			}
+			Expect(isIngestPipelineSame(current, desired)).To(BeFalse())
+		})
+	})
+
+	Describe("#CreateOrUpdateIngestPipelines", func() {
+		const (
+			pipelineURI = "_ingest/pipeline/lowercase-level"
+			settingsURI = "*/_settings/index.default_pipeline"
+		)
+
+		var (
+			cluster *api.Elasticsearch
+			chatter *helpers.FakeElasticsearchChatter
+			request *ElasticsearchRequest
+		)
+
+		BeforeEach(func() {
+			cluster = newManagedObjectsCluster(api.ElasticsearchSpec{
+				IngestPipelines: []api.ElasticsearchIngestPipeline{lowercaseLevel},
+			})
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+		})
+
+		JustBeforeEach(func() {
+			request = newManagedObjectsRequest(cluster, chatter)
+		})
+
+		Context("when a pipeline of the same name was not written by the operator", func() {
+			BeforeEach(func() {
+				chatter.Responses[pipelineURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"lowercase-level": {"processors": [{"set": {"field": "team", "value": "b"}}]}}`},
+				}
+			})
+
+			It("should neither overwrite the pipeline nor bind it to the mappings", func() {
+				Expect(request.CreateOrUpdateIngestPipelines()).To(Succeed())
+
+				Expect(chatter.Requests[pipelineURI]).To(HaveLen(1))
+				status := getClusterStatus(request).IngestPipelines
+				Expect(status[0].State).To(Equal(api.IngestPipelineFailed))
+				Expect(status[0].Message).To(ContainSubstring("not written by the operator"))
+				Expect(isIngestPipelineSynced("lowercase-level", status)).To(BeFalse())
+			})
+		})
+
+		Context("when the pipelines are removed from the spec", func() {
+			BeforeEach(func() {
+				lastSync := metav1.Now()
+				cluster.Spec.IngestPipelines = nil
+				cluster.Status.IngestPipelines = []api.ElasticsearchIngestPipelineStatus{
+					{Name: "lowercase-level", State: api.IngestPipelineSynced, LastSyncTime: &lastSync},
+					{Name: "parse-team", State: api.IngestPipelineFailed, Message: "Unable to write the ingest pipeline: the ingest pipeline exists and was not written by the operator"},
+				}
+				chatter.Responses[settingsURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"app-000001": {"settings": {"index": {"default_pipeline": "lowercase-level"}}}}`},
+					{StatusCode: 200, Body: `{"app-000002": {"settings": {}}}`},
+				}
+				chatter.Responses[pipelineURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"acknowledged": true}`},
+				}
+			})
+
+			It("should keep the pipeline written by the operator until no index uses it", func() {
+				Expect(request.CreateOrUpdateIngestPipelines()).To(Succeed())
+
+				Expect(chatter.Requests).ToNot(HaveKey(pipelineURI))
+				Expect(chatter.Requests).ToNot(HaveKey("_ingest/pipeline/parse-team"))
+				status := getClusterStatus(request).IngestPipelines
+				Expect(status).To(HaveLen(1))
+				Expect(status[0].State).To(Equal(api.IngestPipelineFailed))
+				Expect(status[0].Message).To(HaveSuffix("still the default pipeline of the indices app-000001"))
+				Expect(status[0].LastSyncTime).ToNot(BeNil())
+
+				Expect(request.CreateOrUpdateIngestPipelines()).To(Succeed())
+
+				req, found := chatter.GetRequest(pipelineURI)
+				Expect(found).To(BeTrue())
+				Expect(req.Method).To(Equal(http.MethodDelete))
+				Expect(getClusterStatus(request).IngestPipelines).To(BeNil())
+			})
+		})
+	})
+
+	Describe("#validateIngestPipelines", func() {
+		It("should reject mappings bound to pipelines which are not defined", func() {
+			cluster := newManagedObjectsCluster(api.ElasticsearchSpec{
+				IngestPipelines: []api.ElasticsearchIngestPipeline{lowercaseLevel},
+				IndexManagement: &api.IndexManagementSpec{
+					Mappings: []api.IndexManagementPolicyMappingSpec{{Name: "app", DefaultPipeline: "missing"}},
+				},
+			})
+			Expect(validateIngestPipelines(cluster)).To(ConsistOf("Index management mapping 'app' uses the ingest pipeline 'missing' which is not defined"))
+		})
+	})
+})
@@ -149,7 +149,7 @@ var _ = Describe("Reconciling", func() {
 			})
 
 			It("should create one new console link for the Kibana route", func() {
-				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{}, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: KibanaConsoleLinkName}
 				got := &consolev1.ConsoleLink{}
@@ -210,7 +210,7 @@ var _ = Describe("Reconciling", func() {
 			})
 
 			It("should replace existing sharing confimap links with one console link", func() {
-				Expect(Reconcile(cluster, client, esClient, nil, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(cluster, client, esClient, nil, false, metav1.OwnerReference{}, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: KibanaConsoleLinkName}
 				got := &consolev1.ConsoleLink{}
@@ -281,7 +281,7 @@ var _ = Describe("Reconciling", func() {
 
 			It("should use the default CA bundle in kibana proxy", func() {
 				// Reconcile w/o custom CA bundle
-				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{}, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: constants.KibanaTrustedCAName, Namespace: cluster.GetNamespace()}
 				kibanaCaBundle := &corev1.ConfigMap{}
@@ -302,7 +302,7 @@ var _ = Describe("Reconciling", func() {
 
 			It("should use the injected custom CA bundle in kibana proxy", func() {
 				// Reconcile w/o custom CA bundle
-				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{}, nil, nil)).Should(Succeed())
 
 				// Inject custom CA bundle into kibana config map
 				injectedCABundle := kibanaCABundle.DeepCopy()
@@ -311,7 +311,7 @@ var _ = Describe("Reconciling", func() {
 
 				// Reconcile with injected custom CA bundle
 				esClient = newFakeEsClient(client, fakeResponses)
-				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{})).Should(Succeed())
+				Expect(Reconcile(cluster, client, esClient, proxy, false, metav1.OwnerReference{}, nil, nil)).Should(Succeed())
 
 				key := types.NamespacedName{Name: cluster.GetName(), Namespace: cluster.GetNamespace()}
 				dpl := &appsv1.Deployment{}
@@ -7,6 +7,7 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -15,6 +16,15 @@ type KibanaRequest struct {
 	client   client.Client
 	cluster  *kibana.Kibana
 	esClient elasticsearch.Client
+	recorder record.EventRecorder
+}
+
+// recordEvent records an event on the Kibana custom resource if the request has a recorder
+func (clusterRequest *KibanaRequest) recordEvent(eventType, reason, messageFmt string, args ...interface{}) {
+	if clusterRequest.recorder == nil {
+		return
+	}
+	clusterRequest.recorder.Eventf(clusterRequest.cluster, eventType, reason, messageFmt, args...)
 }
 
 // TODO: determine if this is even necessary
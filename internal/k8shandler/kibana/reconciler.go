@@ -21,6 +21,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,11 +40,12 @@ var kibanaServiceAccountAnnotations = map[string]string{
 	"serviceaccounts.openshift.io/oauth-redirectreference.first": kibanaOAuthRedirectReference,
 }
 
-func Reconcile(requestCluster *kibana.Kibana, requestClient client.Client, esClient elasticsearch.Client, proxyConfig *configv1.Proxy, eoManagedCerts bool, ownerRef metav1.OwnerReference) error {
+func Reconcile(requestCluster *kibana.Kibana, requestClient client.Client, esClient elasticsearch.Client, proxyConfig *configv1.Proxy, eoManagedCerts bool, ownerRef metav1.OwnerReference, certSpec *kibana.ElasticsearchCertificatesSpec, recorder record.EventRecorder) error {
 	clusterKibanaRequest := KibanaRequest{
 		client:   requestClient,
 		cluster:  requestCluster,
 		esClient: esClient,
+		recorder: recorder,
 	}
 
 	migrationRequest := migrations.NewMigrationRequest(requestClient, esClient)
@@ -54,6 +56,7 @@ func Reconcile(requestCluster *kibana.Kibana, requestClient client.Client, esCli
 
 	if eoManagedCerts {
 		cr := k8shandler.NewCertificateRequest(ownerRef.Name, requestCluster.Namespace, ownerRef, requestClient)
+		cr.SetCertificatesSpec(certSpec)
 		cr.GenerateKibanaCerts(requestCluster.Name)
 	}
 
@@ -193,6 +196,7 @@ func (clusterRequest *KibanaRequest) deleteKibana5Deployment() error {
 		}
 		return kverrors.Wrap(err, "failed to delete kibana 5 deployment")
 	}
+	clusterRequest.recordEvent(v1.EventTypeNormal, "DeploymentDeleted", "Deleted the Kibana 5 deployment %s", kibana5.Name)
 	return nil
 }
 
@@ -300,9 +304,13 @@ func (clusterRequest *KibanaRequest) createOrUpdateKibanaDeployment(proxyConfig
 				}
 			}
 
-			if different {
-				return clusterRequest.Update(current)
+			if !different {
+				return nil
 			}
+			if err := clusterRequest.Update(current); err != nil {
+				return err
+			}
+			clusterRequest.recordEvent(v1.EventTypeNormal, "DeploymentUpdated", "Updated the Kibana deployment %s", current.Name)
 			return nil
 		})
 	}
@@ -0,0 +1,173 @@
+package k8shandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// ErrOutsideMaintenanceWindow indicates a restart held until the next maintenance window
+var ErrOutsideMaintenanceWindow = kverrors.New("restart held until the next maintenance window")
+
+// maxMaintenanceWindowDuration bounds the windows to the week searched for an open window
+const maxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+var maintenanceWindowDays = map[api.MaintenanceWindowDay]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// holdForMaintenance returns ErrOutsideMaintenanceWindow while the restart of the nodes has
+// to wait for the next maintenance window and reports it with the MaintenancePending condition.
+// Restarts for certificates expiring before the next window start right away
+func (er *ElasticsearchRequest) holdForMaintenance(restartNodes []NodeTypeInterface) error {
+	windows := er.cluster.Spec.MaintenanceWindows
+	if len(windows) == 0 {
+		return er.clearMaintenancePending()
+	}
+
+	open, next, err := maintenanceWindowOpen(windows, time.Now())
+	if err != nil {
+		er.L().Error(err, "ignoring the invalid maintenance windows")
+		return er.clearMaintenancePending()
+	}
+	if open {
+		return er.clearMaintenancePending()
+	}
+
+	if er.isCertRedeploy(restartNodes) {
+		// the certificates in use are unknown when the nodes can not tell, restart them rather than
+		// risk their expiry
+		notAfter, err := er.esClient.GetCertificatesNotAfter()
+		if err != nil || notAfter.Before(next) {
+			er.L().Info("Restarting the nodes outside of the maintenance windows for expiring certificates",
+				"nodes", restartNodeNames(restartNodes),
+				"notAfter", notAfter,
+				"nextWindow", next)
+			return er.clearMaintenancePending()
+		}
+	}
+
+	message := fmt.Sprintf("Holding the restart of %s until the maintenance window starting at %s",
+		strings.Join(restartNodeNames(restartNodes), ", "), next.Format(time.RFC3339))
+	if err := updateMaintenancePendingCondition(er.cluster, v1.ConditionTrue, message, er.client); err != nil {
+		return err
+	}
+	return ErrOutsideMaintenanceWindow
+}
+
+// clearMaintenancePending removes the MaintenancePending condition once no restart is held
+func (er *ElasticsearchRequest) clearMaintenancePending() error {
+	if !containsClusterCondition(api.MaintenancePending, v1.ConditionTrue, &er.cluster.Status) {
+		return nil
+	}
+	return updateMaintenancePendingCondition(er.cluster, v1.ConditionFalse, "", er.client)
+}
+
+// isCertRedeploy returns true if any of the nodes is restarted for new certificates
+func (er *ElasticsearchRequest) isCertRedeploy(restartNodes []NodeTypeInterface) bool {
+	for _, node := range restartNodes {
+		if er.getNodeState(node).UpgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// logRestartError logs the failure of a restart. The restarts held until a maintenance window
+// are reported by the MaintenancePending condition instead
+func logRestartError(ll logr.Logger, err error, msg string, keysAndValues ...interface{}) {
+	if errors.Is(err, ErrOutsideMaintenanceWindow) {
+		return
+	}
+	ll.Error(err, msg, keysAndValues...)
+}
+
+// maintenanceWindowOpen returns true if one of the windows is open at now. Otherwise it
+// returns the start of the next window
+func maintenanceWindowOpen(windows []api.ElasticsearchMaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	var next time.Time
+	for _, window := range windows {
+		location, startTime, err := parseMaintenanceWindow(window)
+		if err != nil {
+			return false, next, err
+		}
+
+		// a window open at now started at most a week ago
+		local := now.In(location)
+		for offset := -7; offset <= 7; offset++ {
+			day := local.AddDate(0, 0, offset)
+			start := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, location)
+			if !maintenanceWindowStartsOn(window, start.Weekday()) {
+				continue
+			}
+
+			if !start.After(now) && now.Before(start.Add(window.Duration.Duration)) {
+				return true, start, nil
+			}
+			if start.After(now) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+	return false, next, nil
+}
+
+func maintenanceWindowStartsOn(window api.ElasticsearchMaintenanceWindow, weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if maintenanceWindowDays[day] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMaintenanceWindow(window api.ElasticsearchMaintenanceWindow) (*time.Location, time.Time, error) {
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return nil, time.Time{}, kverrors.Wrap(err, "invalid maintenance window time zone",
+			"timeZone", window.TimeZone)
+	}
+	startTime, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return nil, time.Time{}, kverrors.Wrap(err, "invalid maintenance window start time",
+			"startTime", window.StartTime)
+	}
+	for _, day := range window.Days {
+		if _, ok := maintenanceWindowDays[day]; !ok {
+			return nil, time.Time{}, kverrors.New("invalid maintenance window day",
+				"day", day)
+		}
+	}
+	if window.Duration.Duration <= 0 || window.Duration.Duration > maxMaintenanceWindowDuration {
+		return nil, time.Time{}, kverrors.New("maintenance window duration must be positive and at most a week",
+			"duration", window.Duration.Duration)
+	}
+	return location, startTime, nil
+}
+
+// validateMaintenanceWindows returns the reasons the maintenance windows would be ignored
+func validateMaintenanceWindows(windows []api.ElasticsearchMaintenanceWindow) []string {
+	var reasons []string
+	for i, window := range windows {
+		if _, _, err := parseMaintenanceWindow(window); err != nil {
+			reasons = append(reasons, fmt.Sprintf("Maintenance window %d is invalid: %s", i, err.Error()))
+		}
+	}
+	return reasons
+}
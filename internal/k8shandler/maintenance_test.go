@@ -0,0 +1,195 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("maintenance.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	Describe("#maintenanceWindowOpen", func() {
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		windows := []api.ElasticsearchMaintenanceWindow{
+			{
+				Days:      []api.MaintenanceWindowDay{"Saturday"},
+				StartTime: "02:00",
+				Duration:  metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone:  "Europe/Berlin",
+			},
+			{
+				Days:      []api.MaintenanceWindowDay{"Sunday"},
+				StartTime: "22:00",
+				Duration:  metav1.Duration{Duration: 4 * time.Hour},
+				TimeZone:  "Europe/Berlin",
+			},
+		}
+
+		It("should be open within a window", func() {
+			open, _, err := maintenanceWindowOpen(windows, time.Date(2026, 10, 17, 3, 0, 0, 0, berlin))
+			Expect(err).To(BeNil())
+			Expect(open).To(BeTrue())
+		})
+
+		It("should be open within a window starting the day before", func() {
+			open, _, err := maintenanceWindowOpen(windows, time.Date(2026, 10, 19, 1, 30, 0, 0, berlin))
+			Expect(err).To(BeNil())
+			Expect(open).To(BeTrue())
+		})
+
+		It("should return the start of the next window outside of the windows", func() {
+			open, next, err := maintenanceWindowOpen(windows, time.Date(2026, 10, 17, 6, 0, 0, 0, berlin))
+			Expect(err).To(BeNil())
+			Expect(open).To(BeFalse())
+			Expect(next).To(BeTemporally("==", time.Date(2026, 10, 18, 22, 0, 0, 0, berlin)))
+		})
+
+		It("should use UTC without a time zone", func() {
+			open, next, err := maintenanceWindowOpen([]api.ElasticsearchMaintenanceWindow{
+				{StartTime: "23:00", Duration: metav1.Duration{Duration: time.Hour}},
+			}, time.Date(2026, 10, 17, 22, 30, 0, 0, time.UTC))
+			Expect(err).To(BeNil())
+			Expect(open).To(BeFalse())
+			Expect(next).To(BeTemporally("==", time.Date(2026, 10, 17, 23, 0, 0, 0, time.UTC)))
+		})
+	})
+
+	Describe("#validateMaintenanceWindows", func() {
+		It("should reject unknown time zones and empty windows", func() {
+			reasons := validateMaintenanceWindows([]api.ElasticsearchMaintenanceWindow{
+				{StartTime: "02:00", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus"},
+				{StartTime: "02:00"},
+				{StartTime: "02:00", Duration: metav1.Duration{Duration: time.Hour}},
+			})
+			Expect(reasons).To(HaveLen(2))
+			Expect(reasons[0]).To(HavePrefix("Maintenance window 0 is invalid: invalid maintenance window time zone"))
+			Expect(reasons[1]).To(HavePrefix("Maintenance window 1 is invalid: maintenance window duration"))
+		})
+	})
+
+	Describe("#holdForMaintenance", func() {
+		var (
+			cluster   *api.Elasticsearch
+			k8sClient client.Client
+			chatter   *helpers.FakeElasticsearchChatter
+			request   *ElasticsearchRequest
+			key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+			node      = &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1"}}}
+		)
+
+		// windowAt returns a window of an hour starting at the offset from now
+		windowAt := func(offset time.Duration) api.ElasticsearchMaintenanceWindow {
+			return api.ElasticsearchMaintenanceWindow{
+				StartTime: time.Now().UTC().Add(offset).Format("15:04"),
+				Duration:  metav1.Duration{Duration: time.Hour},
+			}
+		}
+
+		certsExpiringIn := func(expiry time.Duration) *helpers.FakeElasticsearchChatter {
+			notAfter := time.Now().Add(expiry).UTC().Format(time.RFC3339)
+			return helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+				"_opendistro/_security/api/ssl/certs": {
+					{
+						StatusCode: 200,
+						Body:       fmt.Sprintf(`{"transport_certificates_list": [{"not_after": %q}]}`, notAfter),
+					},
+				},
+			})
+		}
+
+		expectPending := func(pending bool) {
+			current := &api.Elasticsearch{}
+			Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+			Expect(containsClusterCondition(api.MaintenancePending, v1.ConditionTrue, &current.Status)).To(Equal(pending))
+		}
+
+		BeforeEach(func() {
+			cluster = &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: api.ElasticsearchSpec{
+					MaintenanceWindows: []api.ElasticsearchMaintenanceWindow{windowAt(2 * time.Hour)},
+				},
+				Status: api.ElasticsearchStatus{
+					Nodes: []api.ElasticsearchNodeStatus{
+						{
+							DeploymentName: node.name(),
+							UpgradeStatus:  api.ElasticsearchNodeUpgradeStatus{ScheduledForUpgrade: v1.ConditionTrue},
+						},
+					},
+				},
+			}
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+		})
+
+		JustBeforeEach(func() {
+			k8sClient = fake.NewFakeClient(cluster)
+			request = &ElasticsearchRequest{
+				client:   k8sClient,
+				cluster:  cluster,
+				esClient: helpers.NewFakeElasticsearchClient(key.Name, key.Namespace, k8sClient, chatter),
+			}
+		})
+
+		It("should hold restarts outside of the windows", func() {
+			Expect(request.holdForMaintenance([]NodeTypeInterface{node})).To(MatchError(ErrOutsideMaintenanceWindow))
+			expectPending(true)
+		})
+
+		Context("when a window is open", func() {
+			BeforeEach(func() {
+				cluster.Spec.MaintenanceWindows = append(cluster.Spec.MaintenanceWindows, windowAt(-30*time.Minute))
+				updateESNodeCondition(&cluster.Status, &api.ClusterCondition{Type: api.MaintenancePending, Status: v1.ConditionTrue})
+			})
+
+			It("should start the restart and clear the pending maintenance", func() {
+				Expect(request.holdForMaintenance([]NodeTypeInterface{node})).To(Succeed())
+				expectPending(false)
+			})
+		})
+
+		Context("when the nodes are scheduled for new certificates", func() {
+			BeforeEach(func() {
+				cluster.Status.Nodes[0].UpgradeStatus.ScheduledForCertRedeploy = v1.ConditionTrue
+			})
+
+			Context("which expire before the next window", func() {
+				BeforeEach(func() {
+					chatter = certsExpiringIn(time.Hour)
+				})
+
+				It("should start the restart right away", func() {
+					Expect(request.holdForMaintenance([]NodeTypeInterface{node})).To(Succeed())
+					expectPending(false)
+				})
+			})
+
+			Context("which expire after the next window", func() {
+				BeforeEach(func() {
+					chatter = certsExpiringIn(30 * 24 * time.Hour)
+				})
+
+				It("should hold the restart", func() {
+					Expect(request.holdForMaintenance([]NodeTypeInterface{node})).To(MatchError(ErrOutsideMaintenanceWindow))
+					expectPending(true)
+				})
+			})
+		})
+	})
+})
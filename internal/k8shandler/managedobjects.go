@@ -0,0 +1,167 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+// The states shared by the statuses of the objects written through the REST API of Elasticsearch
+const (
+	managedObjectSynced   = "Synced"
+	managedObjectFailed   = "Failed"
+	managedObjectReserved = "Reserved"
+)
+
+// managedObjectKind is a kind of objects of the spec the operator writes through the REST API
+// of Elasticsearch, e.g. the roles of the security plugin or the index templates
+type managedObjectKind struct {
+	name          string
+	syncedReason  string
+	deletedReason string
+}
+
+// managedObjectStatus is the status of a managed object regardless of its kind, converted from
+// and to the status of the kind in the API
+type managedObjectStatus struct {
+	Name         string
+	State        string
+	Message      string
+	LastSyncTime *metav1.Time
+}
+
+// isOwned returns whether the operator wrote the object. The last sync time is only set once the
+// operator wrote the object, and kept while it fails to write or delete it again
+func (s managedObjectStatus) isOwned() bool {
+	return s.LastSyncTime != nil && s.State != managedObjectReserved
+}
+
+// ownsManagedObject returns whether the previous status records the object as written by the
+// operator. The objects of the same name written by someone else are neither overwritten nor
+// deleted
+func ownsManagedObject(name string, previous []managedObjectStatus) bool {
+	for _, prev := range previous {
+		if prev.Name == name {
+			return prev.isOwned()
+		}
+	}
+	return false
+}
+
+// newManagedObjectNotOwnedError returns the error of an object of the spec with the name of an
+// object in the cluster the operator did not write
+func newManagedObjectNotOwnedError(kind managedObjectKind, name string) error {
+	return kverrors.New(fmt.Sprintf("the %s exists and was not written by the operator, delete it or rename the one of the spec", kind.name),
+		"name", name)
+}
+
+// syncManagedObjects syncs the named objects of the spec in order and returns their status.
+// The sync function returns whether it wrote the object and whether the object is reserved
+func (er *ElasticsearchRequest) syncManagedObjects(kind managedObjectKind, names []string, previous []managedObjectStatus, sync func(i int) (bool, bool, error)) []managedObjectStatus {
+	statuses := []managedObjectStatus{}
+	seen := sets.NewString()
+
+	for i, name := range names {
+		// duplicates are only reported by the validating webhook, which is optional, so they
+		// are skipped here and the first one wins
+		if seen.Has(name) {
+			continue
+		}
+		seen.Insert(name)
+
+		status := managedObjectStatus{Name: name, State: managedObjectSynced}
+		for _, prev := range previous {
+			if prev.Name == name {
+				status.LastSyncTime = prev.LastSyncTime
+				break
+			}
+		}
+
+		written, reserved, err := sync(i)
+		switch {
+		case err != nil:
+			er.L().Error(err, "Unable to sync the object", "kind", kind.name, "name", name)
+			status.State = managedObjectFailed
+			status.Message = fmt.Sprintf("Unable to write the %s: %s", kind.name, err)
+		case reserved:
+			status.State = managedObjectReserved
+			status.Message = fmt.Sprintf("The %s is reserved by the security plugin and left as is", kind.name)
+			status.LastSyncTime = nil
+		case written:
+			now := metav1.Now()
+			status.LastSyncTime = &now
+			er.recordEvent(v1.EventTypeNormal, kind.syncedReason, "Wrote the %s %s to match the spec", kind.name, name)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// deleteManagedObjects deletes the objects of the previous status removed from the spec which
+// the operator wrote. It returns the status of the ones which could not be deleted, to retry
+// them later
+func (er *ElasticsearchRequest) deleteManagedObjects(kind managedObjectKind, names []string, previous []managedObjectStatus, remove func(name string) error) []managedObjectStatus {
+	statuses := []managedObjectStatus{}
+	desired := sets.NewString(names...)
+
+	for _, prev := range previous {
+		// the reserved objects and the ones which failed to be written belong to someone else,
+		// only their status is dropped
+		if desired.Has(prev.Name) || !prev.isOwned() {
+			continue
+		}
+
+		if err := remove(prev.Name); err != nil {
+			er.L().Error(err, "Unable to delete the object", "kind", kind.name, "name", prev.Name)
+			statuses = append(statuses, managedObjectStatus{
+				Name:         prev.Name,
+				State:        managedObjectFailed,
+				Message:      fmt.Sprintf("Unable to delete the %s removed from the spec: %s", kind.name, err),
+				LastSyncTime: prev.LastSyncTime,
+			})
+			continue
+		}
+		er.recordEvent(v1.EventTypeNormal, kind.deletedReason, "Deleted the %s %s removed from the spec", kind.name, prev.Name)
+	}
+
+	return statuses
+}
+
+// updateStatusWithRetry applies the update to the status of the cluster, which returns whether
+// it changed the status, and writes it on conflicts
+func (er *ElasticsearchRequest) updateStatusWithRetry(field string, update func(status *api.ElasticsearchStatus) bool) error {
+	cluster := er.cluster
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		current := &api.Elasticsearch{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, current); err != nil {
+			return err
+		}
+
+		if changed := update(&current.Status); !changed {
+			return nil
+		}
+		return er.client.Status().Update(context.TODO(), current)
+	})
+
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update status for cluster",
+			"cluster", cluster.Name,
+			"status", field,
+			"retries", nretries)
+	}
+	update(&cluster.Status)
+	return nil
+}
@@ -0,0 +1,132 @@
+package k8shandler
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+// newManagedObjectsCluster returns the cluster of the tests of the objects written through the
+// REST API of Elasticsearch
+func newManagedObjectsCluster(spec api.ElasticsearchSpec) *api.Elasticsearch {
+	return &api.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Spec:       spec,
+	}
+}
+
+// newManagedObjectsRequest returns the request of the cluster with a ready data node, for the
+// REST API of Elasticsearch to be reached through the chatter
+func newManagedObjectsRequest(cluster *api.Elasticsearch, chatter *helpers.FakeElasticsearchChatter) *ElasticsearchRequest {
+	readyPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch-cd-abc-1-xyz",
+			Namespace: cluster.Namespace,
+			Labels:    newLabels(cluster.Name, "elasticsearch-cd-abc-1", map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}),
+		},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "elasticsearch", Ready: true}},
+		},
+	}
+	k8sClient := fake.NewFakeClient(cluster, readyPod)
+	return &ElasticsearchRequest{
+		client:   k8sClient,
+		cluster:  cluster,
+		esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+	}
+}
+
+// getClusterStatus returns the status of the cluster of the request as written to the API server
+func getClusterStatus(request *ElasticsearchRequest) api.ElasticsearchStatus {
+	current := &api.Elasticsearch{}
+	key := types.NamespacedName{Name: request.cluster.Name, Namespace: request.cluster.Namespace}
+	Expect(request.client.Get(context.TODO(), key, current)).To(Succeed())
+	return current.Status
+}
+
+var _ = Describe("managedobjects.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	const templateURI = "_template/team-a"
+
+	var (
+		chatter *helpers.FakeElasticsearchChatter
+		request *ElasticsearchRequest
+	)
+
+	BeforeEach(func() {
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			templateURI: {
+				{StatusCode: 500, Body: `{"error": "unavailable"}`},
+			},
+		})
+		request = newManagedObjectsRequest(newManagedObjectsCluster(api.ElasticsearchSpec{}), chatter)
+	})
+
+	Describe("#syncManagedObjects", func() {
+		It("should sync the first of the objects defined more than once", func() {
+			synced := []int{}
+			statuses := request.syncManagedObjects(indexTemplateKind, []string{"team-a", "team-a"}, nil, func(i int) (bool, bool, error) {
+				synced = append(synced, i)
+				return true, false, nil
+			})
+
+			Expect(synced).To(Equal([]int{0}))
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0].State).To(Equal(managedObjectSynced))
+			Expect(statuses[0].LastSyncTime).ToNot(BeNil())
+		})
+
+		It("should keep the last sync time of the objects left as is", func() {
+			lastSync := metav1.Now()
+			previous := []managedObjectStatus{{Name: "team-a", State: managedObjectSynced, LastSyncTime: &lastSync}}
+
+			statuses := request.syncManagedObjects(indexTemplateKind, []string{"team-a"}, previous, func(i int) (bool, bool, error) {
+				return false, false, nil
+			})
+
+			Expect(statuses).To(ConsistOf(managedObjectStatus{Name: "team-a", State: managedObjectSynced, LastSyncTime: &lastSync}))
+		})
+	})
+
+	Describe("#deleteManagedObjects", func() {
+		It("should keep the objects which could not be deleted as failed", func() {
+			lastSync := metav1.Now()
+			previous := []managedObjectStatus{{Name: "team-a", State: managedObjectSynced, LastSyncTime: &lastSync}}
+
+			statuses := request.deleteManagedObjects(indexTemplateKind, nil, previous, request.esClient.DeleteIndexTemplate)
+
+			req, found := chatter.GetRequest(templateURI)
+			Expect(found).To(BeTrue())
+			Expect(req.Method).To(Equal(http.MethodDelete))
+			Expect(statuses).To(HaveLen(1))
+			Expect(statuses[0].State).To(Equal(managedObjectFailed))
+			Expect(statuses[0].Message).To(HavePrefix("Unable to delete the index template removed from the spec"))
+			Expect(statuses[0].LastSyncTime).To(Equal(&lastSync))
+		})
+
+		It("should leave the objects the operator did not write as is", func() {
+			previous := []managedObjectStatus{
+				{Name: "team-a", State: managedObjectReserved},
+				{Name: "team-a", State: managedObjectFailed, Message: "Unable to write the index template"},
+			}
+
+			Expect(request.deleteManagedObjects(indexTemplateKind, nil, previous, request.esClient.DeleteIndexTemplate)).To(BeEmpty())
+			Expect(chatter.Requests).ToNot(HaveKey(templateURI))
+		})
+	})
+})
@@ -0,0 +1,152 @@
+package k8shandler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+const (
+	nodeAttributeEnvVarPrefix = "NODE_ATTR_"
+	// unsetNodeAttributeValue is the value of the attributes used by other nodes
+	unsetNodeAttributeValue = "none"
+)
+
+var nodeAttributeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// attributes rendered by the operator
+var reservedNodeAttributes = []string{"zone"}
+
+type nodeAttribute struct {
+	Name   string
+	EnvVar string
+}
+
+// nodeAttributeNames returns the sorted names of the custom attributes of all nodes
+func nodeAttributeNames(nodes []api.ElasticsearchNode) []string {
+	unique := map[string]bool{}
+	for _, node := range nodes {
+		for name := range node.Attributes {
+			unique[name] = true
+		}
+	}
+
+	names := []string{}
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func nodeAttributeEnvVar(name string) string {
+	return nodeAttributeEnvVarPrefix + strings.ToUpper(name)
+}
+
+func newNodeAttributes(names []string) []nodeAttribute {
+	attributes := make([]nodeAttribute, len(names))
+	for i, name := range names {
+		attributes[i] = nodeAttribute{Name: name, EnvVar: nodeAttributeEnvVar(name)}
+	}
+	return attributes
+}
+
+// newNodeAttributeEnvVars sets every attribute rendered into elasticsearch.yml for a node
+func newNodeAttributeEnvVars(names []string, attributes map[string]string) []v1.EnvVar {
+	envVars := []v1.EnvVar{}
+	for _, name := range names {
+		value, ok := attributes[name]
+		if !ok {
+			value = unsetNodeAttributeValue
+		}
+		envVars = append(envVars, v1.EnvVar{Name: nodeAttributeEnvVar(name), Value: value})
+	}
+	return envVars
+}
+
+// validateNodeAttributes returns the reasons why the node attributes can not be rendered,
+// or why the indices of an index management policy could not be allocated
+func validateNodeAttributes(cluster *api.Elasticsearch) []string {
+	var reasons []string
+	for _, node := range cluster.Spec.Nodes {
+		for _, name := range sortedAttributeNames(node.Attributes) {
+			value := node.Attributes[name]
+			switch {
+			case !nodeAttributeNameRegex.MatchString(name):
+				reasons = append(reasons, fmt.Sprintf("Node attribute name '%s' must consist of lower case letters, digits and '_' and start with a letter", name))
+			case isReservedNodeAttribute(name):
+				reasons = append(reasons, fmt.Sprintf("Node attribute name '%s' is reserved", name))
+			case value == "" || strings.TrimSpace(value) != value:
+				reasons = append(reasons, fmt.Sprintf("Node attribute '%s' must have a value without leading or trailing whitespace", name))
+			}
+		}
+	}
+
+	if cluster.Spec.IndexManagement == nil {
+		return reasons
+	}
+	for _, policy := range cluster.Spec.IndexManagement.Policies {
+		phases := policy.Phases
+		allocations := map[api.IndexManagementPhaseName]*api.IndexManagementAllocationActionSpec{}
+		if phases.Hot != nil {
+			allocations[api.IndexManagementPhaseHot] = phases.Hot.Actions.Allocation
+		}
+		if phases.Warm != nil {
+			allocations[api.IndexManagementPhaseWarm] = phases.Warm.Actions.Allocation
+		}
+		if phases.Cold != nil {
+			allocations[api.IndexManagementPhaseCold] = phases.Cold.Actions.Allocation
+		}
+
+		for _, phase := range []api.IndexManagementPhaseName{api.IndexManagementPhaseHot, api.IndexManagementPhaseWarm, api.IndexManagementPhaseCold} {
+			allocation := allocations[phase]
+			if allocation == nil || hasDataNodeWithAttributes(cluster.Spec.Nodes, allocation.Require) {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("IndexManagement policy '%s': no data nodes have the attributes %v required by the %s phase", policy.Name, allocation.Require, phase))
+		}
+	}
+	return reasons
+}
+
+func hasDataNodeWithAttributes(nodes []api.ElasticsearchNode, required map[string]string) bool {
+	for _, node := range nodes {
+		if !isDataNode(node) {
+			continue
+		}
+		matches := true
+		for name, value := range required {
+			if node.Attributes[name] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func isReservedNodeAttribute(name string) bool {
+	for _, reserved := range reservedNodeAttributes {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedAttributeNames(attributes map[string]string) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
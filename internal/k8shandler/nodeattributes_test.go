@@ -0,0 +1,91 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("nodeattributes.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		hotNode = api.ElasticsearchNode{
+			Roles:      []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+			Attributes: map[string]string{"box_type": "hot"},
+		}
+		warmNode = api.ElasticsearchNode{
+			Roles:      []api.ElasticsearchNodeRole{api.ElasticsearchRoleData},
+			Attributes: map[string]string{"box_type": "warm", "rack": "r1"},
+		}
+		masterNode = api.ElasticsearchNode{
+			Roles:      []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster},
+			Attributes: map[string]string{"box_type": "cold"},
+		}
+	)
+
+	Describe("#nodeAttributeNames", func() {
+		It("should return the sorted names of the attributes of all nodes", func() {
+			Expect(nodeAttributeNames([]api.ElasticsearchNode{warmNode, hotNode})).To(Equal([]string{"box_type", "rack"}))
+		})
+	})
+
+	Describe("#newNodeAttributeEnvVars", func() {
+		It("should default the attributes a node does not define", func() {
+			Expect(newNodeAttributeEnvVars([]string{"box_type", "rack"}, hotNode.Attributes)).To(Equal([]v1.EnvVar{
+				{Name: "NODE_ATTR_BOX_TYPE", Value: "hot"},
+				{Name: "NODE_ATTR_RACK", Value: "none"},
+			}))
+		})
+	})
+
+	Describe("#validateNodeAttributes", func() {
+		var cluster *api.Elasticsearch
+
+		BeforeEach(func() {
+			cluster = &api.Elasticsearch{
+				Spec: api.ElasticsearchSpec{
+					Nodes: []api.ElasticsearchNode{hotNode, warmNode, masterNode},
+				},
+			}
+		})
+
+		It("should accept valid attributes", func() {
+			Expect(validateNodeAttributes(cluster)).To(BeEmpty())
+		})
+		It("should reject invalid and reserved attribute names", func() {
+			cluster.Spec.Nodes[0].Attributes = map[string]string{"Box-Type": "hot", "zone": "a"}
+			Expect(validateNodeAttributes(cluster)).To(Equal([]string{
+				"Node attribute name 'Box-Type' must consist of lower case letters, digits and '_' and start with a letter",
+				"Node attribute name 'zone' is reserved",
+			}))
+		})
+		It("should reject allocations no data node can satisfy", func() {
+			cluster.Spec.IndexManagement = &api.IndexManagementSpec{
+				Policies: []api.IndexManagementPolicySpec{
+					{
+						Name: "infra-policy",
+						Phases: api.IndexManagementPhasesSpec{
+							Hot: &api.IndexManagementHotPhaseSpec{
+								Actions: api.IndexManagementActionsSpec{
+									Allocation: &api.IndexManagementAllocationActionSpec{Require: map[string]string{"box_type": "hot"}},
+								},
+							},
+							Cold: &api.IndexManagementColdPhaseSpec{
+								Actions: api.IndexManagementPhaseActionsSpec{
+									Allocation: &api.IndexManagementAllocationActionSpec{Require: map[string]string{"box_type": "cold"}},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(validateNodeAttributes(cluster)).To(Equal([]string{
+				"IndexManagement policy 'infra-policy': no data nodes have the attributes map[box_type:cold] required by the cold phase",
+			}))
+		})
+	})
+})
@@ -20,6 +20,7 @@ type NodeTypeInterface interface {
 	name() string
 	delete() error
 	getSecretHash() string
+	getCAHash() string
 
 	refreshHashes()
 	scaleDown() error
@@ -72,6 +73,10 @@ func getNodeSuffix(uuid string, roleMap map[api.ElasticsearchNodeRole]bool) stri
 		suffix = fmt.Sprintf("%s%s", suffix, "m")
 	}
 
+	if roleMap[api.ElasticsearchRoleIngest] {
+		suffix = fmt.Sprintf("%s%s", suffix, "i")
+	}
+
 	return fmt.Sprintf("%s-%s", suffix, uuid)
 }
 
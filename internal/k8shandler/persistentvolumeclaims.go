@@ -2,12 +2,16 @@ package k8shandler
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/ViaQ/logerr/kverrors"
 	"github.com/ViaQ/logerr/log"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +19,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// createOrUpdateNodePersistentVolumeClaim creates the claim of the node storage or expands it
+// to the size of the spec. Nodes with ephemeral storage have no claim
+func createOrUpdateNodePersistentVolumeClaim(claimSpec *v1.PersistentVolumeClaimSpec, nodeName, namespace, clusterName string, client client.Client) {
+	if claimSpec == nil {
+		return
+	}
+
+	claimName := fmt.Sprintf("%s-%s", clusterName, nodeName)
+	if err := createOrUpdatePersistentVolumeClaim(*claimSpec, claimName, namespace, clusterName, client); err != nil {
+		log.Error(err, "Unable to create PersistentVolumeClaim")
+	}
+}
+
 func createOrUpdatePersistentVolumeClaim(pvc v1.PersistentVolumeClaimSpec, newName, namespace, clusterName string, client client.Client) error {
 	// for some reason if the PVC already exists but creating it again would violate
 	// quota we get an error regarding quota not that it already exists
@@ -59,9 +76,23 @@ func updatePersistentVolumeClaim(claim *v1.PersistentVolumeClaim, client client.
 			)
 		}
 
+		changed := false
 		if !reflect.DeepEqual(current.ObjectMeta.Labels, claim.ObjectMeta.Labels) {
 			current.ObjectMeta.Labels = claim.ObjectMeta.Labels
+			changed = true
+		}
 
+		desiredSize := claim.Spec.Resources.Requests.Storage()
+		if isStorageExpansion(current, desiredSize) && isVolumeExpansionAllowed(current.Spec.StorageClassName, client) {
+			log.Info("Expanding PVC", "claim", claim.Name, "size", desiredSize.String())
+			if current.Spec.Resources.Requests == nil {
+				current.Spec.Resources.Requests = v1.ResourceList{}
+			}
+			current.Spec.Resources.Requests[v1.ResourceStorage] = *desiredSize
+			changed = true
+		}
+
+		if changed {
 			if err := client.Update(context.TODO(), current); err != nil {
 				return err
 			}
@@ -76,6 +107,22 @@ func updatePersistentVolumeClaim(claim *v1.PersistentVolumeClaim, client client.
 	return nil
 }
 
+// deletePersistentVolumeClaim releases the claim of a removed node
+func deletePersistentVolumeClaim(pvcName, namespace string, client client.Client) error {
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: namespace,
+		},
+	}
+	if err := client.Delete(context.TODO(), claim); err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete PVC",
+			"claim", pvcName,
+		)
+	}
+	return nil
+}
+
 func createPersistentVolumeClaim(pvcName, namespace, clusterName string, volSpec v1.PersistentVolumeClaimSpec) *v1.PersistentVolumeClaim {
 	pvc := persistentVolumeClaim(pvcName, namespace, clusterName)
 	pvc.Spec = volSpec
@@ -99,3 +146,95 @@ func persistentVolumeClaim(pvcName, namespace, clusterName string) *v1.Persisten
 		},
 	}
 }
+
+// volumeResizeGracePeriod is how long a volume may wait to finish expanding before its
+// node is restarted. Drivers supporting online expansion finish without a restart
+const volumeResizeGracePeriod = 5 * time.Minute
+
+// isStorageExpansion returns true if the desired size is larger than the requested size
+// of the claim
+func isStorageExpansion(claim *v1.PersistentVolumeClaim, desiredSize *resource.Quantity) bool {
+	if desiredSize == nil || desiredSize.IsZero() {
+		return false
+	}
+	return desiredSize.Cmp(*claim.Spec.Resources.Requests.Storage()) > 0
+}
+
+// isVolumeExpansionAllowed returns true if the storage class allows expanding its volumes
+func isVolumeExpansionAllowed(storageClassName *string, client client.Client) bool {
+	if storageClassName == nil || *storageClassName == "" {
+		return false
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: *storageClassName}, storageClass); err != nil {
+		log.Error(err, "Could not get StorageClass", "storageClass", *storageClassName)
+		return false
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion
+}
+
+// isPersistentVolumeClaimResizing returns true until the capacity of the claim's volume
+// matches its requested size
+func isPersistentVolumeClaimResizing(claim *v1.PersistentVolumeClaim) bool {
+	if claim.Status.Phase != v1.ClaimBound {
+		return false
+	}
+	capacity, ok := claim.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		return false
+	}
+	return claim.Spec.Resources.Requests.Storage().Cmp(capacity) > 0
+}
+
+// resizeStartTime returns when the claim started waiting for its volume or filesystem
+// to be resized, or nil if it is not waiting
+func resizeStartTime(claim *v1.PersistentVolumeClaim) *metav1.Time {
+	for _, condition := range claim.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		if condition.Type == v1.PersistentVolumeClaimResizing || condition.Type == v1.PersistentVolumeClaimFileSystemResizePending {
+			return &condition.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// getVolumeResizeRestartNodes returns the nodes with a volume that did not finish
+// expanding within the grace period while their pod was running. These nodes are
+// restarted so the volume can be expanded while it is detached or remounted
+func (er *ElasticsearchRequest) getVolumeResizeRestartNodes() []NodeTypeInterface {
+	cluster := er.cluster
+	restartNodes := []NodeTypeInterface{}
+
+	for _, node := range nodes[nodeMapKey(cluster.Name, cluster.Namespace)] {
+		claim := &v1.PersistentVolumeClaim{}
+		claimName := fmt.Sprintf("%s-%s", cluster.Name, node.name())
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: cluster.Namespace}, claim); err != nil {
+			continue
+		}
+
+		since := resizeStartTime(claim)
+		if since == nil || time.Since(since.Time) < volumeResizeGracePeriod {
+			continue
+		}
+
+		pods, err := GetPodList(cluster.Namespace, map[string]string{
+			"cluster-name": cluster.Name,
+			"node-name":    node.name(),
+		}, er.client)
+		if err != nil {
+			er.L().Error(err, "Unable to get the pods of node", "node", node.name())
+			continue
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.StartTime != nil && pod.Status.StartTime.Before(since) {
+				restartNodes = append(restartNodes, node)
+				break
+			}
+		}
+	}
+
+	return restartNodes
+}
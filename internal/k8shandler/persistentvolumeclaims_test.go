@@ -0,0 +1,115 @@
+package k8shandler
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("persistentvolumeclaims.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		k8sClient  client.Client
+		claimKey   = types.NamespacedName{Name: "elasticsearch-elasticsearch-cdm-1", Namespace: "openshift-logging"}
+		expandable = "expandable"
+		fixed      = "fixed"
+	)
+
+	newClaimSpec := func(className, size string) v1.PersistentVolumeClaimSpec {
+		return v1.PersistentVolumeClaimSpec{
+			StorageClassName: &className,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(size)},
+			},
+		}
+	}
+
+	newStorageClass := func(name string, allowExpansion bool) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			AllowVolumeExpansion: &allowExpansion,
+		}
+	}
+
+	getClaimSize := func() resource.Quantity {
+		claim := &v1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(context.TODO(), claimKey, claim)).To(Succeed())
+		return claim.Spec.Resources.Requests[v1.ResourceStorage]
+	}
+
+	Describe("#createOrUpdatePersistentVolumeClaim", func() {
+		BeforeEach(func() {
+			k8sClient = fake.NewFakeClient(newStorageClass(expandable, true), newStorageClass(fixed, false))
+		})
+
+		It("should expand the claim when the storage class allows it", func() {
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(expandable, "10Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(expandable, "20Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+
+			size := getClaimSize()
+			Expect(size.String()).To(Equal("20Gi"))
+		})
+		It("should not expand the claim when the storage class does not allow it", func() {
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(fixed, "10Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(fixed, "20Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+
+			size := getClaimSize()
+			Expect(size.String()).To(Equal("10Gi"))
+		})
+		It("should not shrink the claim", func() {
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(expandable, "20Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+			Expect(createOrUpdatePersistentVolumeClaim(newClaimSpec(expandable, "10Gi"), claimKey.Name, claimKey.Namespace, "elasticsearch", k8sClient)).To(Succeed())
+
+			size := getClaimSize()
+			Expect(size.String()).To(Equal("20Gi"))
+		})
+	})
+
+	Describe("#isPersistentVolumeClaimResizing", func() {
+		It("should be resizing until the capacity matches the request", func() {
+			claim := &v1.PersistentVolumeClaim{
+				Spec: newClaimSpec(expandable, "20Gi"),
+				Status: v1.PersistentVolumeClaimStatus{
+					Phase:    v1.ClaimBound,
+					Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			}
+			Expect(isPersistentVolumeClaimResizing(claim)).To(BeTrue())
+
+			claim.Status.Capacity[v1.ResourceStorage] = resource.MustParse("20Gi")
+			Expect(isPersistentVolumeClaimResizing(claim)).To(BeFalse())
+		})
+	})
+
+	Describe("#resizeStartTime", func() {
+		It("should return when the claim started waiting for the filesystem resize", func() {
+			since := metav1.NewTime(time.Now().Add(-time.Hour))
+			claim := &v1.PersistentVolumeClaim{
+				Status: v1.PersistentVolumeClaimStatus{
+					Conditions: []v1.PersistentVolumeClaimCondition{
+						{
+							Type:               v1.PersistentVolumeClaimFileSystemResizePending,
+							Status:             v1.ConditionTrue,
+							LastTransitionTime: since,
+						},
+					},
+				},
+			}
+			Expect(resizeStartTime(claim)).To(Equal(&since))
+		})
+		It("should return nil when the claim is not resizing", func() {
+			Expect(resizeStartTime(&v1.PersistentVolumeClaim{})).To(BeNil())
+		})
+	})
+})
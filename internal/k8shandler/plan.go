@@ -0,0 +1,419 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/utils/comparators"
+)
+
+// planAnnotation puts the reconcile of a cluster into plan mode when set to true. The operator
+// then only records the changes it would make in the status of the cluster
+const planAnnotation = "elasticsearch.openshift.io/plan"
+
+var planImpactRanks = map[api.ElasticsearchPlanImpact]int{
+	api.PlanImpactNone:               0,
+	api.PlanImpactNoRestart:          1,
+	api.PlanImpactRollingRestart:     2,
+	api.PlanImpactFullClusterRestart: 3,
+}
+
+// isPlanMode returns true if the cluster is annotated for plan mode
+func isPlanMode(cluster *api.Elasticsearch) bool {
+	value, ok := cluster.GetAnnotations()[planAnnotation]
+	if !ok {
+		return false
+	}
+	planMode, _ := strconv.ParseBool(value)
+	return planMode
+}
+
+// plan collects the changes of a plan with their impact
+type plan struct {
+	status *api.ElasticsearchPlanStatus
+}
+
+func (p *plan) add(action api.ElasticsearchPlanAction, impact api.ElasticsearchPlanImpact, node, format string, args ...interface{}) {
+	p.status.Changes = append(p.status.Changes, api.ElasticsearchPlannedChange{
+		Action:      action,
+		Node:        node,
+		Description: fmt.Sprintf(format, args...),
+	})
+	if planImpactRanks[impact] > planImpactRanks[p.status.Impact] {
+		p.status.Impact = impact
+	}
+}
+
+func (p *plan) ignore(format string, args ...interface{}) {
+	p.add(api.PlanActionIgnore, api.PlanImpactNone, "", format, args...)
+}
+
+// Plan computes the changes the reconcile of the cluster would make and records them in the
+// status of the cluster instead of making them
+func (er *ElasticsearchRequest) Plan() error {
+	return er.persistPlan(er.computePlan())
+}
+
+// computePlan compares the spec of the cluster with its resources using the checks of the
+// reconcile. It only reads the resources and the state of Elasticsearch
+func (er *ElasticsearchRequest) computePlan() *api.ElasticsearchPlanStatus {
+	p := &plan{
+		status: &api.ElasticsearchPlanStatus{
+			ObservedGeneration: er.cluster.Generation,
+			Time:               metav1.Now(),
+			Impact:             api.PlanImpactNone,
+		},
+	}
+
+	er.planRestartResume(p)
+	if er.planValidation(p) {
+		er.planConfiguration(p)
+		er.planNodes(p)
+	}
+
+	p.status.Summary = planSummary(p.status)
+	return p.status
+}
+
+// planRestartResume plans the restart recorded in the status, which is resumed first
+func (er *ElasticsearchRequest) planRestartResume(p *plan) {
+	progress := er.cluster.Status.Restart
+	if progress == nil {
+		return
+	}
+
+	impact := api.PlanImpactRollingRestart
+	switch progress.Kind {
+	case api.FullClusterRestart, api.FullClusterCertRestart, api.FullClusterUpdate:
+		impact = api.PlanImpactFullClusterRestart
+	}
+	p.add(api.PlanActionResumeRestart, impact, "", "Resume the %s of %s from step %s",
+		progress.Kind, strings.Join(progress.Nodes, ", "), progress.Step)
+}
+
+// planValidation plans the changes of the spec the operator ignores. It returns false if the
+// whole spec is skipped
+func (er *ElasticsearchRequest) planValidation(p *plan) bool {
+	for _, reason := range ValidateElasticsearch(nil, er.cluster, nil) {
+		p.ignore(reason)
+	}
+
+	valid := isValidMasterCount(er.cluster) && isValidDataCount(er.cluster) && isValidRoles(er.cluster) && isValidRedundancyPolicy(er.cluster)
+
+	validScaleDown, err := er.isValidScaleDownRate()
+	if err != nil {
+		p.ignore("Unable to validate the scale down rate of the data nodes: %s", err.Error())
+		valid = false
+	} else if !validScaleDown {
+		p.ignore("Data node scale down rate is too high based on minimum number of replicas for all indices")
+		valid = false
+	}
+
+	if err := validateUUIDs(er.cluster); err != nil {
+		p.ignore("Previously used GenUUID can not be removed from or changed in Spec.Nodes")
+		valid = false
+	}
+
+	if !valid {
+		p.ignore("Skipping the changes of the spec until it is valid")
+	}
+	return valid
+}
+
+// planConfiguration plans the changes of the configuration of the nodes
+func (er *ElasticsearchRequest) planConfiguration(p *plan) {
+	desired, err := er.newElasticsearchConfigMap()
+	if err != nil {
+		p.ignore("Unable to render the configuration of the nodes: %s", err.Error())
+		return
+	}
+
+	current := &v1.ConfigMap{}
+	err = er.client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	switch {
+	case apierrors.IsNotFound(err):
+		p.add(api.PlanActionUpdateConfiguration, api.PlanImpactNoRestart, "",
+			"Create the configmap %s with the configuration of the nodes", desired.Name)
+	case err != nil:
+		p.ignore("Unable to get the configmap %s: %s", desired.Name, err.Error())
+	case configMapContentChanged(current, desired):
+		p.add(api.PlanActionUpdateConfiguration, api.PlanImpactNoRestart, "",
+			"Update the configmap %s with the configuration of the nodes, which they load on their next restart", desired.Name)
+	}
+}
+
+// planNodes plans the changes of the nodes in the order of the reconcile: certificate redeploys,
+// updates, volume expansions, then creations. The removed nodes are deleted first
+func (er *ElasticsearchRequest) planNodes(p *plan) {
+	desiredNames := sets.NewString()
+	updates, restarts, resizes := []string{}, []string{}, []string{}
+	creates := []api.ElasticsearchPlannedChange{}
+	scales := []api.ElasticsearchPlannedChange{}
+	storageIgnored := []string{}
+
+	for _, specNode := range er.cluster.Spec.Nodes {
+		if specNode.GenUUID == nil {
+			creates = append(creates, api.ElasticsearchPlannedChange{
+				Description: fmt.Sprintf("Create %d node(s) with the roles %s", specNode.NodeCount, planNodeRoles(specNode)),
+			})
+			continue
+		}
+
+		for _, node := range er.GetNodeTypeInterface(*specNode.GenUUID, specNode) {
+			desiredNames.Insert(node.name())
+			if node.isMissing() {
+				creates = append(creates, api.ElasticsearchPlannedChange{
+					Node:        node.name(),
+					Description: fmt.Sprintf("Create node %s", node.name()),
+				})
+				continue
+			}
+
+			switch n := node.(type) {
+			case *deploymentNode:
+				if n.isChanged() {
+					updates = append(updates, n.name())
+				} else if !n.podSpecMatches() {
+					restarts = append(restarts, n.name())
+				}
+			case *statefulSetNode:
+				if n.isChanged() {
+					updates = append(updates, n.name())
+				}
+				current := &apps.StatefulSet{}
+				key := types.NamespacedName{Name: n.name(), Namespace: n.self.Namespace}
+				if err := er.client.Get(context.TODO(), key, current); err == nil && current.Spec.Replicas != nil && *current.Spec.Replicas != *n.self.Spec.Replicas {
+					scales = append(scales, api.ElasticsearchPlannedChange{
+						Node:        n.name(),
+						Description: fmt.Sprintf("Scale node %s from %d to %d replicas", n.name(), *current.Spec.Replicas, *n.self.Spec.Replicas),
+					})
+				}
+			}
+
+			change := er.getStorageChange(specNode, node.name())
+			if change.structure {
+				storageIgnored = append(storageIgnored, fmt.Sprintf("Changing the storage structure of node %s is not supported", node.name()))
+			}
+			if change.className {
+				storageIgnored = append(storageIgnored, fmt.Sprintf("Changing the storage class name of node %s is not supported", node.name()))
+			}
+			if change.size {
+				storageIgnored = append(storageIgnored, fmt.Sprintf("Shrinking the storage of node %s, or expanding it with a storage class that does not allow volume expansion, is not supported", node.name()))
+			}
+			if change.resizing {
+				resizes = append(resizes, node.name())
+			}
+		}
+	}
+
+	for _, reason := range storageIgnored {
+		p.ignore(reason)
+	}
+
+	er.planRemovedNodes(p, desiredNames)
+	er.planCertificates(p)
+
+	if len(updates) > 0 {
+		if version, full := er.isFullClusterUpdate(); full {
+			p.add(api.PlanActionFullClusterRestart, api.PlanImpactFullClusterRestart, "",
+				"Update the pod templates of %s with a full cluster restart, the lowest version of Elasticsearch in the cluster is %s",
+				strings.Join(updates, ", "), version)
+		} else {
+			for _, name := range updates {
+				p.add(api.PlanActionUpdateNode, api.PlanImpactRollingRestart, name,
+					"Update the pod template of node %s and restart it", name)
+			}
+		}
+	}
+	for _, name := range restarts {
+		p.add(api.PlanActionRestartNode, api.PlanImpactRollingRestart, name,
+			"Restart node %s, its pods do not run its pod template yet", name)
+	}
+	for _, scale := range scales {
+		p.add(api.PlanActionScaleNode, api.PlanImpactNoRestart, scale.Node, scale.Description)
+	}
+	for _, name := range resizes {
+		p.add(api.PlanActionExpandVolume, api.PlanImpactRollingRestart, name,
+			"Expand the persistent volume of node %s and restart the node if the volume needs it to finish expanding", name)
+	}
+	for _, create := range creates {
+		p.add(api.PlanActionCreateNode, api.PlanImpactNoRestart, create.Node, create.Description)
+	}
+}
+
+// planRemovedNodes plans the deletion of the nodes of the cluster removed from the spec
+func (er *ElasticsearchRequest) planRemovedNodes(p *plan, desiredNames sets.String) {
+	selector := map[string]string{
+		"cluster-name": er.cluster.Name,
+		"component":    "elasticsearch",
+	}
+
+	deployments, err := GetDeploymentList(er.cluster.Namespace, selector, er.client)
+	if err != nil {
+		p.ignore("Unable to list the deployments of the nodes: %s", err.Error())
+	} else {
+		for _, deployment := range deployments.Items {
+			if desiredNames.Has(deployment.Name) {
+				continue
+			}
+			if deployment.Labels["es-node-data"] == "true" {
+				p.add(api.PlanActionDeleteNode, api.PlanImpactNoRestart, deployment.Name,
+					"Move the shards off node %s and delete it", deployment.Name)
+				continue
+			}
+			p.add(api.PlanActionDeleteNode, api.PlanImpactNoRestart, deployment.Name, "Delete node %s", deployment.Name)
+		}
+	}
+
+	statefulSets, err := GetStatefulSetList(er.cluster.Namespace, selector, er.client)
+	if err != nil {
+		p.ignore("Unable to list the statefulsets of the nodes: %s", err.Error())
+		return
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if !desiredNames.Has(statefulSet.Name) {
+			p.add(api.PlanActionDeleteNode, api.PlanImpactNoRestart, statefulSet.Name, "Delete node %s", statefulSet.Name)
+		}
+	}
+}
+
+// planCertificates plans the redeploy of the certificates of the nodes running with a previous
+// secret of the cluster
+func (er *ElasticsearchRequest) planCertificates(p *plan) {
+	secretHash := getSecretDataHash(er.cluster.Name, er.cluster.Namespace, er.client)
+	certNodes := []NodeTypeInterface{}
+	for _, node := range nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)] {
+		_, nodeStatus := getNodeStatus(node.name(), &er.cluster.Status)
+		scheduled := nodeStatus.UpgradeStatus.ScheduledForCertRedeploy == v1.ConditionTrue
+		if scheduled || (node.getSecretHash() != "" && node.getSecretHash() != secretHash) {
+			certNodes = append(certNodes, node)
+		}
+	}
+
+	stillRecovering := containsClusterCondition(api.Recovering, v1.ConditionTrue, &er.cluster.Status)
+	if len(certNodes) == 0 && !stillRecovering {
+		return
+	}
+
+	names := restartNodeNames(certNodes)
+	switch {
+	case len(certNodes) > 0 && !stillRecovering && er.canReloadCertificates(certNodes):
+		for _, name := range names {
+			p.add(api.PlanActionReloadCertificates, api.PlanImpactNoRestart, name,
+				"Reload the renewed certificates on node %s without a restart", name)
+		}
+	case len(certNodes) > 0 && !stillRecovering && er.isCARotationInProgress():
+		for _, name := range names {
+			p.add(api.PlanActionRestartNode, api.PlanImpactRollingRestart, name,
+				"Restart node %s for the certificates of the CA rotation", name)
+		}
+	default:
+		p.add(api.PlanActionFullClusterRestart, api.PlanImpactFullClusterRestart, "",
+			"Restart all nodes together for the new certificates of %s", strings.Join(names, ", "))
+	}
+}
+
+// isFullClusterUpdate returns true with the lowest version of Elasticsearch in the cluster if
+// the nodes are updated with a full cluster restart
+func (er *ElasticsearchRequest) isFullClusterUpdate() (string, bool) {
+	version, err := er.esClient.GetLowestClusterVersion()
+	if err != nil {
+		return "", false
+	}
+	return version, comparators.CompareVersions(version, expectedMinVersion) > 0
+}
+
+func planNodeRoles(node api.ElasticsearchNode) string {
+	roles := []string{}
+	for _, role := range node.Roles {
+		roles = append(roles, string(role))
+	}
+	return strings.Join(roles, ", ")
+}
+
+func planSummary(plan *api.ElasticsearchPlanStatus) string {
+	changes, ignored := 0, 0
+	for _, change := range plan.Changes {
+		if change.Action == api.PlanActionIgnore {
+			ignored++
+			continue
+		}
+		changes++
+	}
+
+	var summary string
+	switch plan.Impact {
+	case api.PlanImpactNone:
+		summary = "No changes pending"
+	case api.PlanImpactNoRestart:
+		summary = fmt.Sprintf("%d change(s) pending, none restarts a node", changes)
+	case api.PlanImpactRollingRestart:
+		summary = fmt.Sprintf("%d change(s) pending, restarting the nodes one after another", changes)
+	case api.PlanImpactFullClusterRestart:
+		summary = fmt.Sprintf("%d change(s) pending, restarting all nodes of the cluster together", changes)
+	}
+	if ignored > 0 {
+		summary = fmt.Sprintf("%s. %d part(s) of the spec ignored", summary, ignored)
+	}
+	return summary
+}
+
+// persistPlan records the plan in the status of the cluster. The plan is left as is if only
+// its time changed, to not trigger another reconcile
+func (er *ElasticsearchRequest) persistPlan(plan *api.ElasticsearchPlanStatus) error {
+	cluster := er.cluster
+	if planEqual(cluster.Status.Plan, plan) {
+		return nil
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status.Plan = plan.DeepCopy()
+
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to record the plan of cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+
+	return nil
+}
+
+// clearPlan removes the plan of a cluster no longer in plan mode from its status
+func (er *ElasticsearchRequest) clearPlan() error {
+	if er.cluster.Status.Plan == nil {
+		return nil
+	}
+	return er.persistPlan(nil)
+}
+
+func planEqual(lhs, rhs *api.ElasticsearchPlanStatus) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return lhs.ObservedGeneration == rhs.ObservedGeneration &&
+		lhs.Impact == rhs.Impact &&
+		lhs.Summary == rhs.Summary &&
+		reflect.DeepEqual(lhs.Changes, rhs.Changes)
+}
@@ -0,0 +1,166 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("plan.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		chatter   *helpers.FakeElasticsearchChatter
+		request   *ElasticsearchRequest
+		version   string
+		uuid      = "abc"
+		key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+		nodeKey   = types.NamespacedName{Name: "elasticsearch-cdm-abc-1", Namespace: "openshift-logging"}
+		dataRoles = map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}
+	)
+
+	currentPlan := func() *api.ElasticsearchPlanStatus {
+		current := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+		return current.Status.Plan
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{planAnnotation: "true"},
+			},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy: api.ZeroRedundancy,
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleClient, api.ElasticsearchRoleData, api.ElasticsearchRoleMaster},
+						NodeCount: 1,
+						GenUUID:   &uuid,
+					},
+				},
+			},
+		}
+		version = "6.8.1"
+		nodes = map[string][]NodeTypeInterface{}
+	})
+
+	JustBeforeEach(func() {
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_cluster/stats/nodes/_all": {
+				{StatusCode: 200, Body: `{"nodes": {"versions": ["` + version + `"]}}`},
+			},
+		})
+		k8sClient = fake.NewFakeClient(cluster)
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(key.Name, key.Namespace, k8sClient, chatter),
+		}
+
+		// the node runs a previous image and a removed node is left over
+		node := request.GetNodeTypeInterface(uuid, cluster.Spec.Nodes[0])[0].(*deploymentNode)
+		node.self.Spec.Template.Spec.Containers[0].Image = "elasticsearch:previous"
+		Expect(k8sClient.Create(context.TODO(), &node.self)).To(Succeed())
+
+		removed := newDeploymentNode("elasticsearch-cd-old-1", api.ElasticsearchNode{}, cluster, dataRoles, k8sClient, nil)
+		Expect(k8sClient.Create(context.TODO(), &removed.(*deploymentNode).self)).To(Succeed())
+	})
+
+	It("should record the changes without making them", func() {
+		Expect(request.Plan()).To(Succeed())
+
+		plan := currentPlan()
+		Expect(plan).ToNot(BeNil())
+		Expect(plan.Impact).To(Equal(api.PlanImpactRollingRestart))
+		Expect(plan.Changes).To(ContainElement(bePlannedChange(api.PlanActionUpdateConfiguration, "")))
+		Expect(plan.Changes).To(ContainElement(bePlannedChange(api.PlanActionDeleteNode, "elasticsearch-cd-old-1")))
+		Expect(plan.Changes).To(ContainElement(bePlannedChange(api.PlanActionUpdateNode, nodeKey.Name)))
+
+		deployment := &apps.Deployment{}
+		Expect(k8sClient.Get(context.TODO(), nodeKey, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("elasticsearch:previous"))
+		Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch-cd-old-1", Namespace: key.Namespace}, &apps.Deployment{})).To(Succeed())
+		err := k8sClient.Get(context.TODO(), key, &v1.ConfigMap{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	Context("when the cluster runs Elasticsearch 5", func() {
+		BeforeEach(func() {
+			version = "5.6.16"
+		})
+
+		It("should plan a full cluster restart", func() {
+			Expect(request.Plan()).To(Succeed())
+			plan := currentPlan()
+			Expect(plan.Impact).To(Equal(api.PlanImpactFullClusterRestart))
+			Expect(plan.Changes).To(ContainElement(bePlannedChange(api.PlanActionFullClusterRestart, "")))
+		})
+	})
+
+	Context("when the redundancy policy needs more data nodes", func() {
+		BeforeEach(func() {
+			cluster.Spec.RedundancyPolicy = api.SingleRedundancy
+		})
+
+		It("should plan to skip the changes of the spec", func() {
+			Expect(request.Plan()).To(Succeed())
+			plan := currentPlan()
+			Expect(plan.Impact).To(Equal(api.PlanImpactNone))
+			for _, change := range plan.Changes {
+				Expect(change.Action).To(Equal(api.PlanActionIgnore))
+			}
+			Expect(plan.Summary).To(ContainSubstring("ignored"))
+		})
+	})
+
+	Context("when the nodes have persistent storage", func() {
+		BeforeEach(func() {
+			size := resource.MustParse("10Gi")
+			cluster.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{Size: &size}
+		})
+
+		It("should not create the claims of the nodes", func() {
+			Expect(request.Plan()).To(Succeed())
+
+			claims := &v1.PersistentVolumeClaimList{}
+			Expect(k8sClient.List(context.TODO(), claims, client.InNamespace(key.Namespace))).To(Succeed())
+			Expect(claims.Items).To(BeEmpty())
+		})
+	})
+
+	It("should remove the plan once the cluster leaves plan mode", func() {
+		Expect(request.Plan()).To(Succeed())
+		Expect(currentPlan()).ToNot(BeNil())
+
+		Expect(request.clearPlan()).To(Succeed())
+		Expect(currentPlan()).To(BeNil())
+	})
+})
+
+// bePlannedChange matches a planned change by its action and node
+func bePlannedChange(action api.ElasticsearchPlanAction, node string) OmegaMatcher {
+	return WithTransform(func(change api.ElasticsearchPlannedChange) bool {
+		return change.Action == action && change.Node == node
+	}, BeTrue())
+}
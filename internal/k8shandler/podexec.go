@@ -0,0 +1,55 @@
+package k8shandler
+
+import (
+	"bytes"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExecutor runs the command in the container of the pod and returns its output
+type PodExecutor func(namespace, pod, container string, command []string) (string, error)
+
+// NewPodExecutor returns the executor running the commands through the exec subresource
+// of the pods
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to create the client of the pods")
+	}
+
+	return func(namespace, pod, container string, command []string) (string, error) {
+		req := clientset.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("exec").
+			VersionedParams(&v1.PodExecOptions{
+				Container: container,
+				Command:   command,
+				Stdout:    true,
+				Stderr:    true,
+			}, scheme.ParameterCodec)
+
+		exec, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+		if err != nil {
+			return "", kverrors.Wrap(err, "failed to exec in the pod",
+				"namespace", namespace,
+				"pod", pod)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if err := exec.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+			return stdout.String(), kverrors.Wrap(err, "failed to run the command in the pod",
+				"namespace", namespace,
+				"pod", pod,
+				"container", container,
+				"stderr", stderr.String())
+		}
+		return stdout.String(), nil
+	}, nil
+}
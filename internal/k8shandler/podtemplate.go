@@ -23,6 +23,14 @@ func ArePodSpecDifferent(lhs, rhs v1.PodSpec, strictTolerations bool) bool {
 		changed = true
 	}
 
+	if len(lhs.InitContainers) != len(rhs.InitContainers) {
+		changed = true
+	}
+
+	if !reflect.DeepEqual(lhs.TopologySpreadConstraints, rhs.TopologySpreadConstraints) {
+		changed = true
+	}
+
 	// check nodeselectors
 	if !areSelectorsSame(lhs.NodeSelector, rhs.NodeSelector) {
 		changed = true
@@ -90,10 +98,33 @@ func ArePodSpecDifferent(lhs, rhs v1.PodSpec, strictTolerations bool) bool {
 }
 
 // CreateUpdatablePodTemplateSpec creates a pod template from a copy of the update with
-// some aspects of the current
+// some aspects of the current. The current volumes which are still desired are kept and
+// the volumes added by the update are appended
 func CreateUpdatablePodTemplateSpec(current, desired v1.PodTemplateSpec) v1.PodTemplateSpec {
 	desiredCopy := desired
-	desiredCopy.Spec.Volumes = current.Spec.Volumes
+	desiredCopy.Spec.Volumes = []v1.Volume{}
+
+	for _, currentVolume := range current.Spec.Volumes {
+		for _, volume := range desired.Spec.Volumes {
+			if volume.Name == currentVolume.Name {
+				desiredCopy.Spec.Volumes = append(desiredCopy.Spec.Volumes, currentVolume)
+				break
+			}
+		}
+	}
+
+	for _, volume := range desired.Spec.Volumes {
+		found := false
+		for _, currentVolume := range current.Spec.Volumes {
+			if volume.Name == currentVolume.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			desiredCopy.Spec.Volumes = append(desiredCopy.Spec.Volumes, volume)
+		}
+	}
 
 	return desiredCopy
 }
@@ -757,4 +757,85 @@ var _ = Describe("podtemplate", func() {
 			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeTrue())
 		})
 	})
+
+	Context("different topology spread constraints", func() {
+		JustBeforeEach(func() {
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+						{
+							MaxSkew:           1,
+							TopologyKey:       "topology.kubernetes.io/zone",
+							WhenUnsatisfiable: v1.ScheduleAnyway,
+						},
+					},
+				},
+			}
+		})
+
+		It("should recognize a topology spread constraint change", func() {
+			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeTrue())
+		})
+	})
+
+	Context("different init containers", func() {
+		JustBeforeEach(func() {
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{Name: "init", Image: expectedImageName},
+					},
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+				},
+			}
+		})
+
+		It("should recognize an init container change", func() {
+			Expect(ArePodTemplateSpecDifferent(lhs, rhs)).To(BeTrue())
+		})
+	})
+
+	Context("updatable podtemplate", func() {
+		It("should keep the current volumes and add the new ones", func() {
+			lhs.Spec.Volumes = []v1.Volume{pvcVolume}
+			emptyVolume2 := emptyVolume.DeepCopy()
+			emptyVolume2.Name = "pvcVolume"
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					Volumes: []v1.Volume{
+						*emptyVolume2,
+						secretVolume,
+					},
+				},
+			}
+
+			updatable := CreateUpdatablePodTemplateSpec(lhs, rhs)
+			Expect(updatable.Spec.Volumes).To(Equal([]v1.Volume{pvcVolume, secretVolume}))
+		})
+		It("should remove the current volumes which are no longer desired", func() {
+			lhs.Spec.Volumes = []v1.Volume{pvcVolume, secretVolume}
+			rhs = v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						nodeContainer,
+					},
+					Volumes: []v1.Volume{
+						emptyVolume,
+						pvcVolume,
+					},
+				},
+			}
+
+			updatable := CreateUpdatablePodTemplateSpec(lhs, rhs)
+			Expect(updatable.Spec.Volumes).To(Equal([]v1.Volume{pvcVolume, emptyVolume}))
+		})
+	})
 })
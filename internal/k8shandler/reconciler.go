@@ -13,6 +13,7 @@ import (
 	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -21,6 +22,8 @@ type ElasticsearchRequest struct {
 	client   client.Client
 	cluster  *elasticsearchv1.Elasticsearch
 	esClient elasticsearch.Client
+	recorder record.EventRecorder
+	podExec  PodExecutor
 	ll       logr.Logger
 }
 
@@ -117,16 +120,26 @@ func SecretReconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClien
 	return true, nil
 }
 
-func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client) error {
+func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient client.Client, recorder record.EventRecorder, podExec PodExecutor) error {
 	esClient := elasticsearch.NewClient(requestCluster.Name, requestCluster.Namespace, requestClient)
 
 	elasticsearchRequest := ElasticsearchRequest{
 		client:   requestClient,
 		cluster:  requestCluster,
 		esClient: esClient,
+		recorder: recorder,
+		podExec:  podExec,
 		ll:       log.WithValues("cluster", requestCluster.Name, "namespace", requestCluster.Namespace),
 	}
 
+	// in plan mode the pending changes of the spec are only recorded in the status
+	if isPlanMode(requestCluster) {
+		return elasticsearchRequest.Plan()
+	}
+	if err := elasticsearchRequest.clearPlan(); err != nil {
+		elasticsearchRequest.L().Error(err, "Unable to remove the plan of the cluster")
+	}
+
 	// check if we are doing ES cert management looking for annotation:
 	// logging.openshift.io/elasticsearch-cert-management: true
 	value, ok := requestCluster.Annotations[constants.EOCertManagementLabel]
@@ -134,6 +147,12 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		manageBool, _ := strconv.ParseBool(value)
 		if manageBool {
 			cr := NewCertificateRequest(requestCluster.Name, requestCluster.Namespace, requestCluster.GetOwnerRef(), requestClient)
+			cr.SetCertificatesSpec(requestCluster.Spec.Certificates)
+			if cr.IssuerRef == nil {
+				if err := cr.RotateCA(elasticsearchRequest.isSecretRolledOut()); err != nil {
+					elasticsearchRequest.L().Error(err, "Unable to rotate the CA")
+				}
+			}
 			cr.GenerateElasticsearchCerts(requestCluster.Name)
 
 			// for any components specified like:
@@ -148,6 +167,8 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		}
 	}
 
+	elasticsearchRequest.updateCertificateMetrics()
+
 	degradedCondition := false
 
 	// Ensure existence of servicesaccount
@@ -173,6 +194,11 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		return kverrors.Wrap(err, "Failed to reconcile Dashboards for Elasticsearch cluster")
 	}
 
+	// Ensure the pods waiting for the zone of their node are annotated
+	if err := elasticsearchRequest.AnnotatePodZones(); err != nil {
+		return kverrors.Wrap(err, "Failed to annotate the zones of the Elasticsearch pods")
+	}
+
 	// Ensure Elasticsearch cluster itself is up to spec
 	if err := elasticsearchRequest.CreateOrUpdateElasticsearchCluster(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile Elasticsearch deployment spec")
@@ -209,11 +235,36 @@ func Reconcile(requestCluster *elasticsearchv1.Elasticsearch, requestClient clie
 		degradedCondition = true
 	}
 
+	// Ensure the ingest pipelines before the index templates bound to them
+	if err := elasticsearchRequest.CreateOrUpdateIngestPipelines(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile Ingest pipelines for Elasticsearch cluster")
+	}
+
 	// Ensure index management is in place
 	if err := elasticsearchRequest.CreateOrUpdateIndexManagement(); err != nil {
 		return kverrors.Wrap(err, "Failed to reconcile IndexMangement for Elasticsearch cluster")
 	}
 
+	// Ensure snapshots are scheduled
+	if err := elasticsearchRequest.CreateOrUpdateSnapshots(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile Snapshots for Elasticsearch cluster")
+	}
+
+	// Restore the spec'd snapshot
+	if err := elasticsearchRequest.CreateOrUpdateRestore(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile Restore for Elasticsearch cluster")
+	}
+
+	// Ensure the roles and role mappings of the security plugin
+	if err := elasticsearchRequest.CreateOrUpdateSecurity(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile Security roles for Elasticsearch cluster")
+	}
+
+	// Ensure the index templates of the spec
+	if err := elasticsearchRequest.CreateOrUpdateIndexTemplates(); err != nil {
+		return kverrors.Wrap(err, "Failed to reconcile Index templates for Elasticsearch cluster")
+	}
+
 	if !degradedCondition {
 		if err := elasticsearchRequest.UpdateDegradedCondition(false, "", ""); err != nil {
 			elasticsearchRequest.ll.Error(err, "Unable to remove Degraded condition")
@@ -174,7 +174,7 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 
 		for _, role := range node.Roles {
 			switch role {
-			case loggingv1.ElasticsearchRoleClient:
+			case loggingv1.ElasticsearchRoleClient, loggingv1.ElasticsearchRoleCoordinating:
 				selector["es-node-client"] = "true"
 				break
 			case loggingv1.ElasticsearchRoleData:
@@ -196,7 +196,7 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 			for _, deployment := range deploymentList.Items {
 				clusterName, _, uuid := parseNodeName(deployment.Name)
 
-				if clusterName != er.cluster.Name {
+				if clusterName != er.cluster.Name || !matchesOptionalRoles(deployment.Labels, node) {
 					continue
 				}
 
@@ -211,6 +211,7 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 						knownUUIDs = append(knownUUIDs, uuid)
 
 						er.setUUID(nodeIndex, uuid)
+						er.recordEvent(corev1.EventTypeNormal, eventReasonNodeAdopted, "Adopted the existing resources with UUID %s for node %d of the spec", uuid, nodeIndex)
 						break
 					}
 				}
@@ -226,7 +227,7 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 			for _, statefulSet := range statefulsetList.Items {
 				clusterName, _, uuid := parseNodeName(statefulSet.Name)
 
-				if clusterName != er.cluster.Name {
+				if clusterName != er.cluster.Name || !matchesOptionalRoles(statefulSet.Labels, node) {
 					continue
 				}
 
@@ -241,6 +242,7 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 						knownUUIDs = append(knownUUIDs, uuid)
 
 						er.setUUID(nodeIndex, uuid)
+						er.recordEvent(corev1.EventTypeNormal, eventReasonNodeAdopted, "Adopted the existing resources with UUID %s for node %d of the spec", uuid, nodeIndex)
 						break
 					}
 				}
@@ -251,6 +253,14 @@ func (er *ElasticsearchRequest) recoverFromDeployments(knownUUIDs []string, node
 	return nil
 }
 
+// matchesOptionalRoles returns whether the labels of the resources of a node carry the same
+// ingest and coordinating roles as the node of the spec. The labels are only set when true
+func matchesOptionalRoles(labels map[string]string, node loggingv1.ElasticsearchNode) bool {
+	roleMap := getNodeRoleMap(node)
+	return (labels["es-node-ingest"] == "true") == roleMap[loggingv1.ElasticsearchRoleIngest] &&
+		(labels["es-node-coordinating"] == "true") == roleMap[loggingv1.ElasticsearchRoleCoordinating]
+}
+
 // for PVCs we only need to match on roles and replicas for the sake of naming
 func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatch map[int]loggingv1.ElasticsearchNode) error {
 	selector := map[string]string{
@@ -290,10 +300,11 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 		isClientNode := false
 		isDataNode := false
 		isMasterNode := false
+		isIngestNode := false
 
 		for _, role := range node.Roles {
 			switch role {
-			case loggingv1.ElasticsearchRoleClient:
+			case loggingv1.ElasticsearchRoleClient, loggingv1.ElasticsearchRoleCoordinating:
 				isClientNode = true
 				break
 			case loggingv1.ElasticsearchRoleData:
@@ -301,6 +312,9 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 				break
 			case loggingv1.ElasticsearchRoleMaster:
 				isMasterNode = true
+				break
+			case loggingv1.ElasticsearchRoleIngest:
+				isIngestNode = true
 			}
 		}
 
@@ -330,6 +344,10 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 				continue
 			}
 
+			if isIngestNode != strings.Contains(role, "i") {
+				continue
+			}
+
 			if node.NodeCount != uuidCounts[uuid] {
 				continue
 			}
@@ -339,6 +357,7 @@ func (er *ElasticsearchRequest) recoverFromPVCs(knownUUIDs []string, nodesToMatc
 			knownUUIDs = append(knownUUIDs, uuid)
 
 			er.setUUID(nodeIndex, uuid)
+			er.recordEvent(corev1.EventTypeNormal, eventReasonNodeAdopted, "Adopted the existing resources with UUID %s for node %d of the spec", uuid, nodeIndex)
 		}
 	}
 
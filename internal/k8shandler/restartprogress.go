@@ -0,0 +1,135 @@
+package k8shandler
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// trackRestart persists the progress of the restarter in the status of the cluster. A restart of
+// the same kind and nodes that was interrupted, e.g. by a restart of the operator, continues from
+// its recorded progress
+func (er *ElasticsearchRequest) trackRestart(restarter *Restarter, kind api.ElasticsearchRestartKind) {
+	restarter.kind = kind
+	restarter.persist = er.persistRestartProgress
+	restarter.hold = func() error {
+		return er.holdForMaintenance(restarter.scheduledNodes)
+	}
+
+	progress := er.cluster.Status.Restart
+	if progress == nil || progress.Kind != kind {
+		return
+	}
+
+	if reflect.DeepEqual(progress.Nodes, restartNodeNames(restarter.scheduledNodes)) {
+		restarter.progress = progress.DeepCopy()
+	}
+}
+
+func restartNodeNames(restartNodes []NodeTypeInterface) []string {
+	nodeNames := make([]string, 0, len(restartNodes))
+	for _, node := range restartNodes {
+		nodeNames = append(nodeNames, node.name())
+	}
+	return nodeNames
+}
+
+// persistRestartProgress stores the progress of a restart, along with the conditions and node
+// statuses the restart changed, in the status of the cluster. A nil progress clears it
+func (er *ElasticsearchRequest) persistRestartProgress(progress *api.ElasticsearchRestartStatus) error {
+	cluster := er.cluster
+	cluster.Status.Restart = progress.DeepCopy()
+	status := cluster.Status.DeepCopy()
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, cluster); err != nil {
+			return err
+		}
+
+		cluster.Status = *status
+
+		return er.client.Status().Update(context.TODO(), cluster)
+	})
+
+	if retryErr != nil {
+		// keep the progress in memory for the rest of the reconcile
+		cluster.Status = *status
+		return kverrors.Wrap(retryErr, "failed to persist the restart progress of cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+
+	return nil
+}
+
+// resumeRestart continues the restart recorded in the status of the cluster before starting any
+// other operation. The restart is dropped if none of its nodes exist anymore
+func (er *ElasticsearchRequest) resumeRestart() error {
+	progress := er.cluster.Status.Restart
+	if progress == nil {
+		return nil
+	}
+
+	restartNodes := []NodeTypeInterface{}
+	for _, nodeName := range progress.Nodes {
+		for _, node := range nodes[nodeMapKey(er.cluster.Name, er.cluster.Namespace)] {
+			if node.name() == nodeName {
+				restartNodes = append(restartNodes, node)
+			}
+		}
+	}
+
+	ll := log.WithValues("cluster", er.cluster.Name, "namespace", er.cluster.Namespace,
+		"kind", progress.Kind, "step", progress.Step)
+
+	if len(restartNodes) != len(progress.Nodes) {
+		ll.Info("Dropping the restart of removed nodes", "nodes", progress.Nodes)
+		if err := er.persistRestartProgress(nil); err != nil {
+			return err
+		}
+		er.tryEnsureAllShardAllocation()
+		return nil
+	}
+
+	ll.Info("Resuming the restart of the cluster")
+
+	switch progress.Kind {
+	case api.FullClusterRestart:
+		return er.PerformFullClusterRestart(restartNodes)
+	case api.FullClusterCertRestart:
+		if err := er.PerformFullClusterCertRestart(restartNodes); err != nil {
+			return err
+		}
+		metrics.IncrementRestartCounterCert()
+	case api.FullClusterUpdate:
+		return er.PerformFullClusterUpdate(restartNodes)
+	case api.NodeRestart:
+		if err := er.performNodesRestart(restartNodes); err != nil {
+			return err
+		}
+		metrics.IncrementRestartCounterRolling()
+	case api.NodeCertRestart:
+		if err := er.performNodesCertRestart(restartNodes); err != nil {
+			return err
+		}
+		metrics.IncrementRestartCounterCert()
+	case api.NodeUpdate:
+		if err := er.performNodesUpdate(restartNodes); err != nil {
+			return err
+		}
+		metrics.IncrementRestartCounterRolling()
+	default:
+		ll.Info("Dropping the restart of unknown kind")
+		return er.persistRestartProgress(nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package k8shandler
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var errKilled = kverrors.New("operator killed")
+
+// restartSimulation is the state of a cluster restarted by the fake steps of a restarter. A kill
+// point fails the restart like a restart of the operator would
+type restartSimulation struct {
+	allocation string
+	down       bool
+	restarts   int
+
+	killAt     int
+	killPoints int
+}
+
+func (s *restartSimulation) killPoint() error {
+	s.killPoints++
+	if s.killPoints == s.killAt {
+		return errKilled
+	}
+	return nil
+}
+
+// step runs the action of a step between two kill points, the second one killing the step after
+// it changed the cluster
+func (s *restartSimulation) step(action func() error) func() error {
+	return func() error {
+		if err := s.killPoint(); err != nil {
+			return err
+		}
+		if err := action(); err != nil {
+			return err
+		}
+		return s.killPoint()
+	}
+}
+
+var _ = Describe("restartprogress.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		k8sClient client.Client
+		sim       *restartSimulation
+		key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+		node      = &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-cdm-abc-1"}}}
+	)
+
+	newClient := func() client.Client {
+		return fake.NewFakeClient(&api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status: api.ElasticsearchStatus{
+				Conditions: api.ClusterConditions{},
+				Nodes:      []api.ElasticsearchNodeStatus{{DeploymentName: node.name()}},
+			},
+		})
+	}
+
+	BeforeEach(func() {
+		sim = &restartSimulation{allocation: "all"}
+		k8sClient = newClient()
+	})
+
+	// reconcile reads the cluster like a restarted operator would and runs the restart with the
+	// fake steps
+	reconcile := func(nodeRestart bool) error {
+		cluster := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), key, cluster)).To(Succeed())
+		er := &ElasticsearchRequest{client: k8sClient, cluster: cluster}
+
+		restarter := Restarter{
+			scheduledNodes:   []NodeTypeInterface{node},
+			clusterName:      key.Name,
+			clusterNamespace: key.Namespace,
+			precheck: sim.step(func() error {
+				if sim.down {
+					return kverrors.New("cluster is red")
+				}
+				return nil
+			}),
+			prep: sim.step(func() error {
+				sim.allocation = "primaries"
+				return nil
+			}),
+			main: sim.step(func() error {
+				sim.down = true
+				if err := sim.killPoint(); err != nil {
+					return err
+				}
+				sim.down = false
+				sim.restarts++
+				return nil
+			}),
+			post: sim.step(func() error {
+				if sim.down {
+					return kverrors.New("nodes did not rejoin")
+				}
+				sim.allocation = "all"
+				return nil
+			}),
+			recovery: sim.step(func() error { return nil }),
+		}
+
+		kind := api.FullClusterRestart
+		if nodeRestart {
+			kind = api.NodeRestart
+			restarter.setNodeConditions(func() {
+				Expect(er.setNodeStatus(node, restarter.nodeStatus, &er.cluster.Status)).To(Succeed())
+			})
+			restarter.nodeStatus = er.getNodeState(node)
+		} else {
+			restarter.setClusterConditions(func() {})
+			restarter.clusterStatus = &er.cluster.Status
+		}
+		er.trackRestart(&restarter, kind)
+
+		persist := restarter.persist
+		restarter.persist = func(progress *api.ElasticsearchRestartStatus) error {
+			if err := sim.killPoint(); err != nil {
+				return err
+			}
+			return persist(progress)
+		}
+
+		return restarter.restartCluster()
+	}
+
+	expectRestartedAfterEveryKill := func(nodeRestart bool) {
+		// count the kill points of an uninterrupted restart
+		Expect(reconcile(nodeRestart)).To(Succeed())
+		totalPoints := sim.killPoints
+		Expect(totalPoints).To(BeNumerically(">", 10))
+
+		for killAt := 1; killAt <= totalPoints; killAt++ {
+			sim = &restartSimulation{allocation: "all", killAt: killAt}
+			k8sClient = newClient()
+
+			Expect(reconcile(nodeRestart)).To(MatchError(errKilled), "kill point %d", killAt)
+			Expect(reconcile(nodeRestart)).To(Succeed(), "kill point %d", killAt)
+
+			Expect(sim.down).To(BeFalse(), "kill point %d", killAt)
+			Expect(sim.allocation).To(Equal("all"), "kill point %d", killAt)
+			Expect(sim.restarts).To(BeNumerically(">=", 1), "kill point %d", killAt)
+
+			current := &api.Elasticsearch{}
+			Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+			Expect(current.Status.Restart).To(BeNil(), "kill point %d", killAt)
+			if nodeRestart {
+				Expect(current.Status.Nodes[0].UpgradeStatus.UnderUpgrade).To(BeEmpty(), "kill point %d", killAt)
+				Expect(current.Status.Nodes[0].UpgradeStatus.UpgradePhase).To(Equal(api.ControllerUpdated), "kill point %d", killAt)
+			} else {
+				for _, condition := range []api.ClusterConditionType{api.Restarting, api.Recovering, api.UpdatingESSettings} {
+					Expect(containsClusterCondition(condition, v1.ConditionTrue, &current.Status)).To(BeFalse(), "kill point %d", killAt)
+				}
+			}
+		}
+	}
+
+	It("should resume a full cluster restart killed at any step", func() {
+		expectRestartedAfterEveryKill(false)
+	})
+
+	It("should resume a node restart killed at any step", func() {
+		expectRestartedAfterEveryKill(true)
+	})
+
+	It("should record the step a restart was killed in", func() {
+		// killed while the nodes are down
+		sim.killAt = 10
+
+		Expect(reconcile(false)).To(MatchError(errKilled))
+
+		current := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+		Expect(current.Status.Restart).ToNot(BeNil())
+		Expect(current.Status.Restart.Kind).To(Equal(api.FullClusterRestart))
+		Expect(current.Status.Restart.Nodes).To(ConsistOf(node.name()))
+		Expect(current.Status.Restart.Step).To(Equal(api.RestartStepMain))
+		Expect(current.Status.Restart.CompletedStep).To(Equal(api.RestartStepPrep))
+	})
+
+	It("should start over a restart of other nodes", func() {
+		cluster := &api.Elasticsearch{}
+		Expect(k8sClient.Get(context.TODO(), key, cluster)).To(Succeed())
+		cluster.Status.Restart = &api.ElasticsearchRestartStatus{
+			Kind:  api.FullClusterRestart,
+			Nodes: []string{"elasticsearch-cdm-abc-2"},
+			Step:  api.RestartStepPost,
+		}
+		er := &ElasticsearchRequest{client: k8sClient, cluster: cluster}
+
+		restarter := Restarter{scheduledNodes: []NodeTypeInterface{node}}
+		er.trackRestart(&restarter, api.FullClusterRestart)
+		Expect(restarter.progress).To(BeNil())
+
+		restarter = Restarter{scheduledNodes: []NodeTypeInterface{node}}
+		cluster.Status.Restart.Nodes = []string{node.name()}
+		er.trackRestart(&restarter, api.FullClusterRestart)
+		Expect(restarter.progress).To(Equal(cluster.Status.Restart))
+	})
+})
@@ -0,0 +1,404 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	shardRecoveryTypeSnapshot = "SNAPSHOT"
+	shardRecoveryStageDone    = "DONE"
+)
+
+// CreateOrUpdateRestore restores the spec'd snapshot once the cluster is green and follows
+// the recovery of the restored indices. A snapshot is only restored once
+func (er *ElasticsearchRequest) CreateOrUpdateRestore() error {
+	cluster := er.cluster
+	spec := cluster.Spec.Restore
+	if spec == nil {
+		return er.updateRestoreStatus(cluster.Status.Restore, false)
+	}
+
+	repository := restoreRepository(cluster)
+	status := cluster.Status.Restore
+	if status == nil || status.Repository != repository || status.Snapshot != spec.Snapshot {
+		status = &api.ElasticsearchRestoreStatus{
+			Repository: repository,
+			Snapshot:   spec.Snapshot,
+			State:      api.RestoreStatePending,
+		}
+	} else {
+		status = status.DeepCopy()
+	}
+
+	switch status.State {
+	case api.RestoreStatePending:
+		er.startRestore(spec, status)
+	case api.RestoreStateInProgress:
+		er.checkRestore(status)
+	}
+
+	return er.updateRestoreStatus(status, true)
+}
+
+// validateRestore returns the reasons why the restore spec can not be reconciled
+func validateRestore(cluster *api.Elasticsearch) []string {
+	var reasons []string
+	if restoreRepository(cluster) == "" {
+		reasons = append(reasons, fmt.Sprintf("Restore of snapshot '%s' requires a repository", cluster.Spec.Restore.Snapshot))
+	}
+	return reasons
+}
+
+func restoreRepository(cluster *api.Elasticsearch) string {
+	if cluster.Spec.Restore.Repository != "" {
+		return cluster.Spec.Restore.Repository
+	}
+	if cluster.Spec.Snapshot != nil {
+		return cluster.Spec.Snapshot.Repository.Name
+	}
+	return ""
+}
+
+func (er *ElasticsearchRequest) startRestore(spec *api.ElasticsearchRestoreSpec, status *api.ElasticsearchRestoreStatus) {
+	if reasons := validateRestore(er.cluster); len(reasons) > 0 {
+		failRestore(status, strings.Join(reasons, "; "))
+		return
+	}
+
+	if !er.AnyNodeReady() {
+		status.Message = "Waiting for the cluster to be ready"
+		return
+	}
+
+	health, err := er.esClient.GetClusterHealthStatus()
+	if err != nil {
+		er.L().Error(err, "Unable to get cluster health for restore")
+		status.Message = "Waiting for the cluster health"
+		return
+	}
+	if health != "green" {
+		status.Message = fmt.Sprintf("Waiting for the cluster health to be green, currently %q", health)
+		return
+	}
+
+	snapshot, err := er.esClient.GetSnapshot(status.Repository, status.Snapshot)
+	if err != nil {
+		er.L().Error(err, "Unable to get snapshot for restore", "repository", status.Repository, "snapshot", status.Snapshot)
+		status.Message = "Waiting for the snapshot repository"
+		return
+	}
+	if snapshot == nil {
+		failRestore(status, fmt.Sprintf("Snapshot '%s' does not exist in repository '%s'", status.Snapshot, status.Repository))
+		return
+	}
+	if snapshot.State != snapshotStateSuccess && snapshot.State != snapshotStatePartial {
+		failRestore(status, fmt.Sprintf("Snapshot '%s' can not be restored in state %s", status.Snapshot, snapshot.State))
+		return
+	}
+
+	indices := matchIndexPatterns(snapshot.Indices, spec.Indices)
+	if len(indices) == 0 {
+		failRestore(status, fmt.Sprintf("Snapshot '%s' has no indices matching %v", status.Snapshot, spec.Indices))
+		return
+	}
+
+	existing, err := er.esClient.GetAllIndices("*")
+	if err != nil {
+		er.L().Error(err, "Unable to list indices for restore")
+		status.Message = "Waiting for the list of indices"
+		return
+	}
+	conflicts := conflictingIndices(indices, existing)
+
+	// the aliases of the snapshot are not restored since its write aliases would add a second
+	// write index to the aliases of the live indices. The read aliases are added afterwards
+	request := &estypes.SnapshotRestoreRequest{
+		Indices:        strings.Join(indices, ","),
+		IncludeAliases: false,
+	}
+	restored := indices
+	aliased := indices
+	closed := []string{}
+	if len(conflicts) > 0 {
+		switch spec.ConflictPolicy {
+		case api.RestoreConflictPolicyClose:
+			// closing a write index stops the ingestion into its alias and the restore drops
+			// the documents written since the snapshot
+			if index, err := er.findWriteIndex(conflicts); err != nil {
+				er.L().Error(err, "Unable to get the aliases of the conflicting indices for restore")
+				status.Message = "Waiting for the aliases of the conflicting indices"
+				return
+			} else if index != "" {
+				failRestore(status, fmt.Sprintf("Refusing to close the conflicting index '%s', it is the write index of an alias. Use the Rename conflict policy", index))
+				return
+			}
+			for _, index := range conflicts {
+				if err := er.esClient.CloseIndex(index); err != nil {
+					er.L().Error(err, "Unable to close index for restore", "index", index)
+					er.openIndices(closed)
+					failRestore(status, fmt.Sprintf("Unable to close the conflicting index '%s'", index))
+					return
+				}
+				closed = append(closed, index)
+			}
+		default:
+			request.RenamePattern, request.RenameReplacement = newRenameRequest(conflicts)
+			restored = renameIndices(indices, conflicts)
+			// renamed indices would otherwise duplicate the documents of the existing ones
+			aliased = excludeIndices(indices, conflicts)
+		}
+	}
+
+	if err := er.esClient.RestoreSnapshot(status.Repository, status.Snapshot, request); err != nil {
+		er.L().Error(err, "Unable to restore snapshot", "repository", status.Repository, "snapshot", status.Snapshot)
+		er.openIndices(closed)
+		failRestore(status, fmt.Sprintf("Unable to restore snapshot: %s", err))
+		return
+	}
+
+	if actions := newReadAliasActions(aliased, er.cluster.Spec.IndexManagement); len(actions.Actions) > 0 {
+		if err := er.esClient.UpdateAlias(actions); err != nil {
+			er.L().Error(err, "Unable to add the read aliases to the restored indices")
+		}
+	}
+
+	now := metav1.Now()
+	status.State = api.RestoreStateInProgress
+	status.Indices = restored
+	status.StartTime = &now
+	status.Message = ""
+}
+
+func (er *ElasticsearchRequest) checkRestore(status *api.ElasticsearchRestoreStatus) {
+	recoveries, err := er.esClient.GetIndicesRecovery(strings.Join(status.Indices, ","))
+	if err != nil {
+		er.L().Error(err, "Unable to get the recovery of the restored indices")
+		return
+	}
+	if !isRestoreComplete(status.Indices, recoveries) {
+		return
+	}
+
+	now := metav1.Now()
+	status.State = api.RestoreStateCompleted
+	status.CompletionTime = &now
+}
+
+// findWriteIndex returns the first of the indices which is the write index of an alias
+func (er *ElasticsearchRequest) findWriteIndex(indices []string) (string, error) {
+	for _, index := range indices {
+		aliases, err := er.esClient.GetIndexAliases(index)
+		if err != nil {
+			return "", err
+		}
+		for _, alias := range aliases {
+			if alias.IsWriteIndex {
+				return index, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// openIndices reopens the indices closed for a restore which could not be started
+func (er *ElasticsearchRequest) openIndices(indices []string) {
+	for _, index := range indices {
+		if err := er.esClient.OpenIndex(index); err != nil {
+			er.L().Error(err, "Unable to reopen index after failed restore", "index", index)
+		}
+	}
+}
+
+func failRestore(status *api.ElasticsearchRestoreStatus, message string) {
+	now := metav1.Now()
+	status.State = api.RestoreStateFailed
+	status.Message = message
+	status.CompletionTime = &now
+}
+
+// matchIndexPatterns returns the indices matching any of the patterns. Indices starting
+// with a '.' are only matched by patterns starting with a '.'
+func matchIndexPatterns(indices, patterns []string) []string {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+
+	matched := []string{}
+	for _, index := range indices {
+		for _, pattern := range patterns {
+			if strings.HasPrefix(index, ".") && !strings.HasPrefix(pattern, ".") {
+				continue
+			}
+			if ok, _ := path.Match(pattern, index); ok {
+				matched = append(matched, index)
+				break
+			}
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+func conflictingIndices(indices []string, existing estypes.CatIndicesResponses) []string {
+	names := map[string]bool{}
+	for _, index := range existing {
+		names[index.Index] = true
+	}
+
+	conflicts := []string{}
+	for _, index := range indices {
+		if names[index] {
+			conflicts = append(conflicts, index)
+		}
+	}
+	return conflicts
+}
+
+// newRenameRequest returns the rename pattern and replacement to restore the
+// conflicting indices with the restored prefix
+func newRenameRequest(conflicts []string) (string, string) {
+	quoted := make([]string, len(conflicts))
+	for i, index := range conflicts {
+		quoted[i] = regexp.QuoteMeta(index)
+	}
+	return fmt.Sprintf("^(%s)$", strings.Join(quoted, "|")), api.RestoredIndexPrefix + "$1"
+}
+
+func renameIndices(indices, conflicts []string) []string {
+	renamed := map[string]bool{}
+	for _, index := range conflicts {
+		renamed[index] = true
+	}
+
+	result := make([]string, len(indices))
+	for i, index := range indices {
+		if renamed[index] {
+			index = api.RestoredIndexPrefix + index
+		}
+		result[i] = index
+	}
+	return result
+}
+
+func excludeIndices(indices, excluded []string) []string {
+	skip := map[string]bool{}
+	for _, index := range excluded {
+		skip[index] = true
+	}
+
+	result := []string{}
+	for _, index := range indices {
+		if !skip[index] {
+			result = append(result, index)
+		}
+	}
+	return result
+}
+
+// newReadAliasActions returns the actions adding the read aliases of the index management
+// mappings to the restored indices of the mappings. The write aliases are left to the live indices
+func newReadAliasActions(indices []string, spec *api.IndexManagementSpec) estypes.AliasActions {
+	actions := estypes.AliasActions{Actions: []estypes.AliasAction{}}
+	if spec == nil {
+		return actions
+	}
+
+	for _, index := range indices {
+		for _, mapping := range spec.Mappings {
+			if !strings.HasPrefix(index, mapping.Name+"-") {
+				continue
+			}
+			for _, alias := range append([]string{mapping.Name}, mapping.Aliases...) {
+				actions.Actions = append(actions.Actions, estypes.AliasAction{
+					Add: &estypes.AddAliasAction{Index: index, Alias: alias},
+				})
+			}
+		}
+	}
+	return actions
+}
+
+// isRestoreComplete returns true once every shard restored from the snapshot is recovered
+func isRestoreComplete(indices []string, recoveries map[string]estypes.IndexRecovery) bool {
+	for _, index := range indices {
+		recovery, ok := recoveries[index]
+		if !ok || len(recovery.Shards) == 0 {
+			return false
+		}
+		for _, shard := range recovery.Shards {
+			if shard.Type == shardRecoveryTypeSnapshot && shard.Stage != shardRecoveryStageDone {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// updateRestoreStatus persists the restore status and the conditions derived from it.
+// Conditions are removed when the restore is no longer spec'd
+func (er *ElasticsearchRequest) updateRestoreStatus(status *api.ElasticsearchRestoreStatus, spec bool) error {
+	cluster := er.cluster
+
+	restoring := &api.ClusterCondition{Type: api.Restoring, Status: v1.ConditionFalse}
+	failed := &api.ClusterCondition{Type: api.RestoreFailed, Status: v1.ConditionFalse}
+	if spec && status != nil {
+		switch status.State {
+		case api.RestoreStatePending, api.RestoreStateInProgress:
+			restoring.Status = v1.ConditionTrue
+			restoring.Reason = string(status.State)
+			restoring.Message = status.Message
+			if status.State == api.RestoreStateInProgress {
+				restoring.Message = fmt.Sprintf("Restoring snapshot '%s' from repository '%s'", status.Snapshot, status.Repository)
+			}
+		case api.RestoreStateFailed:
+			failed.Status = v1.ConditionTrue
+			failed.Reason = "Failed"
+			failed.Message = status.Message
+		}
+	}
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		current := &api.Elasticsearch{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, current); err != nil {
+			return err
+		}
+
+		changed := !reflect.DeepEqual(current.Status.Restore, status)
+		current.Status.Restore = status
+		if updateESNodeCondition(&current.Status, restoring.DeepCopy()) {
+			changed = true
+		}
+		if updateESNodeCondition(&current.Status, failed.DeepCopy()) {
+			changed = true
+		}
+		if !changed {
+			return nil
+		}
+
+		return er.client.Status().Update(context.TODO(), current)
+	})
+
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update restore status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("restore.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	Describe("#matchIndexPatterns", func() {
+		indices := []string{"app-000002", "infra-000001", ".security", "app-000001"}
+
+		It("should match all indices except hidden ones by default", func() {
+			Expect(matchIndexPatterns(indices, nil)).To(Equal([]string{"app-000001", "app-000002", "infra-000001"}))
+		})
+		It("should match the spec'd patterns", func() {
+			Expect(matchIndexPatterns(indices, []string{"app-*", ".sec*"})).To(Equal([]string{".security", "app-000001", "app-000002"}))
+		})
+	})
+
+	Describe("#newRenameRequest", func() {
+		It("should only rename the conflicting indices", func() {
+			pattern, replacement := newRenameRequest([]string{"app-000001", "infra.1"})
+			Expect(pattern).To(Equal(`^(app-000001|infra\.1)$`))
+			Expect(replacement).To(Equal("restored-$1"))
+			Expect(renameIndices([]string{"app-000001", "app-000002"}, []string{"app-000001"})).To(Equal([]string{"restored-app-000001", "app-000002"}))
+		})
+	})
+
+	Describe("#newReadAliasActions", func() {
+		It("should only add the read aliases of the mappings", func() {
+			spec := &api.IndexManagementSpec{
+				Mappings: []api.IndexManagementPolicyMappingSpec{
+					{Name: "app", Aliases: []string{"logs.app"}},
+					{Name: "infra"},
+				},
+			}
+			actions := newReadAliasActions([]string{"app-000001", "infra-000002", "other"}, spec)
+			Expect(actions.Actions).To(Equal([]estypes.AliasAction{
+				{Add: &estypes.AddAliasAction{Index: "app-000001", Alias: "app"}},
+				{Add: &estypes.AddAliasAction{Index: "app-000001", Alias: "logs.app"}},
+				{Add: &estypes.AddAliasAction{Index: "infra-000002", Alias: "infra"}},
+			}))
+		})
+		It("should add no aliases without index management", func() {
+			Expect(newReadAliasActions([]string{"app-000001"}, nil).Actions).To(BeEmpty())
+		})
+	})
+
+	Describe("#isRestoreComplete", func() {
+		It("should wait for every snapshot shard to be done", func() {
+			recoveries := map[string]estypes.IndexRecovery{
+				"app-000001": {Shards: []estypes.ShardRecovery{{Type: "SNAPSHOT", Stage: "DONE"}, {Type: "PEER", Stage: "INDEX"}}},
+				"app-000002": {Shards: []estypes.ShardRecovery{{Type: "SNAPSHOT", Stage: "INDEX"}}},
+			}
+			Expect(isRestoreComplete([]string{"app-000001"}, recoveries)).To(BeTrue())
+			Expect(isRestoreComplete([]string{"app-000001", "app-000002"}, recoveries)).To(BeFalse())
+			Expect(isRestoreComplete([]string{"app-000003"}, recoveries)).To(BeFalse())
+		})
+	})
+
+	Describe("#CreateOrUpdateRestore", func() {
+		var (
+			cluster   *api.Elasticsearch
+			k8sClient client.Client
+			chatter   *helpers.FakeElasticsearchChatter
+			request   *ElasticsearchRequest
+			key       = types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}
+		)
+
+		BeforeEach(func() {
+			cluster = &api.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: api.ElasticsearchSpec{
+					Restore: &api.ElasticsearchRestoreSpec{Repository: "backups", Snapshot: "scheduled-1"},
+				},
+			}
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+				"app-000001/_recovery": {
+					{
+						StatusCode: 200,
+						Body:       `{"app-000001": {"shards": [{"id": 0, "type": "SNAPSHOT", "stage": "DONE"}]}}`,
+					},
+				},
+			})
+		})
+
+		JustBeforeEach(func() {
+			k8sClient = fake.NewFakeClient(cluster)
+			request = &ElasticsearchRequest{
+				client:   k8sClient,
+				cluster:  cluster,
+				esClient: helpers.NewFakeElasticsearchClient(key.Name, key.Namespace, k8sClient, chatter),
+			}
+		})
+
+		Context("when the restore is pending", func() {
+			It("should wait for the cluster and set the restoring condition", func() {
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(current.Status.Restore.State).To(Equal(api.RestoreStatePending))
+				_, condition := getESNodeCondition(current.Status.Conditions, api.Restoring)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Status).To(Equal(v1.ConditionTrue))
+				Expect(condition.Message).To(Equal("Waiting for the cluster to be ready"))
+			})
+		})
+
+		Context("when the restore is in progress", func() {
+			BeforeEach(func() {
+				cluster.Status.Restore = &api.ElasticsearchRestoreStatus{
+					Repository: "backups",
+					Snapshot:   "scheduled-1",
+					State:      api.RestoreStateInProgress,
+					Indices:    []string{"app-000001"},
+				}
+				cluster.Status.Conditions = []api.ClusterCondition{{Type: api.Restoring, Status: v1.ConditionTrue}}
+			})
+
+			It("should complete once the indices are recovered", func() {
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(current.Status.Restore.State).To(Equal(api.RestoreStateCompleted))
+				Expect(current.Status.Restore.CompletionTime).ToNot(BeNil())
+				Expect(current.Status.Conditions).To(BeEmpty())
+			})
+			It("should restart for another snapshot", func() {
+				cluster.Spec.Restore.Snapshot = "scheduled-2"
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(current.Status.Restore.Snapshot).To(Equal("scheduled-2"))
+				Expect(current.Status.Restore.State).To(Equal(api.RestoreStatePending))
+			})
+		})
+
+		Context("when the restored indices exist", func() {
+			const (
+				restoreURI = "_snapshot/backups/scheduled-1/_restore"
+				aliasURI   = "app-000001/_alias"
+			)
+
+			BeforeEach(func() {
+				cluster.Spec.Restore.Indices = []string{"app-*"}
+				chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+					"_cluster/health": {
+						{StatusCode: 200, Body: `{"status": "green"}`},
+					},
+					"_snapshot/backups/scheduled-1": {
+						{StatusCode: 200, Body: `{"snapshots": [{"snapshot": "scheduled-1", "state": "SUCCESS", "indices": ["app-000001", "app-000002"]}]}`},
+					},
+					"_cat/indices/*?format=json": {
+						{StatusCode: 200, Body: `[{"index": "app-000001"}]`},
+					},
+					aliasURI: {
+						{StatusCode: 200, Body: `{"app-000001": {"aliases": {"app": {}, "app-write": {"is_write_index": true}}}}`},
+					},
+					restoreURI: {
+						{StatusCode: 200, Body: `{"accepted": true}`},
+					},
+					"_aliases": {
+						{StatusCode: 200, Body: `{"acknowledged": true}`},
+					},
+				})
+			})
+
+			JustBeforeEach(func() {
+				request = newManagedObjectsRequest(cluster, chatter)
+			})
+
+			It("should rename them by default", func() {
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				status := getClusterStatus(request)
+				Expect(status.Restore.State).To(Equal(api.RestoreStateInProgress))
+				Expect(status.Restore.Indices).To(Equal([]string{"restored-app-000001", "app-000002"}))
+				req, found := chatter.GetRequest(restoreURI)
+				Expect(found).To(BeTrue())
+				Expect(req.Body).To(ContainSubstring(`"rename_pattern":"^(app-000001)$"`))
+				Expect(chatter.Requests).ToNot(HaveKey("app-000001/_close"))
+			})
+
+			It("should refuse to close the write index of an alias", func() {
+				cluster.Spec.Restore.ConflictPolicy = api.RestoreConflictPolicyClose
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				status := getClusterStatus(request)
+				Expect(status.Restore.State).To(Equal(api.RestoreStateFailed))
+				Expect(status.Restore.Message).To(ContainSubstring("Refusing to close the conflicting index 'app-000001'"))
+				Expect(chatter.Requests).ToNot(HaveKey("app-000001/_close"))
+				Expect(chatter.Requests).ToNot(HaveKey(restoreURI))
+			})
+		})
+
+		Context("when no repository is known", func() {
+			It("should fail the restore", func() {
+				cluster.Spec.Restore.Repository = ""
+				Expect(request.CreateOrUpdateRestore()).To(Succeed())
+
+				current := &api.Elasticsearch{}
+				Expect(k8sClient.Get(context.TODO(), key, current)).To(Succeed())
+				Expect(current.Status.Restore.State).To(Equal(api.RestoreStateFailed))
+				_, condition := getESNodeCondition(current.Status.Conditions, api.RestoreFailed)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Message).To(ContainSubstring("requires a repository"))
+			})
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package k8shandler
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// nextRestartBatch returns the nodes of a rolling restart to restart together and the nodes
+// left for later batches. Data nodes are restarted together, up to the max unavailable nodes
+// of the spec, when the redundancy policy keeps replicas and no two of them hold a copy of
+// the same shard. At most one master node is restarted at once to keep the quorum
+func (er *ElasticsearchRequest) nextRestartBatch(restartNodes []NodeTypeInterface) ([]NodeTypeInterface, []NodeTypeInterface) {
+	first := restartNodes[0]
+	maxUnavailable := int(er.cluster.Spec.MaxUnavailableNodes)
+	if maxUnavailable <= 1 || calculateReplicaCount(er.cluster) < 1 || !isDataNodeType(first) {
+		return restartNodes[:1], restartNodes[1:]
+	}
+
+	// the shards move between the batches, they are read again for every batch
+	nodeShards, err := er.esClient.GetNodeShards()
+	if err != nil {
+		er.L().Error(err, "unable to get the shards of the nodes, restarting one node at a time")
+		return restartNodes[:1], restartNodes[1:]
+	}
+
+	batch := []NodeTypeInterface{first}
+	remaining := []NodeTypeInterface{}
+	batchShards := sets.NewString(nodeShards[first.name()]...)
+	hasMaster := isMasterNodeType(first)
+
+	for _, node := range restartNodes[1:] {
+		shards := nodeShards[node.name()]
+		if len(batch) >= maxUnavailable ||
+			!isDataNodeType(node) ||
+			(hasMaster && isMasterNodeType(node)) ||
+			batchShards.HasAny(shards...) {
+			remaining = append(remaining, node)
+			continue
+		}
+
+		batch = append(batch, node)
+		batchShards.Insert(shards...)
+		hasMaster = hasMaster || isMasterNodeType(node)
+	}
+
+	return batch, remaining
+}
+
+// isMasterNodeType returns true for the deployment nodes eligible as master
+func isMasterNodeType(node NodeTypeInterface) bool {
+	deployment, ok := node.(*deploymentNode)
+	return ok && deployment.self.Labels["es-node-master"] == "true"
+}
@@ -0,0 +1,121 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("rollingrestart.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster      *api.Elasticsearch
+		request      *ElasticsearchRequest
+		chatter      *helpers.FakeElasticsearchChatter
+		restartNodes []NodeTypeInterface
+
+		masterData = map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleMaster: true, api.ElasticsearchRoleData: true}
+		dataOnly   = map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}
+	)
+
+	newNode := func(name string, roles map[api.ElasticsearchNodeRole]bool) NodeTypeInterface {
+		return &deploymentNode{self: apps.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: newLabels("elasticsearch", name, roles),
+		}}}
+	}
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy:    api.SingleRedundancy,
+				MaxUnavailableNodes: 3,
+			},
+		}
+		restartNodes = []NodeTypeInterface{
+			newNode("elasticsearch-cdm-abc-1", masterData),
+			newNode("elasticsearch-cdm-abc-2", masterData),
+			newNode("elasticsearch-cd-def-1", dataOnly),
+			newNode("elasticsearch-cd-def-2", dataOnly),
+			newNode("elasticsearch-cd-def-3", dataOnly),
+		}
+		chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+				{
+					StatusCode: 200,
+					Body: `[
+						{"index": "app-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-1"},
+						{"index": "app-000001", "shard": "0", "prirep": "r", "state": "STARTED", "node": "elasticsearch-cd-def-1"},
+						{"index": "infra-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cdm-abc-2"},
+						{"index": "infra-000001", "shard": "0", "prirep": "r", "state": "STARTED", "node": "elasticsearch-cd-def-2"},
+						{"index": "audit-000001", "shard": "0", "prirep": "p", "state": "STARTED", "node": "elasticsearch-cd-def-3"},
+						{"index": "audit-000001", "shard": "0", "prirep": "r", "state": "STARTED", "node": "elasticsearch-cdm-abc-2"}
+					]`,
+				},
+			},
+		})
+	})
+
+	JustBeforeEach(func() {
+		k8sClient := fake.NewFakeClient(cluster)
+		request = &ElasticsearchRequest{
+			client:   k8sClient,
+			cluster:  cluster,
+			esClient: helpers.NewFakeElasticsearchClient(cluster.Name, cluster.Namespace, k8sClient, chatter),
+		}
+	})
+
+	It("should restart data nodes without common shards together", func() {
+		batch, remaining := request.nextRestartBatch(restartNodes)
+		Expect(restartNodeNames(batch)).To(Equal([]string{"elasticsearch-cdm-abc-1", "elasticsearch-cd-def-2", "elasticsearch-cd-def-3"}))
+		Expect(restartNodeNames(remaining)).To(Equal([]string{"elasticsearch-cdm-abc-2", "elasticsearch-cd-def-1"}))
+	})
+
+	Context("when the batches are limited to two nodes", func() {
+		BeforeEach(func() {
+			cluster.Spec.MaxUnavailableNodes = 2
+		})
+
+		It("should restart no more than two nodes together", func() {
+			batch, remaining := request.nextRestartBatch(restartNodes)
+			Expect(restartNodeNames(batch)).To(Equal([]string{"elasticsearch-cdm-abc-1", "elasticsearch-cd-def-2"}))
+			Expect(remaining).To(HaveLen(3))
+		})
+	})
+
+	Context("when the redundancy policy keeps no replicas", func() {
+		BeforeEach(func() {
+			cluster.Spec.RedundancyPolicy = api.ZeroRedundancy
+		})
+
+		It("should restart one node at a time", func() {
+			batch, remaining := request.nextRestartBatch(restartNodes)
+			Expect(restartNodeNames(batch)).To(Equal([]string{"elasticsearch-cdm-abc-1"}))
+			Expect(remaining).To(HaveLen(4))
+		})
+	})
+
+	Context("when the shards are unknown", func() {
+		BeforeEach(func() {
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+				"_cat/shards?format=json&h=index,shard,prirep,state,node": {
+					{StatusCode: 500, Body: `{"error": "failed"}`},
+				},
+			})
+		})
+
+		It("should restart one node at a time", func() {
+			batch, remaining := request.nextRestartBatch(restartNodes)
+			Expect(restartNodeNames(batch)).To(Equal([]string{"elasticsearch-cdm-abc-1"}))
+			Expect(remaining).To(HaveLen(4))
+		})
+	})
+})
@@ -3,11 +3,15 @@ package k8shandler
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/openshift/elasticsearch-operator/internal/constants"
+	"github.com/openshift/elasticsearch-operator/internal/metrics"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -120,6 +124,49 @@ func getSecretDataHash(secretName, namespace string, client client.Client) strin
 	return hash
 }
 
+// getCADataHash returns the hash of the CA bundle the nodes trust
+func getCADataHash(secretName, namespace string, client client.Client) string {
+	secret, err := getSecret(secretName, namespace, client)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(secret.Data[esAdminCAName]))
+}
+
+// updateCertificateMetrics reports when the certificates of the cluster secret expire. The
+// trust bundle reports the CA expiring first
+func (er *ElasticsearchRequest) updateCertificateMetrics() {
+	secret, err := getSecret(er.cluster.Name, er.cluster.Namespace, er.client)
+	if err != nil {
+		return
+	}
+
+	expiries := map[string]time.Time{}
+	for _, name := range []string{esAdminCAName, esAdminCertName, esComponentCertName, esInternalCertname} {
+		if notAfter, ok := getPEMNotAfter(secret.Data[name]); ok {
+			expiries[name] = notAfter
+		}
+	}
+	metrics.SetCertificateExpiries(er.cluster.Namespace, er.cluster.Name, expiries)
+}
+
+// getPEMNotAfter returns the earliest expiry of the certificates of the PEM data
+func getPEMNotAfter(data []byte) (time.Time, bool) {
+	var notAfter time.Time
+	found := false
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !found || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+			found = true
+		}
+	}
+	return notAfter, found
+}
+
 // hasRequiredSecrets will check that all secrets that we expect for EO to be able to communicate
 // with the ES cluster it manages exist.
 // It will return true if all required secrets/keys exist.
@@ -0,0 +1,222 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	// securityAllDocumentTypes is the document type of the index permissions of managed roles
+	securityAllDocumentTypes = "*"
+)
+
+var (
+	securityKindRole        = managedObjectKind{name: "role", syncedReason: eventReasonSecurityObjectSynced, deletedReason: eventReasonSecurityObjectDeleted}
+	securityKindRoleMapping = managedObjectKind{name: "role mapping", syncedReason: eventReasonSecurityObjectSynced, deletedReason: eventReasonSecurityObjectDeleted}
+)
+
+// CreateOrUpdateSecurity writes the roles and role mappings of the spec through the REST API
+// of the security plugin whenever the ones in the cluster differ, and deletes the ones removed
+// from the spec. The roles are written before the mappings and deleted after them. Only the
+// objects the operator wrote are overwritten or deleted
+func (er *ElasticsearchRequest) CreateOrUpdateSecurity() error {
+	cluster := er.cluster
+	spec := cluster.Spec.Security
+	previous := cluster.Status.Security
+	if spec == nil && previous == nil {
+		return nil
+	}
+
+	// the status is kept until the security plugin can be reached
+	if !er.AnyNodeReady() {
+		return nil
+	}
+
+	if spec == nil {
+		spec = &api.ElasticsearchSecuritySpec{}
+	}
+	if previous == nil {
+		previous = &api.ElasticsearchSecurityStatus{}
+	}
+
+	roleNames := []string{}
+	for _, role := range spec.Roles {
+		roleNames = append(roleNames, role.Name)
+	}
+	mappingNames := []string{}
+	for _, mapping := range spec.RoleMappings {
+		mappingNames = append(mappingNames, mapping.Role)
+	}
+	previousRoles := fromSecurityObjectStatuses(previous.Roles)
+	previousMappings := fromSecurityObjectStatuses(previous.RoleMappings)
+
+	roles := er.syncManagedObjects(securityKindRole, roleNames, previousRoles, func(i int) (bool, bool, error) {
+		return er.syncSecurityRole(spec.Roles[i], ownsManagedObject(spec.Roles[i].Name, previousRoles))
+	})
+	mappings := er.syncManagedObjects(securityKindRoleMapping, mappingNames, previousMappings, func(i int) (bool, bool, error) {
+		return er.syncSecurityRoleMapping(spec.RoleMappings[i], ownsManagedObject(spec.RoleMappings[i].Role, previousMappings))
+	})
+	mappings = append(mappings,
+		er.deleteManagedObjects(securityKindRoleMapping, mappingNames, previousMappings, er.esClient.DeleteSecurityRoleMapping)...)
+	roles = append(roles,
+		er.deleteManagedObjects(securityKindRole, roleNames, previousRoles, er.esClient.DeleteSecurityRole)...)
+
+	var status *api.ElasticsearchSecurityStatus
+	if len(roles) != 0 || len(mappings) != 0 {
+		status = &api.ElasticsearchSecurityStatus{
+			Roles:        toSecurityObjectStatuses(roles),
+			RoleMappings: toSecurityObjectStatuses(mappings),
+		}
+	}
+	return er.updateStatusWithRetry("security", func(current *api.ElasticsearchStatus) bool {
+		if reflect.DeepEqual(current.Security, status) {
+			return false
+		}
+		current.Security = status
+		return true
+	})
+}
+
+// syncSecurityRole writes the role unless the one in the cluster matches the spec, is reserved or
+// was not written by the operator. It returns whether it wrote the role and whether it is reserved
+func (er *ElasticsearchRequest) syncSecurityRole(spec api.ElasticsearchRole, owned bool) (bool, bool, error) {
+	current, err := er.esClient.GetSecurityRole(spec.Name)
+	if err != nil {
+		return false, false, err
+	}
+	if current != nil && (current.Reserved || current.Readonly || current.Hidden) {
+		return false, true, nil
+	}
+	if current != nil && !owned {
+		return false, false, newManagedObjectNotOwnedError(securityKindRole, spec.Name)
+	}
+
+	desired := newSecurityRole(spec)
+	if current != nil && isSecurityRoleSame(current, desired) {
+		return false, false, nil
+	}
+	return true, false, er.esClient.PutSecurityRole(spec.Name, desired)
+}
+
+// syncSecurityRoleMapping writes the role mapping unless the one in the cluster matches the spec,
+// is reserved or was not written by the operator, e.g. the mapping of a static role
+func (er *ElasticsearchRequest) syncSecurityRoleMapping(spec api.ElasticsearchRoleMapping, owned bool) (bool, bool, error) {
+	current, err := er.esClient.GetSecurityRoleMapping(spec.Role)
+	if err != nil {
+		return false, false, err
+	}
+	if current != nil && (current.Reserved || current.Readonly || current.Hidden) {
+		return false, true, nil
+	}
+	if current != nil && !owned {
+		return false, false, newManagedObjectNotOwnedError(securityKindRoleMapping, spec.Role)
+	}
+
+	desired := newSecurityRoleMapping(spec)
+	if current != nil && isSecurityRoleMappingSame(current, desired) {
+		return false, false, nil
+	}
+	return true, false, er.esClient.PutSecurityRoleMapping(spec.Role, desired)
+}
+
+// newSecurityRole returns the role of the security plugin for the spec. The actions of index
+// patterns listed by several permissions are merged
+func newSecurityRole(spec api.ElasticsearchRole) *estypes.SecurityRole {
+	role := &estypes.SecurityRole{
+		Cluster: spec.ClusterPermissions,
+	}
+
+	for _, permission := range spec.IndexPermissions {
+		for _, pattern := range permission.IndexPatterns {
+			if role.Indices == nil {
+				role.Indices = map[string]map[string][]string{}
+			}
+			actions := sets.NewString(role.Indices[pattern][securityAllDocumentTypes]...)
+			actions.Insert(permission.AllowedActions...)
+			role.Indices[pattern] = map[string][]string{securityAllDocumentTypes: actions.List()}
+		}
+	}
+
+	return role
+}
+
+func newSecurityRoleMapping(spec api.ElasticsearchRoleMapping) *estypes.SecurityRoleMapping {
+	return &estypes.SecurityRoleMapping{
+		Users:        spec.Users,
+		BackendRoles: spec.BackendRoles,
+		Hosts:        spec.Hosts,
+	}
+}
+
+// isSecurityRoleSame compares the permissions of the roles regardless of their order
+func isSecurityRoleSame(current, desired *estypes.SecurityRole) bool {
+	if !sets.NewString(current.Cluster...).Equal(sets.NewString(desired.Cluster...)) {
+		return false
+	}
+	if len(current.Indices) != len(desired.Indices) {
+		return false
+	}
+	for pattern, desiredTypes := range desired.Indices {
+		currentTypes, ok := current.Indices[pattern]
+		if !ok || len(currentTypes) != len(desiredTypes) {
+			return false
+		}
+		for docType, actions := range desiredTypes {
+			if !sets.NewString(currentTypes[docType]...).Equal(sets.NewString(actions...)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isSecurityRoleMappingSame compares the members of the mappings regardless of their order
+func isSecurityRoleMappingSame(current, desired *estypes.SecurityRoleMapping) bool {
+	return sets.NewString(current.Users...).Equal(sets.NewString(desired.Users...)) &&
+		sets.NewString(current.BackendRoles...).Equal(sets.NewString(desired.BackendRoles...)) &&
+		sets.NewString(current.Hosts...).Equal(sets.NewString(desired.Hosts...))
+}
+
+func fromSecurityObjectStatuses(statuses []api.ElasticsearchSecurityObjectStatus) []managedObjectStatus {
+	objs := []managedObjectStatus{}
+	for _, status := range statuses {
+		objs = append(objs, managedObjectStatus{Name: status.Name, State: string(status.State), Message: status.Message, LastSyncTime: status.LastSyncTime})
+	}
+	return objs
+}
+
+func toSecurityObjectStatuses(objs []managedObjectStatus) []api.ElasticsearchSecurityObjectStatus {
+	statuses := []api.ElasticsearchSecurityObjectStatus{}
+	for _, obj := range objs {
+		statuses = append(statuses, api.ElasticsearchSecurityObjectStatus{Name: obj.Name, State: api.ElasticsearchSecurityObjectState(obj.State), Message: obj.Message, LastSyncTime: obj.LastSyncTime})
+	}
+	return statuses
+}
+
+// validateSecurity returns the reasons why roles or role mappings of the spec would be ignored
+func validateSecurity(spec *api.ElasticsearchSecuritySpec) []string {
+	var reasons []string
+
+	roles := sets.NewString()
+	for _, role := range spec.Roles {
+		if roles.Has(role.Name) {
+			reasons = append(reasons, fmt.Sprintf("Security role '%s' is defined more than once", role.Name))
+		}
+		roles.Insert(role.Name)
+	}
+
+	mappings := sets.NewString()
+	for _, mapping := range spec.RoleMappings {
+		if mappings.Has(mapping.Role) {
+			reasons = append(reasons, fmt.Sprintf("Security role mapping of role '%s' is defined more than once", mapping.Role))
+		}
+		mappings.Insert(mapping.Role)
+	}
+
+	return reasons
+}
@@ -0,0 +1,188 @@
+package k8shandler
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("securityroles.go", func() {
+	defer GinkgoRecover()
+
+	_ = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	teamARead := api.ElasticsearchRole{
+		Name:               "team_a_read",
+		ClusterPermissions: []string{"CLUSTER_COMPOSITE_OPS_RO", "CLUSTER_MONITOR"},
+		IndexPermissions: []api.ElasticsearchIndexPermission{
+			{IndexPatterns: []string{"app-team-a-*"}, AllowedActions: []string{"READ"}},
+			{IndexPatterns: []string{"app-team-a-*", "infra-*"}, AllowedActions: []string{"SEARCH"}},
+		},
+	}
+
+	Describe("#newSecurityRole", func() {
+		It("should merge the actions of the patterns listed by several permissions", func() {
+			role := newSecurityRole(teamARead)
+			Expect(role.Indices).To(Equal(map[string]map[string][]string{
+				"app-team-a-*": {"*": {"READ", "SEARCH"}},
+				"infra-*":      {"*": {"SEARCH"}},
+			}))
+		})
+	})
+
+	Describe("#isSecurityRoleSame", func() {
+		It("should ignore the order of the permissions", func() {
+			current := &estypes.SecurityRole{
+				Cluster: []string{"CLUSTER_MONITOR", "CLUSTER_COMPOSITE_OPS_RO"},
+				Indices: map[string]map[string][]string{
+					"infra-*":      {"*": {"SEARCH"}},
+					"app-team-a-*": {"*": {"SEARCH", "READ"}},
+				},
+			}
+			Expect(isSecurityRoleSame(current, newSecurityRole(teamARead))).To(BeTrue())
+		})
+
+		It("should detect the index patterns which drifted", func() {
+			current := &estypes.SecurityRole{
+				Cluster: []string{"CLUSTER_COMPOSITE_OPS_RO", "CLUSTER_MONITOR"},
+				Indices: map[string]map[string][]string{
+					"app-*":   {"*": {"READ", "SEARCH"}},
+					"infra-*": {"*": {"SEARCH"}},
+				},
+			}
+			Expect(isSecurityRoleSame(current, newSecurityRole(teamARead))).To(BeFalse())
+		})
+	})
+
+	Describe("#CreateOrUpdateSecurity", func() {
+		const (
+			roleURI    = "_opendistro/_security/api/roles/team_a_read"
+			mappingURI = "_opendistro/_security/api/rolesmapping/team_a_read"
+		)
+
+		var (
+			cluster *api.Elasticsearch
+			chatter *helpers.FakeElasticsearchChatter
+			request *ElasticsearchRequest
+		)
+
+		BeforeEach(func() {
+			cluster = newManagedObjectsCluster(api.ElasticsearchSpec{
+				Security: &api.ElasticsearchSecuritySpec{
+					Roles:        []api.ElasticsearchRole{teamARead},
+					RoleMappings: []api.ElasticsearchRoleMapping{{Role: "team_a_read", BackendRoles: []string{"team-a"}}},
+				},
+			})
+			chatter = helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{})
+		})
+
+		JustBeforeEach(func() {
+			request = newManagedObjectsRequest(cluster, chatter)
+		})
+
+		Context("when the role and its mapping are reserved", func() {
+			BeforeEach(func() {
+				chatter.Responses[roleURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"team_a_read": {"readonly": true, "cluster": ["*"]}}`},
+				}
+				chatter.Responses[mappingURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"team_a_read": {"hidden": true, "users": ["admin"]}}`},
+				}
+			})
+
+			It("should neither overwrite them nor delete them once removed from the spec", func() {
+				Expect(request.CreateOrUpdateSecurity()).To(Succeed())
+				Expect(chatter.Requests[roleURI]).To(HaveLen(1))
+				Expect(chatter.Requests[mappingURI]).To(HaveLen(1))
+
+				status := getClusterStatus(request).Security
+				Expect(status.Roles[0].State).To(Equal(api.SecurityObjectReserved))
+				Expect(status.RoleMappings[0].State).To(Equal(api.SecurityObjectReserved))
+
+				request.cluster.Spec.Security = nil
+				Expect(request.CreateOrUpdateSecurity()).To(Succeed())
+				Expect(chatter.Requests[roleURI]).To(HaveLen(1))
+				Expect(chatter.Requests[mappingURI]).To(HaveLen(1))
+				Expect(getClusterStatus(request).Security).To(BeNil())
+			})
+		})
+
+		Context("when the mapping of a role exists and was not written by the operator", func() {
+			BeforeEach(func() {
+				lastSync := metav1.Now()
+				cluster.Status.Security = &api.ElasticsearchSecurityStatus{
+					Roles: []api.ElasticsearchSecurityObjectStatus{{Name: "team_a_read", State: api.SecurityObjectSynced, LastSyncTime: &lastSync}},
+				}
+				chatter.Responses[roleURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"team_a_read": {"cluster": ["CLUSTER_COMPOSITE_OPS_RO"], "indices": {"app-*": {"*": ["READ"]}}}}`},
+					{StatusCode: 200, Body: `{"status": "OK"}`},
+				}
+				chatter.Responses[mappingURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"team_a_read": {"backendroles": ["admins"]}}`},
+				}
+			})
+
+			It("should rewrite the drifted role and leave the mapping as is", func() {
+				Expect(request.CreateOrUpdateSecurity()).To(Succeed())
+
+				Expect(chatter.Requests[roleURI]).To(HaveLen(2))
+				Expect(chatter.Requests[roleURI][1].Method).To(Equal(http.MethodPut))
+				Expect(chatter.Requests[mappingURI]).To(HaveLen(1))
+
+				status := getClusterStatus(request).Security
+				Expect(status.Roles[0].State).To(Equal(api.SecurityObjectSynced))
+				Expect(status.RoleMappings[0].State).To(Equal(api.SecurityObjectFailed))
+				Expect(status.RoleMappings[0].Message).To(ContainSubstring("not written by the operator"))
+			})
+		})
+
+		Context("when the role and its mapping are removed from the spec", func() {
+			BeforeEach(func() {
+				lastSync := metav1.Now()
+				cluster.Spec.Security = nil
+				cluster.Status.Security = &api.ElasticsearchSecurityStatus{
+					Roles: []api.ElasticsearchSecurityObjectStatus{{Name: "team_a_read", State: api.SecurityObjectSynced, LastSyncTime: &lastSync}},
+					RoleMappings: []api.ElasticsearchSecurityObjectStatus{
+						{Name: "team_a_read", State: api.SecurityObjectSynced, LastSyncTime: &lastSync},
+						{Name: "team_b_read", State: api.SecurityObjectFailed, Message: "Unable to write the role mapping: the role mapping exists and was not written by the operator"},
+					},
+				}
+				chatter.Responses[roleURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"status": "OK"}`},
+				}
+				chatter.Responses[mappingURI] = helpers.FakeElasticsearchResponses{
+					{StatusCode: 200, Body: `{"status": "OK"}`},
+				}
+			})
+
+			It("should delete the mapping before the role and leave the ones it did not write", func() {
+				Expect(request.CreateOrUpdateSecurity()).To(Succeed())
+
+				mappingReq, _ := chatter.GetRequest(mappingURI)
+				roleReq, _ := chatter.GetRequest(roleURI)
+				Expect(mappingReq.Method).To(Equal(http.MethodDelete))
+				Expect(roleReq.Method).To(Equal(http.MethodDelete))
+				Expect(mappingReq.SeqNo).To(BeNumerically("<", roleReq.SeqNo))
+				Expect(chatter.Requests).ToNot(HaveKey("_opendistro/_security/api/rolesmapping/team_b_read"))
+				Expect(getClusterStatus(request).Security).To(BeNil())
+			})
+		})
+	})
+
+	Describe("#validateSecurity", func() {
+		It("should reject the mappings of a role defined more than once", func() {
+			spec := &api.ElasticsearchSecuritySpec{
+				RoleMappings: []api.ElasticsearchRoleMapping{{Role: "team_a_read"}, {Role: "team_a_read"}},
+			}
+			Expect(validateSecurity(spec)).To(ConsistOf("Security role mapping of role 'team_a_read' is defined more than once"))
+		})
+	})
+})
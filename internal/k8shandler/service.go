@@ -12,6 +12,8 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 )
 
 // CreateOrUpdateServices ensures the existence of the services for Elasticsearch cluster
@@ -41,13 +43,19 @@ func (er *ElasticsearchRequest) CreateOrUpdateServices() error {
 		return errCtx.Wrap(err, "failed to create service")
 	}
 
+	// coordinating nodes serve the HTTP requests in place of the other client nodes
+	restRole := "es-node-client"
+	if hasNodeRole(dpl.Spec.Nodes, api.ElasticsearchRoleCoordinating) {
+		restRole = "es-node-coordinating"
+	}
+
 	err = er.createOrUpdateService(
 		dpl.Name,
 		dpl.Namespace,
 		dpl.Name,
 		"restapi",
 		9200,
-		selectorForES("es-node-client", dpl.Name),
+		selectorForES(restRole, dpl.Name),
 		annotations,
 		false,
 		map[string]string{},
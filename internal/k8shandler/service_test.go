@@ -381,3 +381,40 @@ func TestCreateOrUpdateServices(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOrUpdateServicesWithCoordinatingNodes(t *testing.T) {
+	cluster := &loggingv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "elasticsearch",
+			Namespace: "openshift-logging",
+		},
+		Spec: loggingv1.ElasticsearchSpec{
+			Nodes: []loggingv1.ElasticsearchNode{
+				{Roles: []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleClient, loggingv1.ElasticsearchRoleData, loggingv1.ElasticsearchRoleMaster}},
+				{Roles: []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleCoordinating}},
+			},
+		},
+	}
+	client := fake.NewFakeClient()
+	req := &ElasticsearchRequest{
+		client:  client,
+		cluster: cluster,
+		ll:      log.Log.WithValues("cluster", "test-elasticsearch", "namespace", "test"),
+	}
+
+	if err := req.CreateOrUpdateServices(); err != nil {
+		t.Fatalf("failed with error: %s", err)
+	}
+
+	got := &corev1.Service{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: "elasticsearch", Namespace: "openshift-logging"}, got); err != nil {
+		t.Fatalf("failed with error: %s", err)
+	}
+	want := map[string]string{
+		"cluster-name":         "elasticsearch",
+		"es-node-coordinating": "true",
+	}
+	if diff := cmp.Diff(got.Spec.Selector, want); diff != "" {
+		t.Errorf("Exp. the REST API service to only select the coordinating nodes, diff: %s", diff)
+	}
+}
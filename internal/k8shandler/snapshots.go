@@ -0,0 +1,293 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+const (
+	snapshotVolumeName = "elasticsearch-snapshots"
+
+	// the credentials of an s3 repository are added to the keystore of the nodes by an init
+	// container, since Elasticsearch no longer reads them from the repository settings
+	keystoreInitContainerName = "elasticsearch-keystore"
+	keystoreVolumeName        = "elasticsearch-keystore"
+	keystorePath              = "/elasticsearch/keystore"
+	keystoreFile              = "elasticsearch.keystore"
+	s3CredentialsVolumeName   = "elasticsearch-s3-credentials"
+	s3CredentialsPath         = "/etc/elasticsearch/s3"
+	elasticsearchKeystoreTool = "/usr/share/elasticsearch/bin/elasticsearch-keystore"
+	s3KeystoreSettingsPrefix  = "s3.client.default."
+
+	snapshotStateSuccess = "SUCCESS"
+	snapshotStatePartial = "PARTIAL"
+	snapshotStateFailed  = "FAILED"
+)
+
+// the keys of the secret of an s3 repository
+var snapshotCredentialKeys = []string{"access_key", "secret_key"}
+
+// CreateOrUpdateSnapshots registers the snapshot repository, schedules the snapshots and
+// reports the results of the most recent ones
+func (er *ElasticsearchRequest) CreateOrUpdateSnapshots() error {
+	cluster := er.cluster
+	spec := cluster.Spec.Snapshot
+	if spec == nil {
+		if err := indexmanagement.RemoveSnapshotCronJob(er.client, cluster); err != nil {
+			return err
+		}
+		return er.updateSnapshotStatus(nil)
+	}
+
+	status := &api.ElasticsearchSnapshotStatus{Repository: spec.Repository.Name}
+	if reasons := validateSnapshot(spec); len(reasons) > 0 {
+		status.Message = strings.Join(reasons, "; ")
+		return er.updateSnapshotStatus(status)
+	}
+
+	if er.AnyNodeReady() {
+		if err := er.ensureSnapshotRepository(spec.Repository); err != nil {
+			er.L().Error(err, "Unable to register snapshot repository", "repository", spec.Repository.Name)
+			status.Message = fmt.Sprintf("Unable to register the snapshot repository: %s", err)
+		} else {
+			status.RepositoryReady = true
+			snapshots, err := er.esClient.GetSnapshots(spec.Repository.Name)
+			if err != nil {
+				er.L().Error(err, "Unable to get snapshots", "repository", spec.Repository.Name)
+			}
+			status.LastSuccess, status.LastFailure = latestSnapshotResults(snapshots)
+		}
+	} else if cluster.Status.Snapshot != nil {
+		status = cluster.Status.Snapshot.DeepCopy()
+	}
+
+	if err := indexmanagement.ReconcileSnapshotCronJob(er.client, cluster); err != nil {
+		return err
+	}
+
+	return er.updateSnapshotStatus(status)
+}
+
+// validateSnapshot returns the reasons why the snapshot spec can not be reconciled
+func validateSnapshot(spec *api.ElasticsearchSnapshotSpec) []string {
+	var reasons []string
+	repository := spec.Repository
+	if (repository.Filesystem == nil) == (repository.S3 == nil) {
+		reasons = append(reasons, fmt.Sprintf("Snapshot repository '%s' requires exactly one of 'filesystem' or 's3'", repository.Name))
+	}
+	reasons = append(reasons, indexmanagement.ValidateSnapshotRetention(spec.Retention)...)
+	return reasons
+}
+
+func (er *ElasticsearchRequest) ensureSnapshotRepository(spec api.ElasticsearchSnapshotRepositorySpec) error {
+	desired, err := er.newSnapshotRepository(spec)
+	if err != nil {
+		return err
+	}
+
+	current, err := er.esClient.GetSnapshotRepository(spec.Name)
+	if err != nil {
+		return err
+	}
+	if current != nil && isSnapshotRepositorySame(current, desired) {
+		return nil
+	}
+
+	return er.esClient.CreateSnapshotRepository(spec.Name, desired)
+}
+
+func (er *ElasticsearchRequest) newSnapshotRepository(spec api.ElasticsearchSnapshotRepositorySpec) (*estypes.SnapshotRepository, error) {
+	if spec.Filesystem != nil {
+		return &estypes.SnapshotRepository{
+			Type: "fs",
+			Settings: map[string]string{
+				"location": api.SnapshotPathRepo,
+				"compress": "true",
+			},
+		}, nil
+	}
+
+	s3 := spec.S3
+	secret := &v1.Secret{}
+	key := types.NamespacedName{Name: s3.SecretName, Namespace: er.cluster.Namespace}
+	if err := er.client.Get(context.TODO(), key, secret); err != nil {
+		return nil, kverrors.Wrap(err, "failed to get snapshot repository credentials",
+			"secret", s3.SecretName)
+	}
+	// the nodes can not start until the credentials are added to their keystore
+	for _, name := range snapshotCredentialKeys {
+		if _, ok := secret.Data[name]; !ok {
+			return nil, kverrors.New("snapshot repository credentials are missing a key",
+				"secret", s3.SecretName,
+				"key", name)
+		}
+	}
+
+	settings := map[string]string{
+		"bucket":   s3.Bucket,
+		"compress": "true",
+	}
+	if s3.BasePath != "" {
+		settings["base_path"] = s3.BasePath
+	}
+
+	return &estypes.SnapshotRepository{Type: "s3", Settings: settings}, nil
+}
+
+// isSnapshotRepositorySame compares the registered repository to the desired one. Repositories
+// registered with their credentials in the settings are registered again without them
+func isSnapshotRepositorySame(current, desired *estypes.SnapshotRepository) bool {
+	return current.Type == desired.Type && reflect.DeepEqual(current.Settings, desired.Settings)
+}
+
+// latestSnapshotResults returns the most recent successful and the most recent
+// failed or partial snapshot
+func latestSnapshotResults(snapshots []estypes.Snapshot) (success, failure *api.ElasticsearchSnapshotResult) {
+	var lastSuccess, lastFailure *estypes.Snapshot
+	for i, snapshot := range snapshots {
+		switch snapshot.State {
+		case snapshotStateSuccess:
+			if lastSuccess == nil || snapshot.EndTimeInMillis > lastSuccess.EndTimeInMillis {
+				lastSuccess = &snapshots[i]
+			}
+		case snapshotStatePartial, snapshotStateFailed:
+			if lastFailure == nil || snapshot.EndTimeInMillis > lastFailure.EndTimeInMillis {
+				lastFailure = &snapshots[i]
+			}
+		}
+	}
+	return newSnapshotResult(lastSuccess), newSnapshotResult(lastFailure)
+}
+
+func newSnapshotResult(snapshot *estypes.Snapshot) *api.ElasticsearchSnapshotResult {
+	if snapshot == nil {
+		return nil
+	}
+	return &api.ElasticsearchSnapshotResult{
+		Name:    snapshot.Snapshot,
+		State:   snapshot.State,
+		EndTime: metav1.NewTime(time.Unix(0, snapshot.EndTimeInMillis*int64(time.Millisecond))),
+		Reason:  snapshot.Reason,
+	}
+}
+
+// newSnapshotVolume returns the volume and mount of a filesystem snapshot repository
+func newSnapshotVolume(spec *api.ElasticsearchSnapshotSpec) (v1.Volume, v1.VolumeMount, bool) {
+	if spec == nil || spec.Repository.Filesystem == nil {
+		return v1.Volume{}, v1.VolumeMount{}, false
+	}
+
+	volume := v1.Volume{
+		Name: snapshotVolumeName,
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: spec.Repository.Filesystem.ClaimName,
+			},
+		},
+	}
+	mount := v1.VolumeMount{
+		Name:      snapshotVolumeName,
+		MountPath: api.SnapshotPathRepo,
+	}
+	return volume, mount, true
+}
+
+// newSnapshotKeystore returns the init container adding the credentials of an s3 snapshot
+// repository to the keystore of the node, the volumes it uses and the mount of the keystore
+// in the Elasticsearch container. Changed credentials are applied when the nodes restart
+func newSnapshotKeystore(imageName string, spec *api.ElasticsearchSnapshotSpec) (v1.Container, []v1.Volume, v1.VolumeMount, bool) {
+	if spec == nil || spec.Repository.S3 == nil {
+		return v1.Container{}, nil, v1.VolumeMount{}, false
+	}
+
+	commands := []string{
+		"set -e",
+		fmt.Sprintf("rm -f %s/%s", keystorePath, keystoreFile),
+		fmt.Sprintf("%s create", elasticsearchKeystoreTool),
+	}
+	for _, name := range snapshotCredentialKeys {
+		commands = append(commands, fmt.Sprintf("%s add --stdin %s%s < %s/%s", elasticsearchKeystoreTool, s3KeystoreSettingsPrefix, name, s3CredentialsPath, name))
+	}
+
+	container := v1.Container{
+		Name:            keystoreInitContainerName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command:         []string{"/bin/bash", "-c", strings.Join(commands, "\n")},
+		Env: []v1.EnvVar{
+			{Name: "ES_PATH_CONF", Value: keystorePath},
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: keystoreVolumeName, MountPath: keystorePath},
+			{Name: s3CredentialsVolumeName, MountPath: s3CredentialsPath, ReadOnly: true},
+		},
+	}
+	volumes := []v1.Volume{
+		{
+			Name:         keystoreVolumeName,
+			VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+		},
+		{
+			Name: s3CredentialsVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: spec.Repository.S3.SecretName},
+			},
+		},
+	}
+	// Elasticsearch only reads the keystore from its configuration directory
+	mount := v1.VolumeMount{
+		Name:      keystoreVolumeName,
+		MountPath: fmt.Sprintf("%s/%s", elasticsearchConfigPath, keystoreFile),
+		SubPath:   keystoreFile,
+		ReadOnly:  true,
+	}
+	return container, volumes, mount, true
+}
+
+// updateSnapshotStatus persists the snapshot status when it changed
+func (er *ElasticsearchRequest) updateSnapshotStatus(status *api.ElasticsearchSnapshotStatus) error {
+	cluster := er.cluster
+
+	nretries := -1
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nretries++
+		current := &api.Elasticsearch{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}, current); err != nil {
+			return err
+		}
+
+		if reflect.DeepEqual(current.Status.Snapshot, status) {
+			return nil
+		}
+
+		current.Status.Snapshot = status
+		return er.client.Status().Update(context.TODO(), current)
+	})
+
+	if retryErr != nil {
+		return kverrors.Wrap(retryErr, "failed to update snapshot status for cluster",
+			"cluster", cluster.Name,
+			"retries", nretries)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	estypes "github.com/openshift/elasticsearch-operator/internal/types/elasticsearch"
+)
+
+var _ = Describe("snapshots.go", func() {
+	defer GinkgoRecover()
+
+	Describe("#latestSnapshotResults", func() {
+		It("should return the most recent success and failure", func() {
+			success, failure := latestSnapshotResults([]estypes.Snapshot{
+				{Snapshot: "scheduled-1", State: "SUCCESS", EndTimeInMillis: 1000},
+				{Snapshot: "scheduled-2", State: "PARTIAL", EndTimeInMillis: 2000, Reason: "shard failed"},
+				{Snapshot: "scheduled-3", State: "SUCCESS", EndTimeInMillis: 3000},
+				{Snapshot: "scheduled-4", State: "IN_PROGRESS"},
+			})
+			Expect(success.Name).To(Equal("scheduled-3"))
+			Expect(success.EndTime.Unix()).To(Equal(int64(3)))
+			Expect(failure.Name).To(Equal("scheduled-2"))
+			Expect(failure.Reason).To(Equal("shard failed"))
+		})
+		It("should return nothing without completed snapshots", func() {
+			success, failure := latestSnapshotResults(nil)
+			Expect(success).To(BeNil())
+			Expect(failure).To(BeNil())
+		})
+	})
+
+	Describe("#isSnapshotRepositorySame", func() {
+		It("should register repositories with credentials in the settings again", func() {
+			desired := &estypes.SnapshotRepository{Type: "s3", Settings: map[string]string{"bucket": "logs"}}
+			current := &estypes.SnapshotRepository{Type: "s3", Settings: map[string]string{"bucket": "logs", "access_key": "a", "secret_key": "b"}}
+			Expect(isSnapshotRepositorySame(current, desired)).To(BeFalse())
+		})
+		It("should detect changed settings", func() {
+			desired := &estypes.SnapshotRepository{Type: "s3", Settings: map[string]string{"bucket": "logs"}}
+			current := &estypes.SnapshotRepository{Type: "s3", Settings: map[string]string{"bucket": "old"}}
+			Expect(isSnapshotRepositorySame(current, desired)).To(BeFalse())
+		})
+	})
+
+	Describe("#validateSnapshot", func() {
+		It("should require exactly one repository type", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{Name: "backups"}}
+			Expect(validateSnapshot(spec)).To(ConsistOf(ContainSubstring("exactly one of")))
+
+			spec.Repository.Filesystem = &api.ElasticsearchSnapshotFilesystemRepositorySpec{ClaimName: "snapshots"}
+			Expect(validateSnapshot(spec)).To(BeEmpty())
+		})
+		It("should reject a retention age that can not be converted", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{
+				Name:       "backups",
+				Filesystem: &api.ElasticsearchSnapshotFilesystemRepositorySpec{ClaimName: "snapshots"},
+			}}
+			for _, maxAge := range []api.TimeUnit{"30", "1y", "1M"} {
+				spec.Retention.MaxAge = maxAge
+				Expect(validateSnapshot(spec)).To(ConsistOf(ContainSubstring("Snapshot retention 'maxAge'")))
+			}
+
+			spec.Retention.MaxAge = "30d"
+			Expect(validateSnapshot(spec)).To(BeEmpty())
+		})
+	})
+
+	Describe("#newSnapshotVolume", func() {
+		It("should mount the filesystem repository claim", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{
+				Filesystem: &api.ElasticsearchSnapshotFilesystemRepositorySpec{ClaimName: "snapshots"},
+			}}
+			volume, mount, ok := newSnapshotVolume(spec)
+			Expect(ok).To(BeTrue())
+			Expect(volume.PersistentVolumeClaim.ClaimName).To(Equal("snapshots"))
+			Expect(mount.MountPath).To(Equal(api.SnapshotPathRepo))
+		})
+		It("should not mount a volume for an s3 repository", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{
+				S3: &api.ElasticsearchSnapshotS3RepositorySpec{Bucket: "logs"},
+			}}
+			_, _, ok := newSnapshotVolume(spec)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("#newSnapshotKeystore", func() {
+		It("should add the credentials of an s3 repository to the keystore", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{
+				S3: &api.ElasticsearchSnapshotS3RepositorySpec{Bucket: "logs", SecretName: "s3-credentials"},
+			}}
+			container, volumes, mount, ok := newSnapshotKeystore("elasticsearch:latest", spec)
+			Expect(ok).To(BeTrue())
+			Expect(container.Command[2]).To(ContainSubstring("add --stdin s3.client.default.access_key < /etc/elasticsearch/s3/access_key"))
+			Expect(container.Command[2]).To(ContainSubstring("add --stdin s3.client.default.secret_key < /etc/elasticsearch/s3/secret_key"))
+			Expect(volumes).To(HaveLen(2))
+			Expect(volumes[1].Secret.SecretName).To(Equal("s3-credentials"))
+			Expect(mount.MountPath).To(Equal("/usr/share/java/elasticsearch/config/elasticsearch.keystore"))
+			Expect(mount.SubPath).To(Equal("elasticsearch.keystore"))
+		})
+		It("should not use the keystore for a filesystem repository", func() {
+			spec := &api.ElasticsearchSnapshotSpec{Repository: api.ElasticsearchSnapshotRepositorySpec{
+				Filesystem: &api.ElasticsearchSnapshotFilesystemRepositorySpec{ClaimName: "snapshots"},
+			}}
+			_, _, _, ok := newSnapshotKeystore("elasticsearch:latest", spec)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -27,11 +27,16 @@ type statefulSetNode struct {
 	configmapHash string
 	// prior hash for secret content
 	secretHash string
+	// prior hash for the CA bundle in the secret
+	caHash string
 
 	clusterName string
 
 	replicas int32
 
+	// spec of the claim of the node storage, nil for ephemeral storage
+	claimSpec *v1.PersistentVolumeClaimSpec
+
 	client client.Client
 
 	esClient elasticsearch.Client
@@ -73,7 +78,7 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 		Selector: &metav1.LabelSelector{
 			MatchLabels: newLabelSelector(cluster.Name, nodeName, roleMap),
 		},
-		Template: newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, node, cluster.Spec.Spec, labels, roleMap, client, logConfig),
+		Template: newPodTemplateSpec(nodeName, cluster.Name, cluster.Namespace, node, cluster.Spec.Spec, cluster.Spec.Snapshot, nodeAttributeNames(cluster.Spec.Nodes), getNodeIngest(cluster.Spec.Nodes, roleMap), labels, roleMap, logConfig),
 		UpdateStrategy: apps.StatefulSetUpdateStrategy{
 			Type: apps.RollingUpdateStatefulSetStrategyType,
 			RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
@@ -87,6 +92,7 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 
 	n.self = statefulSet
 	n.clusterName = cluster.Name
+	n.claimSpec = newPersistentVolumeClaimSpec(node)
 
 	n.client = client
 	n.esClient = esClient
@@ -94,6 +100,7 @@ func (n *statefulSetNode) populateReference(nodeName string, node api.Elasticsea
 
 func (n *statefulSetNode) updateReference(desired NodeTypeInterface) {
 	n.self = desired.(*statefulSetNode).self
+	n.claimSpec = desired.(*statefulSetNode).claimSpec
 }
 
 func (n *statefulSetNode) scaleDown() error {
@@ -108,6 +115,10 @@ func (n *statefulSetNode) getSecretHash() string {
 	return n.secretHash
 }
 
+func (n *statefulSetNode) getCAHash() string {
+	return n.caHash
+}
+
 func (n *statefulSetNode) state() api.ElasticsearchNodeStatus {
 	var rolloutForUpdate v1.ConditionStatus
 	var rolloutForCertReload v1.ConditionStatus
@@ -124,6 +135,7 @@ func (n *statefulSetNode) state() api.ElasticsearchNodeStatus {
 		// the current hash -- we should have already had our upgradeStatus set if
 		// we required a restart...
 		n.secretHash = newSecretHash
+		n.caHash = getCADataHash(n.clusterName, n.self.Namespace, n.client)
 	} else {
 		// check if the secretHash changed
 		if newSecretHash != n.secretHash {
@@ -284,6 +296,8 @@ func (n *statefulSetNode) delete() error {
 }
 
 func (n *statefulSetNode) create() error {
+	createOrUpdateNodePersistentVolumeClaim(n.claimSpec, n.name(), n.self.Namespace, n.clusterName, n.client)
+
 	if n.self.ObjectMeta.ResourceVersion == "" {
 		err := n.client.Create(context.TODO(), &n.self)
 		if err != nil {
@@ -298,6 +312,7 @@ func (n *statefulSetNode) create() error {
 		// update the hashmaps
 		n.configmapHash = getConfigmapDataHash(n.clusterName, n.self.Namespace, n.client)
 		n.secretHash = getSecretDataHash(n.clusterName, n.self.Namespace, n.client)
+		n.caHash = getCADataHash(n.clusterName, n.self.Namespace, n.client)
 	} else {
 		n.scale()
 	}
@@ -339,6 +354,8 @@ func (n *statefulSetNode) refreshHashes() {
 	if newSecretHash != n.secretHash {
 		n.secretHash = newSecretHash
 	}
+
+	n.caHash = getCADataHash(n.clusterName, n.self.Namespace, n.client)
 }
 
 func (n *statefulSetNode) scale() {
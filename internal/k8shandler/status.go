@@ -211,11 +211,26 @@ func rolePodStateMap(namespace, clusterName string, client client.Client) map[ap
 		client,
 	)
 
-	return map[api.ElasticsearchNodeRole]api.PodStateMap{
+	ingestList, _ := GetPodList(
+		namespace,
+		map[string]string{
+			"component":      "elasticsearch",
+			"cluster-name":   clusterName,
+			"es-node-ingest": "true",
+		},
+		client,
+	)
+
+	podStates := map[api.ElasticsearchNodeRole]api.PodStateMap{
 		api.ElasticsearchRoleClient: podStateMap(clientList.Items),
 		api.ElasticsearchRoleData:   podStateMap(dataList.Items),
 		api.ElasticsearchRoleMaster: podStateMap(masterList.Items),
 	}
+	// only the clusters with dedicated ingest nodes report them
+	if len(ingestList.Items) > 0 {
+		podStates[api.ElasticsearchRoleIngest] = podStateMap(ingestList.Items)
+	}
+	return podStates
 }
 
 func podStateMap(podList []v1.Pod) api.PodStateMap {
@@ -281,17 +296,33 @@ func (er *ElasticsearchRequest) updateNodeConditions(status *api.ElasticsearchSt
 		return err
 	}
 
+	er.updateDrainingNodeConditions(status)
+
 	return nil
 }
 
+// updateDrainingNodeConditions marks the status of the removed data nodes which still hold shards
+func (er *ElasticsearchRequest) updateDrainingNodeConditions(status *api.ElasticsearchStatus) {
+	draining := map[string]bool{}
+	for _, node := range drainingNodes[nodeMapKey(er.cluster.GetName(), er.cluster.GetNamespace())] {
+		draining[node.name()] = true
+	}
+
+	for i := range status.Nodes {
+		nodeStatus := &status.Nodes[i]
+		updatePodDrainingCondition(nodeStatus, nodeStatus.DeploymentName != "" && draining[nodeStatus.DeploymentName])
+	}
+}
+
 func (er *ElasticsearchRequest) updateStorageConditions(status *api.ElasticsearchStatus) error {
 	ll := er.L()
 
-	emptySpecVol := api.ElasticsearchStorageSpec{}
-	structureStatus, nameStatus, sizeStatus := v1.ConditionFalse, v1.ConditionFalse, v1.ConditionFalse
+	structureStatus, nameStatus, sizeStatus, resizingStatus := v1.ConditionFalse, v1.ConditionFalse, v1.ConditionFalse, v1.ConditionFalse
 
 	nodeNames := []string{}
-	clusterNodes := nodes[nodeMapKey(er.cluster.GetName(), er.cluster.GetNamespace())]
+	key := nodeMapKey(er.cluster.GetName(), er.cluster.GetNamespace())
+	// the removed data nodes keep their status until they are drained and deleted
+	clusterNodes := append(append([]NodeTypeInterface{}, nodes[key]...), drainingNodes[key]...)
 
 	for _, node := range clusterNodes {
 		nodeNames = append(nodeNames, node.name())
@@ -305,59 +336,33 @@ func (er *ElasticsearchRequest) updateStorageConditions(status *api.Elasticsearc
 			continue
 		}
 
-		specVol := node.Storage
-		current := &v1.PersistentVolumeClaim{}
-		claimName := fmt.Sprintf("%s-%s", er.cluster.Name, nodeName)
-
-		isUsingPVCStorageSpec := true
-		isEphemeralStorageSpec := reflect.DeepEqual(specVol, emptySpecVol) || specVol.Size == nil
-
-		retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			if err := er.client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: er.cluster.Namespace}, current); err != nil {
-				if !apierrors.IsNotFound(err) {
-					return kverrors.Wrap(err, "failed to get PVC", "claim", claimName)
-				}
-				isUsingPVCStorageSpec = false
-			}
-
-			if isEphemeralStorageSpec && !isUsingPVCStorageSpec {
-				return nil
-			}
-
-			if !isEphemeralStorageSpec != isUsingPVCStorageSpec {
-				structureStatus = v1.ConditionTrue
-				return nil
-			}
-
-			// Generally, this won't last very long with the exception of the case of
-			// moving from ephermeral storage to a persistent one. Since the volume is
-			// never created after the intial create, a PVC should never remain in the
-			// the pending state for a long period of time with the exception of this case.
-			isPVCPending := reflect.DeepEqual(current.Status.Phase, v1.ClaimPending)
-			if isUsingPVCStorageSpec && isPVCPending {
-				structureStatus = v1.ConditionTrue
-				return nil
-			}
-
-			isDefaultName := specVol.StorageClassName == nil && current.Spec.StorageClassName != nil
-			if !isDefaultName && !reflect.DeepEqual(current.Spec.StorageClassName, specVol.StorageClassName) {
-				nameStatus = v1.ConditionTrue
-			}
-
-			currentSize := current.Spec.Resources.Requests.Storage()
-			if currentSize != nil && specVol.Size != nil {
-				if !currentSize.Equal(*specVol.Size) {
-					sizeStatus = v1.ConditionTrue
-				}
-			} else if currentSize != nil || specVol.Size != nil {
-				sizeStatus = v1.ConditionTrue
-			}
-
-			return nil
-		})
+		change := er.getStorageChange(node, nodeName)
+		if change.structure {
+			structureStatus = v1.ConditionTrue
+		}
+		if change.className {
+			nameStatus = v1.ConditionTrue
+		}
+		if change.size {
+			sizeStatus = v1.ConditionTrue
+		}
+		if change.resizing {
+			resizingStatus = v1.ConditionTrue
+		}
+	}
 
-		if retryErr != nil {
-			ll.Error(retryErr, "Unable to get PVC")
+	// the conditions of the status are the previous ones until they are updated below
+	for _, change := range []struct {
+		conditionType api.ClusterConditionType
+		status        v1.ConditionStatus
+		name          string
+	}{
+		{api.StorageStructure, structureStatus, "structure"},
+		{api.StorageClassName, nameStatus, "class name"},
+		{api.StorageSize, sizeStatus, "size"},
+	} {
+		if change.status == v1.ConditionTrue && !containsClusterCondition(change.conditionType, v1.ConditionTrue, status) {
+			er.recordEvent(v1.EventTypeWarning, eventReasonStorageChangeIgnored, "Ignoring the unsupported change of the storage %s", change.name)
 		}
 	}
 
@@ -382,12 +387,102 @@ func (er *ElasticsearchRequest) updateStorageConditions(status *api.Elasticsearc
 		Status:             sizeStatus,
 		LastTransitionTime: metav1.Now(),
 		Reason:             "StorageSizeChangeIgnored",
-		Message:            "Resizing the storage for a custom resource is not supported",
+		Message:            "Shrinking the storage, or expanding it with a storage class that does not allow volume expansion, is not supported",
+	})
+
+	updateESNodeCondition(status, &api.ClusterCondition{
+		Type:               api.StorageResizing,
+		Status:             resizingStatus,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "Resizing",
+		Message:            "Expanding the persistent volumes of the nodes",
 	})
 
 	return nil
 }
 
+// storageChange is the change of the storage of a node against its persistent volume claim
+type storageChange struct {
+	structure bool
+	className bool
+	size      bool
+	resizing  bool
+}
+
+// getStorageChange compares the storage of the spec node with the claim of the named node
+func (er *ElasticsearchRequest) getStorageChange(node api.ElasticsearchNode, nodeName string) storageChange {
+	emptySpecVol := api.ElasticsearchStorageSpec{}
+	change := storageChange{}
+
+	specVol := node.Storage
+	current := &v1.PersistentVolumeClaim{}
+	claimName := fmt.Sprintf("%s-%s", er.cluster.Name, nodeName)
+
+	isUsingPVCStorageSpec := true
+	isEphemeralStorageSpec := reflect.DeepEqual(specVol, emptySpecVol) || specVol.Size == nil
+
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: er.cluster.Namespace}, current); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return kverrors.Wrap(err, "failed to get PVC", "claim", claimName)
+			}
+			isUsingPVCStorageSpec = false
+		}
+
+		if isEphemeralStorageSpec && !isUsingPVCStorageSpec {
+			return nil
+		}
+
+		if !isEphemeralStorageSpec != isUsingPVCStorageSpec {
+			change.structure = true
+			return nil
+		}
+
+		// Generally, this won't last very long with the exception of the case of
+		// moving from ephermeral storage to a persistent one. Since the volume is
+		// never created after the intial create, a PVC should never remain in the
+		// the pending state for a long period of time with the exception of this case.
+		isPVCPending := reflect.DeepEqual(current.Status.Phase, v1.ClaimPending)
+		if isUsingPVCStorageSpec && isPVCPending {
+			change.structure = true
+			return nil
+		}
+
+		isDefaultName := specVol.StorageClassName == nil && current.Spec.StorageClassName != nil
+		if !isDefaultName && !reflect.DeepEqual(current.Spec.StorageClassName, specVol.StorageClassName) {
+			change.className = true
+		}
+
+		currentSize := current.Spec.Resources.Requests.Storage()
+		if currentSize != nil && specVol.Size != nil {
+			switch {
+			case specVol.Size.Cmp(*currentSize) < 0:
+				change.size = true
+			case isStorageExpansion(current, specVol.Size):
+				if isVolumeExpansionAllowed(current.Spec.StorageClassName, er.client) {
+					change.resizing = true
+				} else {
+					change.size = true
+				}
+			}
+		} else if currentSize != nil || specVol.Size != nil {
+			change.size = true
+		}
+
+		if isPersistentVolumeClaimResizing(current) {
+			change.resizing = true
+		}
+
+		return nil
+	})
+
+	if retryErr != nil {
+		er.L().Error(retryErr, "Unable to get PVC")
+	}
+
+	return change
+}
+
 func nodeNameContains(uuid *string, names []string) string {
 	nodeName := ""
 
@@ -409,7 +504,9 @@ func (er *ElasticsearchRequest) pruneMissingNodes(status *api.ElasticsearchStatu
 	cluster := er.cluster
 	ll := er.L()
 
-	clusterNodes := nodes[nodeMapKey(er.cluster.GetName(), er.cluster.GetNamespace())]
+	key := nodeMapKey(er.cluster.GetName(), er.cluster.GetNamespace())
+	// the removed data nodes keep their status until they are drained and deleted
+	clusterNodes := append(append([]NodeTypeInterface{}, nodes[key]...), drainingNodes[key]...)
 
 	ns := status.Nodes[:0]
 	for _, nodeStatus := range status.Nodes {
@@ -796,6 +893,24 @@ func updatePodNotReadyCondition(node *api.ElasticsearchNodeStatus, conditionType
 	})
 }
 
+func updatePodDrainingCondition(node *api.ElasticsearchNodeStatus, draining bool) bool {
+	if !draining {
+		return updatePodCondition(node, &api.ClusterCondition{Type: api.ScalingDown, Status: v1.ConditionFalse})
+	}
+
+	transitionTime := metav1.Now()
+	if _, condition := getPodCondition(node, api.ScalingDown); condition != nil {
+		transitionTime = condition.LastTransitionTime
+	}
+	return updatePodCondition(node, &api.ClusterCondition{
+		Type:               api.ScalingDown,
+		Status:             v1.ConditionTrue,
+		Reason:             "Draining",
+		Message:            "Moving the shards to the other nodes before deleting the node removed from the spec",
+		LastTransitionTime: transitionTime,
+	})
+}
+
 func updatePodNodeStorageCondition(node *api.ElasticsearchNodeStatus, reason, message string) bool {
 	var status v1.ConditionStatus
 	if message == "" && reason == "" {
@@ -969,6 +1084,24 @@ func updateInvalidDataCountCondition(status *api.ElasticsearchStatus, value v1.C
 	})
 }
 
+func updateInvalidRolesCondition(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+	var message string
+	var reason string
+	if value == v1.ConditionTrue {
+		message = "Invalid node roles. Please ensure nodes with the coordinating role have no master, data or ingest roles"
+		reason = "Invalid Settings"
+	} else {
+		message = ""
+		reason = ""
+	}
+	return updateESNodeCondition(status, &api.ClusterCondition{
+		Type:    api.InvalidRoles,
+		Status:  value,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
 func updateInvalidUUIDChangeCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
 	var reason string
 	if value == v1.ConditionTrue {
@@ -992,6 +1125,52 @@ func updateInvalidUUIDChangeCondition(cluster *api.Elasticsearch, value v1.Condi
 	)
 }
 
+func updateInvalidNodeAttributesCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Spec"
+	} else {
+		reason = ""
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(&cluster.Status, &api.ClusterCondition{
+				Type:    api.InvalidNodeAttributes,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateInvalidCertificatesCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Invalid Spec"
+	} else {
+		reason = ""
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(&cluster.Status, &api.ClusterCondition{
+				Type:    api.InvalidCertificates,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
 func updateInvalidReplicationCondition(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
 	var message string
 	var reason string
@@ -1071,6 +1250,48 @@ func updateScalingDownCondition(status *api.ElasticsearchStatus, value v1.Condit
 	})
 }
 
+func updateScalingDownConditionWithMessage(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Draining Nodes"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.ScalingDown,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
+func updateMaintenancePendingCondition(cluster *api.Elasticsearch, value v1.ConditionStatus, message string, client client.Client) error {
+	var reason string
+	if value == v1.ConditionTrue {
+		reason = "Outside Maintenance Window"
+	}
+
+	return updateConditionWithRetry(
+		cluster,
+		value,
+		func(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
+			return updateESNodeCondition(status, &api.ClusterCondition{
+				Type:    api.MaintenancePending,
+				Status:  value,
+				Reason:  reason,
+				Message: message,
+			})
+		},
+		client,
+	)
+}
+
 func updateRestartingCondition(status *api.ElasticsearchStatus, value v1.ConditionStatus) bool {
 	return updateESNodeCondition(status, &api.ClusterCondition{
 		Type:   api.Restarting,
@@ -192,6 +192,19 @@ func isValidDataCount(dpl *api.Elasticsearch) bool {
 	return dataCount > 0
 }
 
+// isValidRoles returns whether the roles of every node can be combined. Coordinating nodes only
+// serve HTTP requests, the client role is implied
+func isValidRoles(dpl *api.Elasticsearch) bool {
+	for _, node := range dpl.Spec.Nodes {
+		roleMap := getNodeRoleMap(node)
+		if roleMap[api.ElasticsearchRoleCoordinating] &&
+			(roleMap[api.ElasticsearchRoleMaster] || roleMap[api.ElasticsearchRoleData] || roleMap[api.ElasticsearchRoleIngest]) {
+			return false
+		}
+	}
+	return true
+}
+
 func isValidRedundancyPolicy(dpl *api.Elasticsearch) bool {
 	dataCount := int(getDataCount(dpl))
 
@@ -250,6 +263,18 @@ func (er *ElasticsearchRequest) isValidScaleDownRate() (bool, error) {
 func (er *ElasticsearchRequest) isValidConf() error {
 	dpl := er.cluster
 
+	// invalid certificate lifetimes do not stop the reconcile, the defaults are used instead
+	if reasons := validateCertificates(dpl.Spec.Certificates); len(reasons) > 0 {
+		message := fmt.Sprintf("Using the default lifetimes of the certificates: %s", strings.Join(reasons, "; "))
+		if err := updateInvalidCertificatesCondition(dpl, v1.ConditionTrue, message, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set certificates status")
+		}
+	} else {
+		if err := updateInvalidCertificatesCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set certificates status")
+		}
+	}
+
 	if !isValidMasterCount(dpl) {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidMasterCountCondition, er.client); err != nil {
 			return err
@@ -273,6 +298,17 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		}
 	}
 
+	if !isValidRoles(dpl) {
+		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidRolesCondition, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set roles status")
+		}
+		return kverrors.New("invalid node roles. Please ensure nodes with the coordinating role have no master, data or ingest roles")
+	} else {
+		if err := updateConditionWithRetry(dpl, v1.ConditionFalse, updateInvalidRolesCondition, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set roles status")
+		}
+	}
+
 	if !isValidRedundancyPolicy(dpl) {
 		if err := updateConditionWithRetry(dpl, v1.ConditionTrue, updateInvalidReplicationCondition, er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set replication status")
@@ -285,6 +321,18 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		}
 	}
 
+	if reasons := validateNodeAttributes(dpl); len(reasons) > 0 {
+		message := strings.Join(reasons, "; ")
+		if err := updateInvalidNodeAttributesCondition(dpl, v1.ConditionTrue, message, er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set node attributes status")
+		}
+		return kverrors.New("invalid node attributes", "reasons", message)
+	} else {
+		if err := updateInvalidNodeAttributesCondition(dpl, v1.ConditionFalse, "", er.client); err != nil {
+			return kverrors.Wrap(err, "failed to set node attributes status")
+		}
+	}
+
 	isValid, err := er.isValidScaleDownRate()
 	if err != nil {
 		return err
@@ -301,7 +349,7 @@ func (er *ElasticsearchRequest) isValidConf() error {
 		return kverrors.New("Data node scale down rate is too high based on minimum number of replicas for all indices")
 	}
 
-	// TODO: replace this with a validating web hook to ensure field is immutable
+	// UUID changes are rejected by the validating webhook, unless it is disabled
 	if err := validateUUIDs(dpl); err != nil {
 		if err := updateInvalidUUIDChangeCondition(dpl, v1.ConditionTrue, err.Error(), er.client); err != nil {
 			return kverrors.Wrap(err, "failed to set UUID change status")
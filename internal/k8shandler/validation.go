@@ -0,0 +1,142 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ViaQ/logerr/log"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement"
+)
+
+// ValidateElasticsearch returns the reasons why the reconciler would refuse to
+// act on the desired spec. The current object is nil when the custom resource is
+// being created. The esClient is only used to determine the scale down rate the
+// cluster can tolerate.
+func ValidateElasticsearch(current, desired *api.Elasticsearch, esClient elasticsearch.Client) []string {
+	var reasons []string
+
+	if !isValidMasterCount(desired) {
+		reasons = append(reasons, fmt.Sprintf("Invalid master nodes count. Please ensure there are no more than %v total nodes with master roles", maxMasterCount))
+	}
+	if !isValidDataCount(desired) {
+		reasons = append(reasons, "No data nodes requested. Please ensure there is at least 1 node with data roles")
+	}
+	if !isValidRoles(desired) {
+		reasons = append(reasons, "Invalid node roles. Please ensure nodes with the coordinating role have no master, data or ingest roles")
+	}
+	if !isValidRedundancyPolicy(desired) {
+		reasons = append(reasons, fmt.Sprintf("Wrong RedundancyPolicy selected '%s'. Choose different RedundancyPolicy or add more nodes with data roles", desired.Spec.RedundancyPolicy))
+	}
+	reasons = append(reasons, validateIndexManagement(desired)...)
+	reasons = append(reasons, validateNodeAttributes(desired)...)
+	if desired.Spec.Snapshot != nil {
+		reasons = append(reasons, validateSnapshot(desired.Spec.Snapshot)...)
+	}
+	if desired.Spec.Restore != nil {
+		reasons = append(reasons, validateRestore(desired)...)
+	}
+	if desired.Spec.Certificates != nil {
+		reasons = append(reasons, validateCertificates(desired.Spec.Certificates)...)
+	}
+	reasons = append(reasons, validateMaintenanceWindows(desired.Spec.MaintenanceWindows)...)
+	if desired.Spec.Security != nil {
+		reasons = append(reasons, validateSecurity(desired.Spec.Security)...)
+	}
+	reasons = append(reasons, validateIndexTemplates(desired.Spec.IndexTemplates)...)
+	reasons = append(reasons, validateIngestPipelines(desired)...)
+
+	if current == nil {
+		return reasons
+	}
+
+	// the status of the request object can not be trusted, use the one known to the cluster
+	withStatus := desired.DeepCopy()
+	withStatus.Status = current.Status
+	if err := validateUUIDs(withStatus); err != nil {
+		reasons = append(reasons, "Previously used GenUUID can not be removed from or changed in Spec.Nodes")
+	}
+	reasons = append(reasons, validateStorageChanges(current, desired)...)
+
+	if !isValidScaleDownRequest(current, desired, esClient) {
+		reasons = append(reasons, "Data node scale down rate is too high based on minimum number of replicas for all indices")
+	}
+
+	return reasons
+}
+
+// validateIndexManagement returns the messages of the conditions that would cause
+// an index management policy or mapping to be dropped
+func validateIndexManagement(desired *api.Elasticsearch) []string {
+	cluster := desired.DeepCopy()
+	indexmanagement.VerifyAndNormalize(cluster)
+
+	var reasons []string
+	for _, policy := range cluster.Status.IndexManagementStatus.Policies {
+		for _, condition := range policy.Conditions {
+			reasons = append(reasons, fmt.Sprintf("IndexManagement policy '%s': %s", policy.Name, condition.Message))
+		}
+	}
+	for _, mapping := range cluster.Status.IndexManagementStatus.Mappings {
+		for _, condition := range mapping.Conditions {
+			reasons = append(reasons, fmt.Sprintf("IndexManagement mapping '%s': %s", mapping.Name, condition.Message))
+		}
+	}
+	return reasons
+}
+
+// validateStorageChanges returns the reasons why changes to the storage of existing
+// nodes would be ignored by the reconciler
+func validateStorageChanges(current, desired *api.Elasticsearch) []string {
+	emptySpecVol := api.ElasticsearchStorageSpec{}
+
+	var reasons []string
+	for _, node := range desired.Spec.Nodes {
+		if node.GenUUID == nil {
+			continue
+		}
+		for _, currentNode := range current.Spec.Nodes {
+			if currentNode.GenUUID == nil || *currentNode.GenUUID != *node.GenUUID {
+				continue
+			}
+
+			specVol, currentVol := node.Storage, currentNode.Storage
+			isEphemeral := reflect.DeepEqual(specVol, emptySpecVol) || specVol.Size == nil
+			wasEphemeral := reflect.DeepEqual(currentVol, emptySpecVol) || currentVol.Size == nil
+
+			if isEphemeral != wasEphemeral {
+				reasons = append(reasons, fmt.Sprintf("Changing the storage structure of node %s is not supported", *node.GenUUID))
+				continue
+			}
+			if isEphemeral {
+				continue
+			}
+			if !reflect.DeepEqual(specVol.StorageClassName, currentVol.StorageClassName) {
+				reasons = append(reasons, fmt.Sprintf("Changing the storage class name of node %s is not supported", *node.GenUUID))
+			}
+			if specVol.Size.Cmp(*currentVol.Size) < 0 {
+				reasons = append(reasons, fmt.Sprintf("Shrinking the storage of node %s is not supported", *node.GenUUID))
+			}
+		}
+	}
+	return reasons
+}
+
+// isValidScaleDownRequest mirrors isValidScaleDownRate using the data node count
+// of the current spec. The request is allowed if the lowest replica value can not
+// be determined, since the reconciler will still refuse to scale down too quickly
+func isValidScaleDownRequest(current, desired *api.Elasticsearch, esClient elasticsearch.Client) bool {
+	rate := getDataCount(current) - getDataCount(desired)
+	if rate <= 0 || esClient == nil {
+		return true
+	}
+
+	lowestReplica, err := esClient.GetLowestReplicaValue()
+	if err != nil {
+		log.Error(err, "Unable to determine lowest replica value for cluster", "cluster", desired.Name, "namespace", desired.Namespace)
+		return true
+	}
+	return rate <= lowestReplica
+}
@@ -0,0 +1,128 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+var _ = Describe("validation.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		current  *api.Elasticsearch
+		desired  *api.Elasticsearch
+		uuid     = "abc123"
+		size     = resource.MustParse("10Gi")
+		esClient elasticsearch.Client
+	)
+
+	BeforeEach(func() {
+		current = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				RedundancyPolicy: api.SingleRedundancy,
+				Nodes: []api.ElasticsearchNode{
+					{
+						Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleMaster, api.ElasticsearchRoleData, api.ElasticsearchRoleClient},
+						NodeCount: 3,
+						GenUUID:   &uuid,
+						Storage:   api.ElasticsearchStorageSpec{Size: &size},
+					},
+				},
+			},
+			Status: api.ElasticsearchStatus{
+				Nodes: []api.ElasticsearchNodeStatus{
+					{DeploymentName: "elasticsearch-cdm-abc123-1"},
+				},
+			},
+		}
+		desired = current.DeepCopy()
+		desired.Status = api.ElasticsearchStatus{}
+		chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+			"app-*,infra-*,audit-*/_settings/index.number_of_replicas": {
+				{
+					StatusCode: 200,
+					Body:       `{"app-000001":{"settings":{"index":{"number_of_replicas":"1"}}}}`,
+				},
+			},
+		})
+		esClient = helpers.NewFakeElasticsearchClient("elasticsearch", "openshift-logging", nil, chatter)
+	})
+
+	Describe("#ValidateElasticsearch", func() {
+		It("should accept a valid custom resource", func() {
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(BeEmpty())
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(BeEmpty())
+		})
+		It("should reject too many master nodes", func() {
+			desired.Spec.Nodes[0].NodeCount = 4
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(ConsistOf(ContainSubstring("Invalid master nodes count")))
+		})
+		It("should reject coordinating nodes with other roles", func() {
+			desired.Spec.Nodes = append(desired.Spec.Nodes, api.ElasticsearchNode{
+				Roles:     []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating, api.ElasticsearchRoleIngest},
+				NodeCount: 1,
+			})
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(ConsistOf(ContainSubstring("Invalid node roles")))
+		})
+		It("should accept dedicated ingest and coordinating nodes", func() {
+			desired.Spec.Nodes = append(desired.Spec.Nodes,
+				api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleIngest}, NodeCount: 1},
+				api.ElasticsearchNode{Roles: []api.ElasticsearchNodeRole{api.ElasticsearchRoleCoordinating}, NodeCount: 1},
+			)
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(BeEmpty())
+		})
+		It("should reject a redundancy policy the data nodes can not support", func() {
+			desired.Spec.Nodes[0].NodeCount = 1
+			desired.Spec.RedundancyPolicy = api.MultipleRedundancy
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(ConsistOf(ContainSubstring("Wrong RedundancyPolicy")))
+		})
+		It("should reject an invalid index management policy", func() {
+			desired.Spec.IndexManagement = &api.IndexManagementSpec{
+				Policies: []api.IndexManagementPolicySpec{
+					{Name: "foo", PollInterval: "1x"},
+				},
+			}
+			Expect(ValidateElasticsearch(nil, desired, esClient)).To(ConsistOf(ContainSubstring("IndexManagement policy 'foo'")))
+		})
+		It("should reject a removed GenUUID", func() {
+			other := "def456"
+			desired.Spec.Nodes[0].GenUUID = &other
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(ContainElement(ContainSubstring("Previously used GenUUID")))
+		})
+		It("should reject storage changes for existing nodes", func() {
+			smaller := resource.MustParse("5Gi")
+			className := "gp2"
+			desired.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{Size: &smaller, StorageClassName: &className}
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(ConsistOf(
+				ContainSubstring("storage class name"),
+				ContainSubstring("Shrinking the storage"),
+			))
+		})
+		It("should allow expanding the storage of existing nodes", func() {
+			larger := resource.MustParse("20Gi")
+			desired.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{Size: &larger}
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(BeEmpty())
+		})
+		It("should reject changing the storage structure for existing nodes", func() {
+			desired.Spec.Nodes[0].Storage = api.ElasticsearchStorageSpec{}
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(ConsistOf(ContainSubstring("storage structure")))
+		})
+		It("should allow scaling down as many data nodes as the lowest replica value", func() {
+			desired.Spec.Nodes[0].NodeCount = 2
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(BeEmpty())
+		})
+		It("should reject scaling down more data nodes than the lowest replica value", func() {
+			desired.Spec.Nodes[0].NodeCount = 1
+			desired.Spec.RedundancyPolicy = api.ZeroRedundancy
+			Expect(ValidateElasticsearch(current, desired, esClient)).To(ConsistOf(ContainSubstring("scale down rate is too high")))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+const (
+	// zoneAnnotation holds the zone of the node an Elasticsearch pod is scheduled on
+	zoneAnnotation         = "elasticsearch.openshift.io/zone"
+	defaultZoneTopologyKey = "topology.kubernetes.io/zone"
+	zoneEnvVar             = "ZONE"
+	zoneVolumeName         = "elasticsearch-topology"
+	zoneMountPath          = "/etc/elasticsearch/topology"
+	zoneInitContainerName  = "wait-for-zone"
+)
+
+// AnnotatePodZones copies the zone of the node each scheduled Elasticsearch pod runs on to
+// the pod. The pods wait for the annotation before starting Elasticsearch
+func (er *ElasticsearchRequest) AnnotatePodZones() error {
+	cluster := er.cluster
+	spec := cluster.Spec.Spec.ZoneAwareness
+	if spec == nil {
+		return nil
+	}
+	topologyKey := zoneTopologyKey(spec)
+
+	pods, err := GetPodList(cluster.Namespace, map[string]string{
+		"component":    "elasticsearch",
+		"cluster-name": cluster.Name,
+	}, er.client)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Annotations[zoneAnnotation] != "" {
+			continue
+		}
+
+		node := &v1.Node{}
+		if err := er.client.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			er.L().Error(err, "Unable to get the node of pod", "pod", pod.Name, "node", pod.Spec.NodeName)
+			continue
+		}
+		zone, ok := node.Labels[topologyKey]
+		if !ok || zone == "" {
+			er.L().Info("Node is missing the zone label. The pod waits until it is added", "pod", pod.Name, "node", node.Name, "label", topologyKey)
+			continue
+		}
+
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[zoneAnnotation] = zone
+		if err := er.client.Update(context.TODO(), pod); err != nil {
+			er.L().Error(err, "Unable to annotate the zone of pod", "pod", pod.Name)
+		}
+	}
+	return nil
+}
+
+func zoneTopologyKey(spec *api.ZoneAwarenessSpec) string {
+	if spec.TopologyKey != "" {
+		return spec.TopologyKey
+	}
+	return defaultZoneTopologyKey
+}
+
+// newZoneEnvVar exposes the annotated zone of the pod to elasticsearch.yml
+func newZoneEnvVar() v1.EnvVar {
+	return v1.EnvVar{
+		Name: zoneEnvVar,
+		ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{
+				FieldPath: fmt.Sprintf("metadata.annotations['%s']", zoneAnnotation),
+			},
+		},
+	}
+}
+
+// newZoneVolume exposes the annotated zone of the pod as a file, which unlike environment
+// variables is updated once the pod is annotated
+func newZoneVolume() v1.Volume {
+	return v1.Volume{
+		Name: zoneVolumeName,
+		VolumeSource: v1.VolumeSource{
+			DownwardAPI: &v1.DownwardAPIVolumeSource{
+				Items: []v1.DownwardAPIVolumeFile{
+					{
+						Path: "zone",
+						FieldRef: &v1.ObjectFieldSelector{
+							FieldPath: fmt.Sprintf("metadata.annotations['%s']", zoneAnnotation),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newZoneInitContainer waits for the pod to be annotated with its zone, so the zone is
+// set when the Elasticsearch container is started
+func newZoneInitContainer(imageName string) v1.Container {
+	return v1.Container{
+		Name:            zoneInitContainerName,
+		Image:           imageName,
+		ImagePullPolicy: "IfNotPresent",
+		Command: []string{
+			"/bin/bash",
+			"-c",
+			fmt.Sprintf("until [ -s %[1]s/zone ]; do echo 'Waiting for the zone of the node'; sleep 2; done; echo \"Zone: $(cat %[1]s/zone)\"", zoneMountPath),
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("10m"),
+				v1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      zoneVolumeName,
+				MountPath: zoneMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+}
+
+// newTopologySpreadConstraints spreads the nodes with the same roles across zones
+func newTopologySpreadConstraints(clusterName string, roleMap map[api.ElasticsearchNodeRole]bool, spec *api.ZoneAwarenessSpec) []v1.TopologySpreadConstraint {
+	maxSkew := spec.MaxSkew
+	if maxSkew == 0 {
+		maxSkew = 1
+	}
+	whenUnsatisfiable := spec.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = v1.ScheduleAnyway
+	}
+
+	return []v1.TopologySpreadConstraint{
+		{
+			MaxSkew:           maxSkew,
+			TopologyKey:       zoneTopologyKey(spec),
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"cluster-name": clusterName,
+				},
+				MatchExpressions: newRoleLabelSelectorRequirements(roleMap),
+			},
+		},
+	}
+}
+
+// forcedZones returns the forced awareness values for elasticsearch.yml
+func forcedZones(spec *api.ZoneAwarenessSpec) string {
+	return strings.Join(spec.Zones, ",")
+}
@@ -0,0 +1,114 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+)
+
+var _ = Describe("zones.go", func() {
+	defer GinkgoRecover()
+
+	var (
+		cluster   *api.Elasticsearch
+		k8sClient client.Client
+		request   *ElasticsearchRequest
+		podKey    = types.NamespacedName{Name: "elasticsearch-cdm-1-abc", Namespace: "openshift-logging"}
+	)
+
+	BeforeEach(func() {
+		cluster = &api.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+			Spec: api.ElasticsearchSpec{
+				Spec: api.ElasticsearchNodeSpec{
+					ZoneAwareness: &api.ZoneAwarenessSpec{},
+				},
+			},
+		}
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podKey.Name,
+				Namespace: podKey.Namespace,
+				Labels: map[string]string{
+					"component":    "elasticsearch",
+					"cluster-name": "elasticsearch",
+				},
+			},
+			Spec: v1.PodSpec{NodeName: "worker-1"},
+		}
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "worker-1",
+				Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+			},
+		}
+		k8sClient = fake.NewFakeClient(pod, node)
+		request = &ElasticsearchRequest{client: k8sClient, cluster: cluster}
+	})
+
+	Describe("#AnnotatePodZones", func() {
+		It("should annotate scheduled pods with the zone of their node", func() {
+			Expect(request.AnnotatePodZones()).To(Succeed())
+
+			pod := &v1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), podKey, pod)).To(Succeed())
+			Expect(pod.Annotations).To(HaveKeyWithValue(zoneAnnotation, "us-east-1a"))
+		})
+		It("should leave the pods when the node is missing the topology key", func() {
+			cluster.Spec.Spec.ZoneAwareness.TopologyKey = "failure-domain.beta.kubernetes.io/zone"
+			Expect(request.AnnotatePodZones()).To(Succeed())
+
+			pod := &v1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), podKey, pod)).To(Succeed())
+			Expect(pod.Annotations).ToNot(HaveKey(zoneAnnotation))
+		})
+		It("should not annotate pods without zone awareness", func() {
+			cluster.Spec.Spec.ZoneAwareness = nil
+			Expect(request.AnnotatePodZones()).To(Succeed())
+
+			pod := &v1.Pod{}
+			Expect(k8sClient.Get(context.TODO(), podKey, pod)).To(Succeed())
+			Expect(pod.Annotations).ToNot(HaveKey(zoneAnnotation))
+		})
+	})
+
+	Describe("#newPodTemplateSpec", func() {
+		It("should spread the nodes with the same roles across zones", func() {
+			roleMap := map[api.ElasticsearchNodeRole]bool{api.ElasticsearchRoleData: true}
+			spec := newPodTemplateSpec("elasticsearch-cdm-1", "elasticsearch", "openshift-logging", api.ElasticsearchNode{}, cluster.Spec.Spec, nil, nil, nil, map[string]string{}, roleMap, LogConfig{}).Spec
+
+			Expect(spec.InitContainers).To(HaveLen(1))
+			Expect(spec.InitContainers[0].Name).To(Equal(zoneInitContainerName))
+			Expect(spec.Containers[0].Env).To(ContainElement(newZoneEnvVar()))
+			Expect(spec.Volumes).To(ContainElement(newZoneVolume()))
+			Expect(spec.TopologySpreadConstraints).To(Equal([]v1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       "topology.kubernetes.io/zone",
+					WhenUnsatisfiable: v1.ScheduleAnyway,
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"cluster-name": "elasticsearch"},
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "es-node-data", Operator: metav1.LabelSelectorOpIn, Values: []string{"true"}},
+						},
+					},
+				},
+			}))
+		})
+		It("should not change the pods without zone awareness", func() {
+			spec := newPodTemplateSpec("elasticsearch-cdm-1", "elasticsearch", "openshift-logging", api.ElasticsearchNode{}, api.ElasticsearchNodeSpec{}, nil, nil, nil, map[string]string{}, nil, LogConfig{}).Spec
+
+			Expect(spec.InitContainers).To(BeEmpty())
+			Expect(spec.TopologySpreadConstraints).To(BeNil())
+		})
+	})
+})
@@ -1,6 +1,13 @@
 package metrics
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -26,12 +33,175 @@ var (
 			Help: "Number of Elasticsearch cluster that are in Managed state or Unmanaged state.",
 		}, []string{"state"})
 
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "eo_elasticsearch_cr_reconcile_duration_seconds",
+			Help:    "Duration of the reconciliation of an Elasticsearch cluster.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"namespace", "cluster"})
+
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "eo_elasticsearch_cr_reconcile_errors_total",
+			Help: "Total number of reconciliations of an Elasticsearch cluster that failed.",
+		}, []string{"namespace", "cluster"})
+
+	esRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "eo_elasticsearch_api_request_duration_seconds",
+			Help:    "Duration of the requests of the operator to the Elasticsearch API by method and status code, which is 0 if no response was received.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"namespace", "cluster", "method", "code"})
+
+	nodeUpgradePhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_node_upgrade_phase",
+			Help: "Upgrade phase of an Elasticsearch node, 1 for the current phase and 0 for the others.",
+		}, []string{"namespace", "cluster", "node", "phase"})
+
+	certificateExpiry = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_certificate_expiry_timestamp_seconds",
+			Help: "Time in seconds since the epoch at which a certificate of the Elasticsearch cluster expires.",
+		}, []string{"namespace", "cluster", "certificate"})
+
+	indexManagementLastRun = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_index_management_last_run_timestamp_seconds",
+			Help: "Time in seconds since the epoch at which the last index management job of a mapping finished.",
+		}, []string{"namespace", "cluster", "mapping"})
+
+	indexManagementLastRunSucceeded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_index_management_last_run_succeeded",
+			Help: "Whether the last index management job of a mapping succeeded (1) or failed (0).",
+		}, []string{"namespace", "cluster", "mapping"})
+
+	indexManagementFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_index_management_consecutive_failures",
+			Help: "Number of index management jobs of a mapping which failed since the last successful job.",
+		}, []string{"namespace", "cluster", "mapping"})
+
+	clusterCondition = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "eo_elasticsearch_cr_condition",
+			Help: "Status of a condition of an Elasticsearch cluster, 1 if True and 0 otherwise.",
+		}, []string{"namespace", "cluster", "type"})
+
 	metricList = []prometheus.Collector{
 		restartCounter,
 		esClusterManagementState,
+		reconcileDuration,
+		reconcileErrors,
+		esRequestDuration,
+		nodeUpgradePhase,
+		certificateExpiry,
+		indexManagementLastRun,
+		indexManagementLastRunSucceeded,
+		indexManagementFailures,
+		clusterCondition,
+	}
+
+	// clusterVecs are the metrics with series for each cluster
+	clusterVecs = []deleter{
+		reconcileDuration,
+		reconcileErrors,
+		esRequestDuration,
+		nodeUpgradePhase,
+		certificateExpiry,
+		indexManagementLastRun,
+		indexManagementLastRunSucceeded,
+		indexManagementFailures,
+		clusterCondition,
 	}
 )
 
+// UpgradePhases are the upgrade phases of a node reported by eo_elasticsearch_node_upgrade_phase
+var UpgradePhases = []string{"controllerUpdated", "preparationComplete", "nodeRestarting", "recoveringData"}
+
+type deleter interface {
+	Delete(prometheus.Labels) bool
+}
+
+// clusterSeries holds the labels of the series of each cluster, the client library being unable
+// to delete series by a subset of their labels
+var clusterSeries = struct {
+	sync.Mutex
+	byCluster map[string]map[deleter]map[string]prometheus.Labels
+}{byCluster: map[string]map[deleter]map[string]prometheus.Labels{}}
+
+func clusterKey(namespace, cluster string) string {
+	return fmt.Sprintf("%s/%s", namespace, cluster)
+}
+
+func seriesKey(labels prometheus.Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+labels[name])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// clusterLabels returns the labels of a series of the cluster and records them
+func clusterLabels(vec deleter, namespace, cluster string, labels prometheus.Labels) prometheus.Labels {
+	series := prometheus.Labels{"namespace": namespace, "cluster": cluster}
+	for name, value := range labels {
+		series[name] = value
+	}
+
+	clusterSeries.Lock()
+	defer clusterSeries.Unlock()
+	key := clusterKey(namespace, cluster)
+	if clusterSeries.byCluster[key] == nil {
+		clusterSeries.byCluster[key] = map[deleter]map[string]prometheus.Labels{}
+	}
+	if clusterSeries.byCluster[key][vec] == nil {
+		clusterSeries.byCluster[key][vec] = map[string]prometheus.Labels{}
+	}
+	clusterSeries.byCluster[key][vec][seriesKey(series)] = series
+	return series
+}
+
+// setClusterGauges sets the series of the gauge for the cluster and deletes its other series,
+// which no longer describe the cluster
+func setClusterGauges(vec *prometheus.GaugeVec, namespace, cluster string, values map[string]float64, labelsOf func(string) prometheus.Labels) {
+	current := map[string]bool{}
+	for id, value := range values {
+		labels := clusterLabels(vec, namespace, cluster, labelsOf(id))
+		vec.With(labels).Set(value)
+		current[seriesKey(labels)] = true
+	}
+
+	clusterSeries.Lock()
+	defer clusterSeries.Unlock()
+	recorded := clusterSeries.byCluster[clusterKey(namespace, cluster)][vec]
+	for key, labels := range recorded {
+		if !current[key] {
+			vec.Delete(labels)
+			delete(recorded, key)
+		}
+	}
+}
+
+// DeleteClusterMetrics deletes the series of a cluster which no longer exists
+func DeleteClusterMetrics(namespace, cluster string) {
+	clusterSeries.Lock()
+	defer clusterSeries.Unlock()
+	key := clusterKey(namespace, cluster)
+	for vec, series := range clusterSeries.byCluster[key] {
+		for _, labels := range series {
+			vec.Delete(labels)
+		}
+	}
+	delete(clusterSeries.byCluster, key)
+}
+
 // This function registers the custom metrics to the kubernetes controller-runtime default metrics.
 func RegisterCustomMetrics() {
 	for _, metric := range metricList {
@@ -81,3 +251,89 @@ func SetEsClusterManagementStateUnmanaged() {
 		"state": UnmanagedState,
 	}).Set(1)
 }
+
+// Observes the duration of a reconciliation of the cluster and counts it as an error if it failed.
+func ObserveReconcile(namespace, cluster string, duration time.Duration, err error) {
+	reconcileDuration.With(clusterLabels(reconcileDuration, namespace, cluster, nil)).Observe(duration.Seconds())
+	if err != nil {
+		reconcileErrors.With(clusterLabels(reconcileErrors, namespace, cluster, nil)).Inc()
+	}
+}
+
+// Observes the duration of a request to the Elasticsearch API of the cluster.
+func ObserveElasticsearchRequest(namespace, cluster, method string, statusCode int, duration time.Duration) {
+	labels := clusterLabels(esRequestDuration, namespace, cluster, prometheus.Labels{
+		"method": method,
+		"code":   strconv.Itoa(statusCode),
+	})
+	esRequestDuration.With(labels).Observe(duration.Seconds())
+}
+
+// Sets the upgrade phase of every node of the cluster, keyed by the node name.
+func SetNodeUpgradePhases(namespace, cluster string, phases map[string]string) {
+	values := map[string]float64{}
+	for node, current := range phases {
+		for _, phase := range UpgradePhases {
+			value := 0.0
+			if phase == current {
+				value = 1
+			}
+			values[node+"/"+phase] = value
+		}
+	}
+	setClusterGauges(nodeUpgradePhase, namespace, cluster, values, func(id string) prometheus.Labels {
+		parts := strings.SplitN(id, "/", 2)
+		return prometheus.Labels{"node": parts[0], "phase": parts[1]}
+	})
+}
+
+// Sets the expiry of the certificates of the cluster, keyed by the certificate name.
+func SetCertificateExpiries(namespace, cluster string, expiries map[string]time.Time) {
+	values := map[string]float64{}
+	for name, notAfter := range expiries {
+		values[name] = float64(notAfter.Unix())
+	}
+	setClusterGauges(certificateExpiry, namespace, cluster, values, func(name string) prometheus.Labels {
+		return prometheus.Labels{"certificate": name}
+	})
+}
+
+// IndexManagementRun is the outcome of the last index management job of a mapping
+type IndexManagementRun struct {
+	Finished            time.Time
+	Succeeded           bool
+	ConsecutiveFailures int32
+}
+
+// Sets the outcome of the last index management job of every mapping of the cluster, keyed by the mapping name.
+func SetIndexManagementRuns(namespace, cluster string, runs map[string]IndexManagementRun) {
+	finished, succeeded, failures := map[string]float64{}, map[string]float64{}, map[string]float64{}
+	for mapping, run := range runs {
+		finished[mapping] = float64(run.Finished.Unix())
+		succeeded[mapping] = 0
+		if run.Succeeded {
+			succeeded[mapping] = 1
+		}
+		failures[mapping] = float64(run.ConsecutiveFailures)
+	}
+	mappingLabels := func(mapping string) prometheus.Labels {
+		return prometheus.Labels{"mapping": mapping}
+	}
+	setClusterGauges(indexManagementLastRun, namespace, cluster, finished, mappingLabels)
+	setClusterGauges(indexManagementLastRunSucceeded, namespace, cluster, succeeded, mappingLabels)
+	setClusterGauges(indexManagementFailures, namespace, cluster, failures, mappingLabels)
+}
+
+// Sets the status of the conditions of the cluster, keyed by the condition type.
+func SetClusterConditions(namespace, cluster string, conditions map[string]bool) {
+	values := map[string]float64{}
+	for conditionType, isTrue := range conditions {
+		values[conditionType] = 0
+		if isTrue {
+			values[conditionType] = 1
+		}
+	}
+	setClusterGauges(clusterCondition, namespace, cluster, values, func(conditionType string) prometheus.Labels {
+		return prometheus.Labels{"type": conditionType}
+	})
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func gatherSeries(t *testing.T, collector prometheus.Collector) []map[string]string {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather the metrics: %v", err)
+	}
+
+	series := []map[string]string{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			series = append(series, labels)
+		}
+	}
+	return series
+}
+
+func TestSetNodeUpgradePhasesDeletesRemovedNodes(t *testing.T) {
+	defer DeleteClusterMetrics("openshift-logging", "elasticsearch")
+
+	SetNodeUpgradePhases("openshift-logging", "elasticsearch", map[string]string{
+		"elasticsearch-cdm-abc-1": "nodeRestarting",
+		"elasticsearch-cdm-abc-2": "",
+	})
+	if series := gatherSeries(t, nodeUpgradePhase); len(series) != 2*len(UpgradePhases) {
+		t.Errorf("expected a series per phase of both nodes, got %v", series)
+	}
+
+	SetNodeUpgradePhases("openshift-logging", "elasticsearch", map[string]string{
+		"elasticsearch-cdm-abc-1": "recoveringData",
+	})
+	for _, labels := range gatherSeries(t, nodeUpgradePhase) {
+		if labels["node"] != "elasticsearch-cdm-abc-1" {
+			t.Errorf("expected the series of the removed node to be deleted, got %v", labels)
+		}
+	}
+}
+
+func TestDeleteClusterMetrics(t *testing.T) {
+	SetClusterConditions("openshift-logging", "elasticsearch", map[string]bool{"Restarting": true})
+	SetClusterConditions("other", "elasticsearch", map[string]bool{"Restarting": false})
+	defer DeleteClusterMetrics("other", "elasticsearch")
+
+	DeleteClusterMetrics("openshift-logging", "elasticsearch")
+
+	series := gatherSeries(t, clusterCondition)
+	if len(series) != 1 || series[0]["namespace"] != "other" {
+		t.Errorf("expected only the series of the remaining cluster, got %v", series)
+	}
+}
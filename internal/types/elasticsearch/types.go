@@ -62,6 +62,20 @@ type GetIndexTemplate struct {
 	Mappings      map[string]IndexMappingSettings `json:"mappings,omitempty"`
 }
 
+// RawIndexTemplate is an index template whose settings and mappings are passed as is
+type RawIndexTemplate struct {
+	Order         int32                  `json:"order"`
+	IndexPatterns []string               `json:"index_patterns"`
+	Settings      map[string]interface{} `json:"settings,omitempty"`
+	Mappings      map[string]interface{} `json:"mappings,omitempty"`
+}
+
+type IngestPipeline struct {
+	Description string                   `json:"description,omitempty"`
+	Processors  []map[string]interface{} `json:"processors"`
+	OnFailure   []map[string]interface{} `json:"on_failure,omitempty"`
+}
+
 type GetIndexTemplateSettings struct {
 	Index IndexTemplateSettings `json:"index,omitempty"`
 }
@@ -72,6 +86,8 @@ type IndexTemplateSettings struct {
 	RefreshInterval  string                 `json:"refresh_interval,omitempty"`
 	NumberOfShards   string                 `json:"number_of_shards,omitempty"`
 	NumberOfReplicas string                 `json:"number_of_replicas,omitempty"`
+	Routing          *IndexRoutingSettings  `json:"routing,omitempty"`
+	DefaultPipeline  string                 `json:"default_pipeline,omitempty"`
 }
 
 type UnassignedIndexSetting struct {
@@ -104,6 +120,16 @@ type IndexingSettings struct {
 	Blocks           *IndexBlocksSettings  `json:"blocks,omitempty"`
 	Mapper           *IndexMapperSettings  `json:"mapper,omitempty"`
 	Mapping          *IndexMappingSettings `json:"mapping,omitempty"`
+	Routing          *IndexRoutingSettings `json:"routing,omitempty"`
+	DefaultPipeline  string                `json:"default_pipeline,omitempty"`
+}
+
+type IndexRoutingSettings struct {
+	Allocation IndexRoutingAllocationSettings `json:"allocation"`
+}
+
+type IndexRoutingAllocationSettings struct {
+	Require map[string]string `json:"require,omitempty"`
 }
 
 type IndexBlocksSettings struct {
@@ -144,8 +170,9 @@ type AliasAction struct {
 }
 
 type AddAliasAction struct {
-	Index string `json:"index"`
-	Alias string `json:"alias"`
+	Index        string `json:"index"`
+	Alias        string `json:"alias"`
+	IsWriteIndex *bool  `json:"is_write_index,omitempty"`
 }
 
 type RemoveAliasAction struct {
@@ -192,3 +219,109 @@ type StatsNode struct {
 	Versions []string       `json:"versions,omitempty"`
 	Count    map[string]int `json:"count,omitempty"`
 }
+
+type SnapshotRepository struct {
+	Type     string            `json:"type"`
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+type SnapshotsResponse struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+type Snapshot struct {
+	Snapshot          string   `json:"snapshot"`
+	State             string   `json:"state,omitempty"`
+	Reason            string   `json:"reason,omitempty"`
+	Indices           []string `json:"indices,omitempty"`
+	StartTimeInMillis int64    `json:"start_time_in_millis,omitempty"`
+	EndTimeInMillis   int64    `json:"end_time_in_millis,omitempty"`
+}
+
+type SnapshotRequest struct {
+	Indices            string `json:"indices,omitempty"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+}
+
+type SnapshotResponse struct {
+	Snapshot Snapshot `json:"snapshot"`
+}
+
+type SnapshotRestoreRequest struct {
+	Indices            string `json:"indices,omitempty"`
+	IgnoreUnavailable  bool   `json:"ignore_unavailable"`
+	IncludeGlobalState bool   `json:"include_global_state"`
+	IncludeAliases     bool   `json:"include_aliases"`
+	RenamePattern      string `json:"rename_pattern,omitempty"`
+	RenameReplacement  string `json:"rename_replacement,omitempty"`
+}
+
+type IndexRecovery struct {
+	Shards []ShardRecovery `json:"shards"`
+}
+
+type ShardRecovery struct {
+	ID    int32  `json:"id"`
+	Type  string `json:"type"`
+	Stage string `json:"stage"`
+}
+
+type CatAllocationResponses []CatAllocationResponse
+
+type CatAllocationResponse struct {
+	Shards string `json:"shards,omitempty"`
+	Node   string `json:"node,omitempty"`
+}
+
+type CatShardsResponses []CatShardsResponse
+
+type CatShardsResponse struct {
+	Index  string `json:"index,omitempty"`
+	Shard  string `json:"shard,omitempty"`
+	Prirep string `json:"prirep,omitempty"`
+	State  string `json:"state,omitempty"`
+	Node   string `json:"node,omitempty"`
+}
+
+type RolloverRequest struct {
+	Conditions RolloverConditions `json:"conditions"`
+}
+
+type RolloverConditions struct {
+	MaxAge  string `json:"max_age,omitempty"`
+	MaxDocs int32  `json:"max_docs,omitempty"`
+	MaxSize string `json:"max_size,omitempty"`
+}
+
+type RolloverResponse struct {
+	Acknowledged bool            `json:"acknowledged"`
+	OldIndex     string          `json:"old_index"`
+	NewIndex     string          `json:"new_index"`
+	RolledOver   bool            `json:"rolled_over"`
+	Conditions   map[string]bool `json:"conditions,omitempty"`
+}
+
+type ShrinkIndexRequest struct {
+	Settings map[string]interface{} `json:"settings"`
+	Aliases  map[string]IndexAlias  `json:"aliases,omitempty"`
+}
+
+// SecurityRole is a role of the security plugin. The index permissions are keyed by index
+// pattern and document type
+type SecurityRole struct {
+	Reserved bool                           `json:"reserved,omitempty"`
+	Readonly bool                           `json:"readonly,omitempty"`
+	Hidden   bool                           `json:"hidden,omitempty"`
+	Cluster  []string                       `json:"cluster,omitempty"`
+	Indices  map[string]map[string][]string `json:"indices,omitempty"`
+}
+
+// SecurityRoleMapping maps users, backend roles and hosts to a role of the security plugin
+type SecurityRoleMapping struct {
+	Reserved     bool     `json:"reserved,omitempty"`
+	Readonly     bool     `json:"readonly,omitempty"`
+	Hidden       bool     `json:"hidden,omitempty"`
+	Users        []string `json:"users,omitempty"`
+	BackendRoles []string `json:"backendroles,omitempty"`
+	Hosts        []string `json:"hosts,omitempty"`
+}
@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+)
+
+const (
+	// ElasticsearchValidatorPath is the path the Elasticsearch validating webhook is served on
+	ElasticsearchValidatorPath = "/validate-logging-openshift-io-v1-elasticsearch"
+
+	// elasticsearchRequestTimeout bounds the time an admission waits for Elasticsearch, well
+	// below the timeout of the webhook
+	elasticsearchRequestTimeout = 3 * time.Second
+)
+
+// +kubebuilder:webhook:path=/validate-logging-openshift-io-v1-elasticsearch,mutating=false,failurePolicy=ignore,sideEffects=None,groups=logging.openshift.io,resources=elasticsearches,verbs=create;update,versions=v1,name=velasticsearch.logging.openshift.io
+
+// ElasticsearchValidator rejects changes to an Elasticsearch custom resource the
+// reconciler would otherwise refuse or silently ignore
+type ElasticsearchValidator struct {
+	// Client reads the secrets of the clusters for the requests to Elasticsearch
+	Client client.Client
+
+	decoder *admission.Decoder
+	// newESClient returns the client of the cluster, replaced by the tests
+	newESClient func(cluster, namespace string, k8sClient client.Client) elasticsearch.Client
+}
+
+// Handle validates the Elasticsearch in the admission request
+func (v *ElasticsearchValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	desired := &loggingv1.Elasticsearch{}
+	if err := v.decoder.Decode(req, desired); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var current *loggingv1.Elasticsearch
+	if req.Operation == admissionv1beta1.Update {
+		current = &loggingv1.Elasticsearch{}
+		if err := v.decoder.DecodeRaw(req.OldObject, current); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	// the writes of the operator, e.g. the status or the generated UUIDs, are never blocked
+	if current != nil && reflect.DeepEqual(current.Spec, desired.Spec) {
+		return admission.Allowed("")
+	}
+
+	// Elasticsearch is only queried when data nodes are scaled down. The admission is allowed
+	// when it can not be reached, the reconciler still refuses to scale down too quickly
+	reasons := k8shandler.ValidateElasticsearch(current, desired, v.esClient(desired))
+	if current != nil {
+		// the update is only denied for what it changes, not for the reasons the current
+		// custom resource was already invalid
+		existing := sets.NewString(k8shandler.ValidateElasticsearch(current, current, nil)...)
+		added := []string{}
+		for _, reason := range reasons {
+			if !existing.Has(reason) {
+				added = append(added, reason)
+			}
+		}
+		reasons = added
+	}
+
+	if len(reasons) > 0 {
+		return admission.Denied(strings.Join(reasons, "; "))
+	}
+	return admission.Allowed("")
+}
+
+func (v *ElasticsearchValidator) esClient(cluster *loggingv1.Elasticsearch) elasticsearch.Client {
+	if v.newESClient != nil {
+		return v.newESClient(cluster.Name, cluster.Namespace, v.Client)
+	}
+	esClient := elasticsearch.NewClient(cluster.Name, cluster.Namespace, v.Client)
+	esClient.SetSendRequestFn(elasticsearch.NewTimeoutSendRequestFn(elasticsearchRequestTimeout))
+	return esClient
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *ElasticsearchValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
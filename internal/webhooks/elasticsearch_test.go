@@ -0,0 +1,125 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
+	"github.com/openshift/elasticsearch-operator/internal/elasticsearch"
+	"github.com/openshift/elasticsearch-operator/test/helpers"
+)
+
+func newValidator(t *testing.T, replicasResponse helpers.FakeElasticsearchResponse) (*ElasticsearchValidator, *helpers.FakeElasticsearchChatter) {
+	scheme := runtime.NewScheme()
+	if err := loggingv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	chatter := helpers.NewFakeElasticsearchChatter(map[string]helpers.FakeElasticsearchResponses{
+		"app-*,infra-*,audit-*/_settings/index.number_of_replicas": {replicasResponse},
+	})
+	validator := &ElasticsearchValidator{
+		decoder: decoder,
+		newESClient: func(cluster, namespace string, k8sClient client.Client) elasticsearch.Client {
+			return helpers.NewFakeElasticsearchClient(cluster, namespace, k8sClient, chatter)
+		},
+	}
+	return validator, chatter
+}
+
+func newCluster(dataNodes int32) *loggingv1.Elasticsearch {
+	uuid := "abc123"
+	return &loggingv1.Elasticsearch{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "logging.openshift.io/v1", Kind: "Elasticsearch"},
+		ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch", Namespace: "openshift-logging"},
+		Spec: loggingv1.ElasticsearchSpec{
+			RedundancyPolicy: loggingv1.SingleRedundancy,
+			Nodes: []loggingv1.ElasticsearchNode{
+				{
+					Roles:     []loggingv1.ElasticsearchNodeRole{loggingv1.ElasticsearchRoleMaster, loggingv1.ElasticsearchRoleData, loggingv1.ElasticsearchRoleClient},
+					NodeCount: dataNodes,
+					GenUUID:   &uuid,
+				},
+			},
+		},
+	}
+}
+
+func newUpdateRequest(t *testing.T, current, desired *loggingv1.Elasticsearch) admission.Request {
+	oldObject, err := json.Marshal(current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	object, err := json.Marshal(desired)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+		Operation: admissionv1beta1.Update,
+		Object:    runtime.RawExtension{Raw: object},
+		OldObject: runtime.RawExtension{Raw: oldObject},
+	}}
+}
+
+func TestHandleDeniesAScaleDownFasterThanTheReplicas(t *testing.T) {
+	validator, _ := newValidator(t, helpers.FakeElasticsearchResponse{
+		StatusCode: 200,
+		Body:       `{"app-000001": {"settings": {"index": {"number_of_replicas": "1"}}}}`,
+	})
+
+	response := validator.Handle(context.TODO(), newUpdateRequest(t, newCluster(4), newCluster(2)))
+
+	if response.Allowed {
+		t.Fatal("Expected the scale down of 2 data nodes with 1 replica to be denied")
+	}
+	if want := "Data node scale down rate is too high based on minimum number of replicas for all indices"; response.Result.Reason != metav1.StatusReason(want) {
+		t.Errorf("Expected the reason %q, got %q", want, response.Result.Reason)
+	}
+}
+
+func TestHandleAllowsTheScaleDownWhenElasticsearchFails(t *testing.T) {
+	validator, _ := newValidator(t, helpers.FakeElasticsearchResponse{
+		StatusCode: 503,
+		Body:       `{"error": "unavailable", "status": 503}`,
+	})
+
+	response := validator.Handle(context.TODO(), newUpdateRequest(t, newCluster(4), newCluster(2)))
+
+	if !response.Allowed {
+		t.Errorf("Expected the scale down to be allowed when Elasticsearch fails, got %v", response.Result)
+	}
+}
+
+func TestHandleAllowsUpdatesOfAnInvalidCustomResource(t *testing.T) {
+	validator, chatter := newValidator(t, helpers.FakeElasticsearchResponse{StatusCode: 200, Body: `{}`})
+	current := newCluster(3)
+	current.Spec.RedundancyPolicy = loggingv1.FullRedundancy
+	current.Spec.Nodes[0].NodeCount = 1
+
+	unchanged := current.DeepCopy()
+	unchanged.Status.Conditions = []loggingv1.ClusterCondition{{Type: loggingv1.UpdatingSettings}}
+	if response := validator.Handle(context.TODO(), newUpdateRequest(t, current, unchanged)); !response.Allowed {
+		t.Errorf("Expected an update without spec changes to be allowed, got %v", response.Result)
+	}
+
+	labeled := current.DeepCopy()
+	labeled.Spec.Nodes[0].NodeSelector = map[string]string{"zone": "a"}
+	if response := validator.Handle(context.TODO(), newUpdateRequest(t, current, labeled)); !response.Allowed {
+		t.Errorf("Expected an update which keeps the existing reasons to be allowed, got %v", response.Result)
+	}
+
+	if len(chatter.Requests) != 0 {
+		t.Errorf("Expected Elasticsearch not to be queried without a scale down, got %v", chatter.Requests)
+	}
+}
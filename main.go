@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/openshift/elasticsearch-operator/internal/metrics"
 
@@ -13,6 +14,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	// "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
@@ -24,6 +26,9 @@ import (
 	"github.com/ViaQ/logerr/log"
 	loggingv1 "github.com/openshift/elasticsearch-operator/apis/logging/v1"
 	controllers "github.com/openshift/elasticsearch-operator/controllers/logging"
+	"github.com/openshift/elasticsearch-operator/internal/indexmanagement/job"
+	"github.com/openshift/elasticsearch-operator/internal/k8shandler"
+	"github.com/openshift/elasticsearch-operator/internal/webhooks"
 	"github.com/openshift/elasticsearch-operator/version"
 	// +kubebuilder:scaffold:imports
 )
@@ -49,10 +54,18 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == job.Command {
+		os.Exit(job.Main(os.Args[2:]))
+	}
+
 	var enableLeaderElection bool
+	var healthCheckInterval time.Duration
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&healthCheckInterval, "health-check-interval", controllers.DefaultHealthCheckInterval,
+		"How often the health of idle Elasticsearch clusters is checked. "+
+			"Changes of the resources of the clusters are reconciled as they happen.")
 	flag.Parse()
 
 	log.MustInit("elasticsearch-operator")
@@ -79,24 +92,36 @@ func main() {
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "d471c3b1.openshift.io",
 		Logger:             ll,
+		NewClient:          controllers.NewClient,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	podExecutor, err := k8shandler.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create the pod executor")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.ElasticsearchReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Elasticsearch"),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("Elasticsearch"),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("elasticsearch-controller"),
+		PodExecutor:         podExecutor,
+		HealthCheckInterval: healthCheckInterval,
+		Namespace:           namespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Elasticsearch")
 		os.Exit(1)
 	}
 	if err = (&controllers.KibanaReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Kibana"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Kibana"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("kibana-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Kibana")
 		os.Exit(1)
@@ -109,6 +134,12 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Secret")
 		os.Exit(1)
 	}
+	// The serving certificate of the webhook is provisioned by OLM from the webhook definition of the CSV
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		mgr.GetWebhookServer().Register(webhooks.ElasticsearchValidatorPath, &webhook.Admission{
+			Handler: &webhooks.ElasticsearchValidator{Client: mgr.GetClient()},
+		})
+	}
 	// +kubebuilder:scaffold:builder
 
 	log.Info("Registering custom metrics for Elasticsearch Operator.")
@@ -34,10 +34,11 @@ type FakeElasticsearchChatter struct {
 type FakeElasticsearchRequests []FakeElasticsearchRequest
 
 type FakeElasticsearchRequest struct {
-	URI    string
-	Method string
-	Body   string
-	SeqNo  int
+	URI     string
+	Method  string
+	Body    string
+	Address string
+	SeqNo   int
 }
 
 type FakeElasticsearchResponses []FakeElasticsearchResponse
@@ -73,10 +74,11 @@ func (chat *FakeElasticsearchChatter) GetResponse(key string) (*FakeElasticsearc
 func (chat *FakeElasticsearchChatter) recordRequest(payload *elasticsearch.EsRequest) {
 	key := payload.URI
 	req := FakeElasticsearchRequest{
-		URI:    key,
-		Body:   payload.RequestBody,
-		Method: payload.Method,
-		SeqNo:  chat.nextSeqNo(),
+		URI:     key,
+		Body:    payload.RequestBody,
+		Method:  payload.Method,
+		Address: payload.Address,
+		SeqNo:   chat.nextSeqNo(),
 	}
 	chat.Requests[key] = append(chat.Requests[key], req)
 }